@@ -1,27 +1,52 @@
 package apifootball
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"time"
+
+	"github.com/nero-15/calcio-app/logging"
+	"github.com/nero-15/calcio-app/metrics"
+	"github.com/sirupsen/logrus"
 )
 
 type APIClient struct {
 	token      string
 	baseUrl    string
 	httpClient *http.Client
+	ctx        context.Context
+	decodeMode DecodeMode
 }
 
 func New(token string, baseUrl string) *APIClient {
-	apiClient := &APIClient{token, baseUrl, &http.Client{}}
+	return NewWithHTTPClient(token, baseUrl, &http.Client{Timeout: 10 * time.Second})
+}
+
+// NewWithHTTPClient lets callers swap the transport, e.g. to replay recorded responses in tests.
+func NewWithHTTPClient(token string, baseUrl string, httpClient *http.Client) *APIClient {
+	apiClient := &APIClient{token, baseUrl, httpClient, context.Background(), Lenient}
 	return apiClient
 }
 
+// WithContext returns a copy of the client whose requests use ctx, so they
+// are cancelled with the incoming request and logged with its request id.
+func (api *APIClient) WithContext(ctx context.Context) *APIClient {
+	apiClient := *api
+	apiClient.ctx = ctx
+	return &apiClient
+}
+
 func (api *APIClient) doRequest(urlPath string, query map[string]string) (body []byte, err error) {
-	url, _ := url.Parse(api.baseUrl)
+	url, err := url.Parse(api.baseUrl)
+	if err != nil {
+		return nil, err
+	}
 	url.Path = path.Join(url.Path, urlPath)
 
 	queryParams := url.Query()
@@ -30,16 +55,64 @@ func (api *APIClient) doRequest(urlPath string, query map[string]string) (body [
 	}
 	url.RawQuery = queryParams.Encode()
 
-	req, _ := http.NewRequest("GET", url.String(), nil)
+	req, err := http.NewRequestWithContext(api.ctx, "GET", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Add("x-apisports-key", api.token)
-	client := new(http.Client)
-	resp, _ := client.Do(req)
+	start := time.Now()
+	resp, err := api.httpClient.Do(req)
+	if err != nil {
+		logRequest(api.ctx, urlPath, url.RawQuery, 0, time.Since(start), err)
+		return nil, err
+	}
 	defer resp.Body.Close()
+	logRequest(api.ctx, urlPath, url.RawQuery, resp.StatusCode, time.Since(start), nil)
+	observeQuota(resp.Header)
 
-	byteArray, _ := ioutil.ReadAll(resp.Body)
+	byteArray, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return byteArray, fmt.Errorf("apifootball: %s returned %s", urlPath, resp.Status)
+	}
 	return byteArray, nil
 }
 
+// logRequest logs and records metrics for one request to the provider.
+func logRequest(ctx context.Context, urlPath string, query string, status int, duration time.Duration, err error) {
+	metrics.ObserveUpstream("apifootball", urlPath, status, duration)
+	entry := logging.FromContext(ctx).WithFields(logrus.Fields{
+		"provider":    "apifootball",
+		"endpoint":    urlPath,
+		"query":       query,
+		"status":      status,
+		"duration_ms": float64(duration.Microseconds()) / 1000,
+	})
+	switch {
+	case err != nil:
+		entry.WithError(err).Error("upstream request")
+	case status != http.StatusOK:
+		entry.Warn("upstream request")
+	default:
+		entry.Info("upstream request")
+	}
+}
+
+// observeQuota reads the rate limit headers sent with every response.
+func observeQuota(header http.Header) {
+	limit, err := strconv.Atoi(header.Get("x-ratelimit-requests-limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(header.Get("x-ratelimit-requests-remaining"))
+	if err != nil {
+		return
+	}
+	metrics.SetApiFootballQuota(limit-remaining, limit)
+}
+
 type Coach struct {
 	ID        int    `json:"id"`
 	Name      string `json:"name"`
@@ -47,14 +120,14 @@ type Coach struct {
 	Lastname  string `json:"lastname"`
 	Age       int    `json:"age"`
 	Birth     struct {
-		Date    string      `json:"date"`
-		Place   interface{} `json:"place"`
-		Country string      `json:"country"`
+		Date    string     `json:"date"`
+		Place   NullString `json:"place"`
+		Country string     `json:"country"`
 	} `json:"birth"`
-	Nationality string      `json:"nationality"`
-	Height      interface{} `json:"height"`
-	Weight      interface{} `json:"weight"`
-	Photo       string      `json:"photo"`
+	Nationality string     `json:"nationality"`
+	Height      NullString `json:"height"`
+	Weight      NullString `json:"weight"`
+	Photo       string     `json:"photo"`
 	Team        struct {
 		ID   int    `json:"id"`
 		Name string `json:"name"`
@@ -77,16 +150,36 @@ type Coachs struct {
 }
 
 type CommonResponse struct {
-	Get        string        `json:"get"`
-	Parameters []interface{} `json:"parameters"`
-	Errors     []interface{} `json:"errors"`
-	Results    int           `json:"results"`
+	Get string `json:"get"`
+	// Parameters and Errors are kept as sent: an object by parameter name,
+	// or an empty array when there is nothing to report.
+	Parameters json.RawMessage `json:"parameters"`
+	Errors     json.RawMessage `json:"errors"`
+	Results    int             `json:"results"`
 	Paging     struct {
 		Current int `json:"current"`
 		Total   int `json:"total"`
 	} `json:"paging"`
 }
 
+// ErrorMessages reads Errors as messages by parameter name, or by index when
+// the API sends a list. It is empty when the request succeeded.
+func (r CommonResponse) ErrorMessages() map[string]string {
+	messages := map[string]string{}
+	var object map[string]interface{}
+	var list []interface{}
+	if json.Unmarshal(r.Errors, &object) == nil {
+		for key, value := range object {
+			messages[key] = fmt.Sprint(value)
+		}
+	} else if json.Unmarshal(r.Errors, &list) == nil {
+		for i, value := range list {
+			messages[strconv.Itoa(i)] = fmt.Sprint(value)
+		}
+	}
+	return messages
+}
+
 type Country struct {
 	Name string `json:"name"`
 	Code string `json:"code"`
@@ -97,8 +190,8 @@ type Events struct {
 	CommonResponse
 	Response []struct {
 		Time struct {
-			Elapsed int         `json:"elapsed"`
-			Extra   interface{} `json:"extra"`
+			Elapsed int     `json:"elapsed"`
+			Extra   NullInt `json:"extra"`
 		} `json:"time"`
 		Team struct {
 			ID   int    `json:"id"`
@@ -110,77 +203,72 @@ type Events struct {
 			Name string `json:"name"`
 		} `json:"player"`
 		Assist struct {
-			ID   interface{} `json:"id"`
-			Name interface{} `json:"name"`
+			ID   NullInt    `json:"id"`
+			Name NullString `json:"name"`
 		} `json:"assist"`
-		Type     string      `json:"type"`
-		Detail   string      `json:"detail"`
-		Comments interface{} `json:"comments"`
+		Type     string     `json:"type"`
+		Detail   string     `json:"detail"`
+		Comments NullString `json:"comments"`
 	} `json:"response"`
 }
 
 type Fixtures struct {
 	CommonResponse
-	Response []struct {
-		Fixture struct {
-			ID        int       `json:"id"`
-			Referee   string    `json:"referee"`
-			Timezone  string    `json:"timezone"`
-			Date      time.Time `json:"date"`
-			Timestamp int       `json:"timestamp"`
-			Periods   struct {
-				First  int `json:"first"`
-				Second int `json:"second"`
-			} `json:"periods"`
-			Venue struct {
-				ID   int    `json:"id"`
-				Name string `json:"name"`
-				City string `json:"city"`
-			} `json:"venue"`
-			Status struct {
-				Long    string `json:"long"`
-				Short   string `json:"short"`
-				Elapsed int    `json:"elapsed"`
-			} `json:"status"`
-		} `json:"fixture"`
-		League `json:"league"`
-		Teams  struct {
-			Home struct {
-				ID     int    `json:"id"`
-				Name   string `json:"name"`
-				Logo   string `json:"logo"`
-				Winner bool   `json:"winner"`
-			} `json:"home"`
-			Away struct {
-				ID     int    `json:"id"`
-				Name   string `json:"name"`
-				Logo   string `json:"logo"`
-				Winner bool   `json:"winner"`
-			} `json:"away"`
-		} `json:"teams"`
-		Goals struct {
-			Home int `json:"home"`
-			Away int `json:"away"`
-		} `json:"goals"`
-		Score struct {
-			Halftime struct {
-				Home int `json:"home"`
-				Away int `json:"away"`
-			} `json:"halftime"`
-			Fulltime struct {
-				Home int `json:"home"`
-				Away int `json:"away"`
-			} `json:"fulltime"`
-			Extratime struct {
-				Home interface{} `json:"home"`
-				Away interface{} `json:"away"`
-			} `json:"extratime"`
-			Penalty struct {
-				Home interface{} `json:"home"`
-				Away interface{} `json:"away"`
-			} `json:"penalty"`
-		} `json:"score"`
-	} `json:"response"`
+	Response []Fixture `json:"response"`
+}
+
+// Fixture is one match. Goals and scores are null until the match is played,
+// so a match that has not started is not mistaken for a 0-0.
+type Fixture struct {
+	Fixture struct {
+		ID        int        `json:"id"`
+		Referee   NullString `json:"referee"`
+		Timezone  string     `json:"timezone"`
+		Date      time.Time  `json:"date"`
+		Timestamp int        `json:"timestamp"`
+		Periods   struct {
+			First  NullInt `json:"first"`
+			Second NullInt `json:"second"`
+		} `json:"periods"`
+		Venue struct {
+			ID   NullInt    `json:"id"`
+			Name NullString `json:"name"`
+			City NullString `json:"city"`
+		} `json:"venue"`
+		Status struct {
+			Long    string  `json:"long"`
+			Short   string  `json:"short"`
+			Elapsed NullInt `json:"elapsed"`
+		} `json:"status"`
+	} `json:"fixture"`
+	League `json:"league"`
+	Teams  struct {
+		Home struct {
+			ID     int    `json:"id"`
+			Name   string `json:"name"`
+			Logo   string `json:"logo"`
+			Winner bool   `json:"winner"`
+		} `json:"home"`
+		Away struct {
+			ID     int    `json:"id"`
+			Name   string `json:"name"`
+			Logo   string `json:"logo"`
+			Winner bool   `json:"winner"`
+		} `json:"away"`
+	} `json:"teams"`
+	Goals Score `json:"goals"`
+	Score struct {
+		Halftime  Score `json:"halftime"`
+		Fulltime  Score `json:"fulltime"`
+		Extratime Score `json:"extratime"`
+		Penalty   Score `json:"penalty"`
+	} `json:"score"`
+}
+
+// Score is a home and away pair of goals, null when not (yet) played.
+type Score struct {
+	Home NullInt `json:"home"`
+	Away NullInt `json:"away"`
 }
 
 type FixturesPlayers struct {
@@ -200,57 +288,57 @@ type FixturesPlayers struct {
 			} `json:"player"`
 			Statistics []struct {
 				Games struct {
-					Minutes    int    `json:"minutes"`
-					Number     int    `json:"number"`
-					Position   string `json:"position"`
-					Rating     string `json:"rating"`
-					Captain    bool   `json:"captain"`
-					Substitute bool   `json:"substitute"`
+					Minutes    NullInt   `json:"minutes"`
+					Number     int       `json:"number"`
+					Position   string    `json:"position"`
+					Rating     NullFloat `json:"rating"`
+					Captain    bool      `json:"captain"`
+					Substitute bool      `json:"substitute"`
 				} `json:"games"`
-				Offsides interface{} `json:"offsides"`
+				Offsides NullInt `json:"offsides"`
 				Shots    struct {
-					Total interface{} `json:"total"`
-					On    interface{} `json:"on"`
+					Total NullInt `json:"total"`
+					On    NullInt `json:"on"`
 				} `json:"shots"`
 				Goals struct {
-					Total    interface{} `json:"total"`
-					Conceded int         `json:"conceded"`
-					Assists  interface{} `json:"assists"`
-					Saves    interface{} `json:"saves"`
+					Total    NullInt `json:"total"`
+					Conceded int     `json:"conceded"`
+					Assists  NullInt `json:"assists"`
+					Saves    NullInt `json:"saves"`
 				} `json:"goals"`
 				Passes struct {
-					Total    int         `json:"total"`
-					Key      interface{} `json:"key"`
-					Accuracy string      `json:"accuracy"`
+					Total    NullInt    `json:"total"`
+					Key      NullInt    `json:"key"`
+					Accuracy NullString `json:"accuracy"`
 				} `json:"passes"`
 				Tackles struct {
-					Total         interface{} `json:"total"`
-					Blocks        interface{} `json:"blocks"`
-					Interceptions interface{} `json:"interceptions"`
+					Total         NullInt `json:"total"`
+					Blocks        NullInt `json:"blocks"`
+					Interceptions NullInt `json:"interceptions"`
 				} `json:"tackles"`
 				Duels struct {
-					Total interface{} `json:"total"`
-					Won   interface{} `json:"won"`
+					Total NullInt `json:"total"`
+					Won   NullInt `json:"won"`
 				} `json:"duels"`
 				Dribbles struct {
-					Attempts interface{} `json:"attempts"`
-					Success  interface{} `json:"success"`
-					Past     interface{} `json:"past"`
+					Attempts NullInt `json:"attempts"`
+					Success  NullInt `json:"success"`
+					Past     NullInt `json:"past"`
 				} `json:"dribbles"`
 				Fouls struct {
-					Drawn     interface{} `json:"drawn"`
-					Committed interface{} `json:"committed"`
+					Drawn     NullInt `json:"drawn"`
+					Committed NullInt `json:"committed"`
 				} `json:"fouls"`
 				Cards struct {
 					Yellow int `json:"yellow"`
 					Red    int `json:"red"`
 				} `json:"cards"`
 				Penalty struct {
-					Won      interface{} `json:"won"`
-					Commited interface{} `json:"commited"`
-					Scored   int         `json:"scored"`
-					Missed   int         `json:"missed"`
-					Saved    int         `json:"saved"`
+					Won      NullInt `json:"won"`
+					Commited NullInt `json:"commited"`
+					Scored   int     `json:"scored"`
+					Missed   int     `json:"missed"`
+					Saved    int     `json:"saved"`
 				} `json:"penalty"`
 			} `json:"statistics"`
 		} `json:"players"`
@@ -266,8 +354,8 @@ type FixturesStatistics struct {
 			Logo string `json:"logo"`
 		} `json:"team"`
 		Statistics []struct {
-			Type  string `json:"type"`
-			Value int    `json:"value"`
+			Type  string         `json:"type"`
+			Value StatisticValue `json:"value"`
 		} `json:"statistics"`
 	} `json:"response"`
 }
@@ -275,7 +363,11 @@ type FixturesStatistics struct {
 type Injuries struct {
 	CommonResponse
 	Response []struct {
-		Player  `json:"player"`
+		Player struct {
+			Player
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"player"`
 		Team    `json:"team"`
 		Fixture struct {
 			ID        int       `json:"id"`
@@ -324,11 +416,11 @@ type Lineups struct {
 		} `json:"startXI"`
 		Substitutes []struct {
 			Player struct {
-				ID     int         `json:"id"`
-				Name   string      `json:"name"`
-				Number int         `json:"number"`
-				Pos    string      `json:"pos"`
-				Grid   interface{} `json:"grid"`
+				ID     int        `json:"id"`
+				Name   string     `json:"name"`
+				Number int        `json:"number"`
+				Pos    string     `json:"pos"`
+				Grid   NullString `json:"grid"`
 			} `json:"player"`
 		} `json:"substitutes"`
 	} `json:"response"`
@@ -342,6 +434,7 @@ type League struct {
 	Flag    string `json:"flag"`
 	Season  int    `json:"season"`
 	Round   string `json:"round"`
+	Type    string `json:"type"`
 }
 
 type Leagues struct {
@@ -353,39 +446,22 @@ type Leagues struct {
 	} `json:"response"`
 }
 
+// Minute splits goals or cards into 15 minute periods of play.
 type Minute struct {
-	Zero15 struct {
-		Total      int    `json:"total"`
-		Percentage string `json:"percentage"`
-	} `json:"0-15"`
-	One630 struct {
-		Total      int    `json:"total"`
-		Percentage string `json:"percentage"`
-	} `json:"16-30"`
-	Three145 struct {
-		Total      int    `json:"total"`
-		Percentage string `json:"percentage"`
-	} `json:"31-45"`
-	Four660 struct {
-		Total      int    `json:"total"`
-		Percentage string `json:"percentage"`
-	} `json:"46-60"`
-	Six175 struct {
-		Total      int    `json:"total"`
-		Percentage string `json:"percentage"`
-	} `json:"61-75"`
-	Seven690 struct {
-		Total      int    `json:"total"`
-		Percentage string `json:"percentage"`
-	} `json:"76-90"`
-	Nine1105 struct {
-		Total      int    `json:"total"`
-		Percentage string `json:"percentage"`
-	} `json:"91-105"`
-	One06120 struct {
-		Total      interface{} `json:"total"`
-		Percentage interface{} `json:"percentage"`
-	} `json:"106-120"`
+	Zero15   MinuteBucket `json:"0-15"`
+	One630   MinuteBucket `json:"16-30"`
+	Three145 MinuteBucket `json:"31-45"`
+	Four660  MinuteBucket `json:"46-60"`
+	Six175   MinuteBucket `json:"61-75"`
+	Seven690 MinuteBucket `json:"76-90"`
+	Nine1105 MinuteBucket `json:"91-105"`
+	One06120 MinuteBucket `json:"106-120"`
+}
+
+// MinuteBucket is null in both fields when nothing happened in the period.
+type MinuteBucket struct {
+	Total      NullInt    `json:"total"`
+	Percentage Percentage `json:"percentage"`
 }
 
 type Player struct {
@@ -461,61 +537,47 @@ type Standings struct {
 	CommonResponse
 	Response []struct {
 		League struct {
-			ID        int    `json:"id"`
-			Name      string `json:"name"`
-			Country   string `json:"country"`
-			Logo      string `json:"logo"`
-			Flag      string `json:"flag"`
-			Season    int    `json:"season"`
-			Standings [][]struct {
-				Rank int `json:"rank"`
-				Team struct {
-					ID   int    `json:"id"`
-					Name string `json:"name"`
-					Logo string `json:"logo"`
-				} `json:"team"`
-				Points      int    `json:"points"`
-				Goalsdiff   int    `json:"goalsDiff"`
-				Group       string `json:"group"`
-				Form        string `json:"form"`
-				Status      string `json:"status"`
-				Description string `json:"description"`
-				All         struct {
-					Played int `json:"played"`
-					Win    int `json:"win"`
-					Draw   int `json:"draw"`
-					Lose   int `json:"lose"`
-					Goals  struct {
-						For     int `json:"for"`
-						Against int `json:"against"`
-					} `json:"goals"`
-				} `json:"all"`
-				Home struct {
-					Played int `json:"played"`
-					Win    int `json:"win"`
-					Draw   int `json:"draw"`
-					Lose   int `json:"lose"`
-					Goals  struct {
-						For     int `json:"for"`
-						Against int `json:"against"`
-					} `json:"goals"`
-				} `json:"home"`
-				Away struct {
-					Played int `json:"played"`
-					Win    int `json:"win"`
-					Draw   int `json:"draw"`
-					Lose   int `json:"lose"`
-					Goals  struct {
-						For     int `json:"for"`
-						Against int `json:"against"`
-					} `json:"goals"`
-				} `json:"away"`
-				Update time.Time `json:"update"`
-			} `json:"standings"`
+			ID        int              `json:"id"`
+			Name      string           `json:"name"`
+			Country   string           `json:"country"`
+			Logo      string           `json:"logo"`
+			Flag      string           `json:"flag"`
+			Season    int              `json:"season"`
+			Standings [][]StandingsRow `json:"standings"`
 		} `json:"league"`
 	} `json:"response"`
 }
 
+type StandingsRow struct {
+	Rank int `json:"rank"`
+	Team struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Logo string `json:"logo"`
+	} `json:"team"`
+	Points      int             `json:"points"`
+	Goalsdiff   int             `json:"goalsDiff"`
+	Group       string          `json:"group"`
+	Form        string          `json:"form"`
+	Status      string          `json:"status"`
+	Description string          `json:"description"`
+	All         StandingsRecord `json:"all"`
+	Home        StandingsRecord `json:"home"`
+	Away        StandingsRecord `json:"away"`
+	Update      time.Time       `json:"update"`
+}
+
+type StandingsRecord struct {
+	Played int `json:"played"`
+	Win    int `json:"win"`
+	Draw   int `json:"draw"`
+	Lose   int `json:"lose"`
+	Goals  struct {
+		For     int `json:"for"`
+		Against int `json:"against"`
+	} `json:"goals"`
+}
+
 type Squads struct {
 	CommonResponse
 	Response []struct {
@@ -539,13 +601,13 @@ type Statistic struct {
 	Team   `json:"team"`
 	League `json:"league"`
 	Games  struct {
-		Appearences int         `json:"appearences"`
-		Lineups     int         `json:"lineups"`
-		Minutes     int         `json:"minutes"`
-		Number      interface{} `json:"number"`
-		Position    string      `json:"position"`
-		Rating      string      `json:"rating"`
-		Captain     bool        `json:"captain"`
+		Appearences NullInt   `json:"appearences"`
+		Lineups     NullInt   `json:"lineups"`
+		Minutes     NullInt   `json:"minutes"`
+		Number      NullInt   `json:"number"`
+		Position    string    `json:"position"`
+		Rating      NullFloat `json:"rating"`
+		Captain     bool      `json:"captain"`
 	} `json:"games"`
 	Substitutes struct {
 		In    int `json:"in"`
@@ -553,37 +615,37 @@ type Statistic struct {
 		Bench int `json:"bench"`
 	} `json:"substitutes"`
 	Shots struct {
-		Total int `json:"total"`
-		On    int `json:"on"`
+		Total NullInt `json:"total"`
+		On    NullInt `json:"on"`
 	} `json:"shots"`
 	Goals struct {
-		Total    int         `json:"total"`
-		Conceded int         `json:"conceded"`
-		Assists  int         `json:"assists"`
-		Saves    interface{} `json:"saves"`
+		Total    NullInt `json:"total"`
+		Conceded NullInt `json:"conceded"`
+		Assists  NullInt `json:"assists"`
+		Saves    NullInt `json:"saves"`
 	} `json:"goals"`
 	Passes struct {
-		Total    int `json:"total"`
-		Key      int `json:"key"`
-		Accuracy int `json:"accuracy"`
+		Total    NullInt `json:"total"`
+		Key      NullInt `json:"key"`
+		Accuracy NullInt `json:"accuracy"`
 	} `json:"passes"`
 	Tackles struct {
-		Total         int         `json:"total"`
-		Blocks        interface{} `json:"blocks"`
-		Interceptions int         `json:"interceptions"`
+		Total         NullInt `json:"total"`
+		Blocks        NullInt `json:"blocks"`
+		Interceptions NullInt `json:"interceptions"`
 	} `json:"tackles"`
 	Duels struct {
-		Total int `json:"total"`
-		Won   int `json:"won"`
+		Total NullInt `json:"total"`
+		Won   NullInt `json:"won"`
 	} `json:"duels"`
 	Dribbles struct {
-		Attempts int         `json:"attempts"`
-		Success  int         `json:"success"`
-		Past     interface{} `json:"past"`
+		Attempts NullInt `json:"attempts"`
+		Success  NullInt `json:"success"`
+		Past     NullInt `json:"past"`
 	} `json:"dribbles"`
 	Fouls struct {
-		Drawn     int `json:"drawn"`
-		Committed int `json:"committed"`
+		Drawn     NullInt `json:"drawn"`
+		Committed NullInt `json:"committed"`
 	} `json:"fouls"`
 	Cards struct {
 		Yellow    int `json:"yellow"`
@@ -591,125 +653,99 @@ type Statistic struct {
 		Red       int `json:"red"`
 	} `json:"cards"`
 	Penalty struct {
-		Won      interface{} `json:"won"`
-		Commited interface{} `json:"commited"`
-		Scored   int         `json:"scored"`
-		Missed   int         `json:"missed"`
-		Saved    interface{} `json:"saved"`
+		Won      NullInt `json:"won"`
+		Commited NullInt `json:"commited"`
+		Scored   NullInt `json:"scored"`
+		Missed   NullInt `json:"missed"`
+		Saved    NullInt `json:"saved"`
 	} `json:"penalty"`
 }
 
 type Statistics struct {
 	CommonResponse
 	Response struct {
-		League   `json:"league"`
-		Team     `json:"team"`
-		Form     string `json:"form"`
-		Fixtures struct {
-			Played struct {
-				Home  int `json:"home"`
-				Away  int `json:"away"`
-				Total int `json:"total"`
-			} `json:"played"`
-			Wins struct {
-				Home  int `json:"home"`
-				Away  int `json:"away"`
-				Total int `json:"total"`
-			} `json:"wins"`
-			Draws struct {
-				Home  int `json:"home"`
-				Away  int `json:"away"`
-				Total int `json:"total"`
-			} `json:"draws"`
-			Loses struct {
-				Home  int `json:"home"`
-				Away  int `json:"away"`
-				Total int `json:"total"`
-			} `json:"loses"`
-		} `json:"fixtures"`
+		League `json:"league"`
+		Team   `json:"team"`
+		TeamSeason
+	} `json:"response"`
+}
+
+// TeamSeason is a team's record in one league season, as sent by
+// teams/statistics and for both teams of a prediction.
+type TeamSeason struct {
+	Form     string `json:"form"`
+	Fixtures struct {
+		Played HomeAway `json:"played"`
+		Wins   HomeAway `json:"wins"`
+		Draws  HomeAway `json:"draws"`
+		Loses  HomeAway `json:"loses"`
+	} `json:"fixtures"`
+	Goals struct {
+		For     SeasonGoals `json:"for"`
+		Against SeasonGoals `json:"against"`
+	} `json:"goals"`
+	Biggest struct {
+		Streak struct {
+			Wins  int `json:"wins"`
+			Draws int `json:"draws"`
+			Loses int `json:"loses"`
+		} `json:"streak"`
+		Wins struct {
+			Home NullString `json:"home"`
+			Away NullString `json:"away"`
+		} `json:"wins"`
+		Loses struct {
+			Home NullString `json:"home"`
+			Away NullString `json:"away"`
+		} `json:"loses"`
 		Goals struct {
 			For struct {
-				Total struct {
-					Home  int `json:"home"`
-					Away  int `json:"away"`
-					Total int `json:"total"`
-				} `json:"total"`
-				Average struct {
-					Home  string `json:"home"`
-					Away  string `json:"away"`
-					Total string `json:"total"`
-				} `json:"average"`
-				Minute `json:"minute"`
+				Home int `json:"home"`
+				Away int `json:"away"`
 			} `json:"for"`
 			Against struct {
-				Total struct {
-					Home  int `json:"home"`
-					Away  int `json:"away"`
-					Total int `json:"total"`
-				} `json:"total"`
-				Average struct {
-					Home  string `json:"home"`
-					Away  string `json:"away"`
-					Total string `json:"total"`
-				} `json:"average"`
-				Minute `json:"minute"`
+				Home int `json:"home"`
+				Away int `json:"away"`
 			} `json:"against"`
 		} `json:"goals"`
-		Biggest struct {
-			Streak struct {
-				Wins  int `json:"wins"`
-				Draws int `json:"draws"`
-				Loses int `json:"loses"`
-			} `json:"streak"`
-			Wins struct {
-				Home string `json:"home"`
-				Away string `json:"away"`
-			} `json:"wins"`
-			Loses struct {
-				Home string `json:"home"`
-				Away string `json:"away"`
-			} `json:"loses"`
-			Goals struct {
-				For struct {
-					Home int `json:"home"`
-					Away int `json:"away"`
-				} `json:"for"`
-				Against struct {
-					Home int `json:"home"`
-					Away int `json:"away"`
-				} `json:"against"`
-			} `json:"goals"`
-		} `json:"biggest"`
-		CleanSheet struct {
-			Home  int `json:"home"`
-			Away  int `json:"away"`
-			Total int `json:"total"`
-		} `json:"clean_sheet"`
-		FailedToScore struct {
-			Home  int `json:"home"`
-			Away  int `json:"away"`
-			Total int `json:"total"`
-		} `json:"failed_to_score"`
-		Penalty struct {
-			Scored struct {
-				Total      int    `json:"total"`
-				Percentage string `json:"percentage"`
-			} `json:"scored"`
-			Missed struct {
-				Total      int    `json:"total"`
-				Percentage string `json:"percentage"`
-			} `json:"missed"`
-			Total int `json:"total"`
-		} `json:"penalty"`
-		Lineups []struct {
-			Formation string `json:"formation"`
-			Played    int    `json:"played"`
-		} `json:"lineups"`
-		Cards struct {
-			Yellow Minute `json:"yellow"`
-			Red    Minute `json:"red"`
-		} `json:"cards"`
-	} `json:"response"`
+	} `json:"biggest"`
+	CleanSheet    HomeAway `json:"clean_sheet"`
+	FailedToScore HomeAway `json:"failed_to_score"`
+	Penalty       struct {
+		Scored struct {
+			Total      int        `json:"total"`
+			Percentage Percentage `json:"percentage"`
+		} `json:"scored"`
+		Missed struct {
+			Total      int        `json:"total"`
+			Percentage Percentage `json:"percentage"`
+		} `json:"missed"`
+		Total int `json:"total"`
+	} `json:"penalty"`
+	Lineups []struct {
+		Formation string `json:"formation"`
+		Played    int    `json:"played"`
+	} `json:"lineups"`
+	Cards struct {
+		Yellow Minute `json:"yellow"`
+		Red    Minute `json:"red"`
+	} `json:"cards"`
+}
+
+type HomeAway struct {
+	Home  int `json:"home"`
+	Away  int `json:"away"`
+	Total int `json:"total"`
+}
+
+type SeasonGoals struct {
+	Total   HomeAway `json:"total"`
+	Average struct {
+		Home  NullFloat `json:"home"`
+		Away  NullFloat `json:"away"`
+		Total NullFloat `json:"total"`
+	} `json:"average"`
+	Minute `json:"minute"`
 }
 
 type Topscorers struct {
@@ -731,7 +767,7 @@ type Topassists struct {
 type Topyellowcards struct {
 	CommonResponse
 	Response []struct {
-		Player
+		Player     `json:"player"`
 		Statistics []Statistic `json:"statistics"`
 	} `json:"response"`
 }
@@ -739,7 +775,7 @@ type Topyellowcards struct {
 type Topredcards struct {
 	CommonResponse
 	Response []struct {
-		Player
+		Player     `json:"player"`
 		Statistics []Statistic `json:"statistics"`
 	} `json:"response"`
 }
@@ -829,7 +865,12 @@ func (api *APIClient) GetStatus() (Status, error) {
 	if err != nil {
 		return status, err
 	}
-	json.Unmarshal(resp, &status)
+	if err := api.decode("status", resp, &status); err != nil {
+		return status, err
+	}
+	if status.Response.Requests.LimitDay > 0 {
+		metrics.SetApiFootballQuota(status.Response.Requests.Current, status.Response.Requests.LimitDay)
+	}
 	return status, nil
 }
 
@@ -842,7 +883,9 @@ func (api *APIClient) GetLeagues() (Leagues, error) {
 	if err != nil {
 		return leagues, err
 	}
-	json.Unmarshal(resp, &leagues)
+	if err := api.decode("leagues", resp, &leagues); err != nil {
+		return leagues, err
+	}
 	return leagues, nil
 }
 
@@ -856,7 +899,9 @@ func (api *APIClient) GetLeagueByLeagueId(leagueId string) (Leagues, error) {
 	if err != nil {
 		return leagues, err
 	}
-	json.Unmarshal(resp, &leagues)
+	if err := api.decode("leagues", resp, &leagues); err != nil {
+		return leagues, err
+	}
 	return leagues, nil
 }
 
@@ -869,7 +914,9 @@ func (api *APIClient) GetStandingsByLeagueId(leagueId string) (Standings, error)
 	if err != nil {
 		return standings, err
 	}
-	json.Unmarshal(resp, &standings)
+	if err := api.decode("standings", resp, &standings); err != nil {
+		return standings, err
+	}
 	return standings, nil
 }
 
@@ -882,7 +929,9 @@ func (api *APIClient) GetTopscorersByLeagueId(leagueId string) (Topscorers, erro
 	if err != nil {
 		return topscorers, err
 	}
-	json.Unmarshal(resp, &topscorers)
+	if err := api.decode("players/topscorers", resp, &topscorers); err != nil {
+		return topscorers, err
+	}
 	return topscorers, nil
 }
 
@@ -895,7 +944,9 @@ func (api *APIClient) GetTopassistsByLeagueId(leagueId string) (Topassists, erro
 	if err != nil {
 		return topassists, err
 	}
-	json.Unmarshal(resp, &topassists)
+	if err := api.decode("players/topassists", resp, &topassists); err != nil {
+		return topassists, err
+	}
 	return topassists, nil
 }
 
@@ -908,7 +959,9 @@ func (api *APIClient) GetTopyellowcardsByLeagueId(leagueId string) (Topyellowcar
 	if err != nil {
 		return topyellowcards, err
 	}
-	json.Unmarshal(resp, &topyellowcards)
+	if err := api.decode("players/topyellowcards", resp, &topyellowcards); err != nil {
+		return topyellowcards, err
+	}
 	return topyellowcards, nil
 }
 
@@ -921,7 +974,9 @@ func (api *APIClient) GetTopredcardsByLeagueId(leagueId string) (Topredcards, er
 	if err != nil {
 		return topyellowcards, err
 	}
-	json.Unmarshal(resp, &topyellowcards)
+	if err := api.decode("players/topredcards", resp, &topyellowcards); err != nil {
+		return topyellowcards, err
+	}
 	return topyellowcards, nil
 }
 
@@ -934,7 +989,9 @@ func (api *APIClient) GetTeamsByLeagueId(leagueId string) (Teams, error) {
 	if err != nil {
 		return teams, err
 	}
-	json.Unmarshal(resp, &teams)
+	if err := api.decode("teams", resp, &teams); err != nil {
+		return teams, err
+	}
 	return teams, nil
 }
 
@@ -948,7 +1005,9 @@ func (api *APIClient) GetTeamsByLeagueIdAndTeamId(leagueId string, teamId string
 	if err != nil {
 		return teams, err
 	}
-	json.Unmarshal(resp, &teams)
+	if err := api.decode("teams", resp, &teams); err != nil {
+		return teams, err
+	}
 	return teams, nil
 }
 
@@ -962,10 +1021,30 @@ func (api *APIClient) GetStatisticsByLeagueIdAndTeamId(leagueId string, teamId s
 	if err != nil {
 		return statistics, err
 	}
-	json.Unmarshal(resp, &statistics)
+	if err := api.decode("teams/statistics", resp, &statistics); err != nil {
+		return statistics, err
+	}
 	return statistics, nil
 }
 
+// GetPlayersByLeagueId returns one page of the players of a league; the
+// provider sends 20 per page.
+func (api *APIClient) GetPlayersByLeagueId(leagueId string, page string) (Players, error) {
+	resp, err := api.doRequest("players", map[string]string{
+		"season": "2021",
+		"league": leagueId,
+		"page":   page,
+	})
+	var players Players
+	if err != nil {
+		return players, err
+	}
+	if err := api.decode("players", resp, &players); err != nil {
+		return players, err
+	}
+	return players, nil
+}
+
 func (api *APIClient) GetPlayersByLeagueIdAndTeamId(leagueId string, teamId string) (Players, error) {
 	resp, err := api.doRequest("players", map[string]string{
 		"season": "2021",
@@ -976,10 +1055,27 @@ func (api *APIClient) GetPlayersByLeagueIdAndTeamId(leagueId string, teamId stri
 	if err != nil {
 		return players, err
 	}
-	json.Unmarshal(resp, &players)
+	if err := api.decode("players", resp, &players); err != nil {
+		return players, err
+	}
 	return players, nil
 }
 
+func (api *APIClient) GetFixturesByLeagueId(leagueId string) (Fixtures, error) {
+	resp, err := api.doRequest("fixtures", map[string]string{
+		"season": "2021",
+		"league": leagueId,
+	})
+	var fixtures Fixtures
+	if err != nil {
+		return fixtures, err
+	}
+	if err := api.decode("fixtures", resp, &fixtures); err != nil {
+		return fixtures, err
+	}
+	return fixtures, nil
+}
+
 func (api *APIClient) GetFixturesByLeagueIdAndTeamId(leagueId string, teamId string) (Fixtures, error) {
 	resp, err := api.doRequest("fixtures", map[string]string{
 		"season": "2021",
@@ -990,7 +1086,24 @@ func (api *APIClient) GetFixturesByLeagueIdAndTeamId(leagueId string, teamId str
 	if err != nil {
 		return fixtures, err
 	}
-	json.Unmarshal(resp, &fixtures)
+	if err := api.decode("fixtures", resp, &fixtures); err != nil {
+		return fixtures, err
+	}
+	return fixtures, nil
+}
+
+func (api *APIClient) GetFixturesByTeamId(teamId string) (Fixtures, error) {
+	resp, err := api.doRequest("fixtures", map[string]string{
+		"season": "2021",
+		"team":   teamId,
+	})
+	var fixtures Fixtures
+	if err != nil {
+		return fixtures, err
+	}
+	if err := api.decode("fixtures", resp, &fixtures); err != nil {
+		return fixtures, err
+	}
 	return fixtures, nil
 }
 
@@ -1002,7 +1115,9 @@ func (api *APIClient) GetFixtureByFixtureId(fixtureId string) (Fixtures, error)
 	if err != nil {
 		return fixtures, err
 	}
-	json.Unmarshal(resp, &fixtures)
+	if err := api.decode("fixtures", resp, &fixtures); err != nil {
+		return fixtures, err
+	}
 	return fixtures, nil
 }
 
@@ -1017,7 +1132,26 @@ func (api *APIClient) GetInjuriesByLeagueIdAndTeamIdAndFixtureId(leagueId string
 	if err != nil {
 		return injuries, err
 	}
-	json.Unmarshal(resp, &injuries)
+	if err := api.decode("injuries", resp, &injuries); err != nil {
+		return injuries, err
+	}
+	return injuries, nil
+}
+
+// GetInjuriesByTeamId returns the players of a team missing each of its
+// fixtures of the season, in every competition.
+func (api *APIClient) GetInjuriesByTeamId(teamId string) (Injuries, error) {
+	resp, err := api.doRequest("injuries", map[string]string{
+		"season": "2021",
+		"team":   teamId,
+	})
+	var injuries Injuries
+	if err != nil {
+		return injuries, err
+	}
+	if err := api.decode("injuries", resp, &injuries); err != nil {
+		return injuries, err
+	}
 	return injuries, nil
 }
 
@@ -1030,7 +1164,9 @@ func (api *APIClient) GetStatisticsByTeamIdAndFixtureId(teamId string, fixtureId
 	if err != nil {
 		return fixturesStatistics, err
 	}
-	json.Unmarshal(resp, &fixturesStatistics)
+	if err := api.decode("fixtures/statistics", resp, &fixturesStatistics); err != nil {
+		return fixturesStatistics, err
+	}
 	return fixturesStatistics, nil
 }
 
@@ -1043,7 +1179,9 @@ func (api *APIClient) GetEventsByTeamIdAndFixtureId(teamId string, fixtureId str
 	if err != nil {
 		return events, err
 	}
-	json.Unmarshal(resp, &events)
+	if err := api.decode("fixtures/events", resp, &events); err != nil {
+		return events, err
+	}
 	return events, nil
 }
 
@@ -1056,7 +1194,9 @@ func (api *APIClient) GetLineupsByTeamIdAndFixtureId(teamId string, fixtureId st
 	if err != nil {
 		return lineups, err
 	}
-	json.Unmarshal(resp, &lineups)
+	if err := api.decode("fixtures/lineups", resp, &lineups); err != nil {
+		return lineups, err
+	}
 	return lineups, nil
 }
 
@@ -1069,7 +1209,9 @@ func (api *APIClient) GetPlayersByTeamIdAndFixtureId(teamId string, fixtureId st
 	if err != nil {
 		return fixturesPlayers, err
 	}
-	json.Unmarshal(resp, &fixturesPlayers)
+	if err := api.decode("fixtures/players", resp, &fixturesPlayers); err != nil {
+		return fixturesPlayers, err
+	}
 	return fixturesPlayers, nil
 }
 
@@ -1081,7 +1223,9 @@ func (api *APIClient) GetCoachsByTeamId(teamId string) (Coachs, error) {
 	if err != nil {
 		return coachs, err
 	}
-	json.Unmarshal(resp, &coachs)
+	if err := api.decode("coachs", resp, &coachs); err != nil {
+		return coachs, err
+	}
 	return coachs, nil
 }
 
@@ -1093,7 +1237,9 @@ func (api *APIClient) GetSquadsByTeamId(teamId string) (Squads, error) {
 	if err != nil {
 		return squads, err
 	}
-	json.Unmarshal(resp, &squads)
+	if err := api.decode("players/squads", resp, &squads); err != nil {
+		return squads, err
+	}
 	return squads, nil
 }
 
@@ -1106,6 +1252,22 @@ func (api *APIClient) GetHeadtoheadByLeagueIdAndH2hId(leagueId string, h2hId str
 	return resp, err
 }
 
+// GetHeadtoheadByTeamIds returns every meeting of two teams the provider
+// knows, in all competitions and seasons.
+func (api *APIClient) GetHeadtoheadByTeamIds(teamId string, opponentId string) (Fixtures, error) {
+	resp, err := api.doRequest("fixtures/headtohead", map[string]string{
+		"h2h": teamId + "-" + opponentId,
+	})
+	var fixtures Fixtures
+	if err != nil {
+		return fixtures, err
+	}
+	if err := api.decode("fixtures/headtohead", resp, &fixtures); err != nil {
+		return fixtures, err
+	}
+	return fixtures, nil
+}
+
 func (api *APIClient) GetVenues() (Venues, error) {
 	resp, err := api.doRequest("venues", map[string]string{
 		"country": "Italy",
@@ -1114,7 +1276,9 @@ func (api *APIClient) GetVenues() (Venues, error) {
 	if err != nil {
 		return venues, err
 	}
-	json.Unmarshal(resp, &venues)
+	if err := api.decode("venues", resp, &venues); err != nil {
+		return venues, err
+	}
 	return venues, nil
 }
 
@@ -1127,7 +1291,9 @@ func (api *APIClient) GetVenueByVenueId(venueId string) (Venues, error) {
 	if err != nil {
 		return venues, err
 	}
-	json.Unmarshal(resp, &venues)
+	if err := api.decode("venues", resp, &venues); err != nil {
+		return venues, err
+	}
 	return venues, nil
 }
 
@@ -1139,7 +1305,9 @@ func (api *APIClient) GetPredictionsByFixtureId(fixtureId string) (Predictions,
 	if err != nil {
 		return predictions, err
 	}
-	json.Unmarshal(resp, &predictions)
+	if err := api.decode("predictions", resp, &predictions); err != nil {
+		return predictions, err
+	}
 	return predictions, nil
 }
 
@@ -1152,7 +1320,9 @@ func (api *APIClient) GetPlayersByPlayerId(playerId string) (Players, error) {
 	if err != nil {
 		return players, err
 	}
-	json.Unmarshal(resp, &players)
+	if err := api.decode("players", resp, &players); err != nil {
+		return players, err
+	}
 	return players, nil
 }
 
@@ -1164,7 +1334,26 @@ func (api *APIClient) GetTransfersByPlayerId(playerId string) (Transfers, error)
 	if err != nil {
 		return transfers, err
 	}
-	json.Unmarshal(resp, &transfers)
+	if err := api.decode("transfers", resp, &transfers); err != nil {
+		return transfers, err
+	}
+	return transfers, nil
+}
+
+// GetTransfersByTeamId returns the transfers of the players who moved to
+// or from a team. The provider lists every transfer of those players,
+// including the ones between other teams.
+func (api *APIClient) GetTransfersByTeamId(teamId string) (Transfers, error) {
+	resp, err := api.doRequest("transfers", map[string]string{
+		"team": teamId,
+	})
+	var transfers Transfers
+	if err != nil {
+		return transfers, err
+	}
+	if err := api.decode("transfers", resp, &transfers); err != nil {
+		return transfers, err
+	}
 	return transfers, nil
 }
 
@@ -1176,7 +1365,9 @@ func (api *APIClient) GetTrophiesByPlayerId(playerId string) (Trophies, error) {
 	if err != nil {
 		return trophies, err
 	}
-	json.Unmarshal(resp, &trophies)
+	if err := api.decode("trophies", resp, &trophies); err != nil {
+		return trophies, err
+	}
 	return trophies, nil
 }
 
@@ -1192,21 +1383,21 @@ type Predictions struct {
 	Response []struct {
 		Predictions struct {
 			Winner struct {
-				ID      int    `json:"id"`
-				Name    string `json:"name"`
-				Comment string `json:"comment"`
+				ID      NullInt    `json:"id"`
+				Name    NullString `json:"name"`
+				Comment NullString `json:"comment"`
 			} `json:"winner"`
-			WinOrDraw bool        `json:"win_or_draw"`
-			UnderOver interface{} `json:"under_over"`
+			WinOrDraw bool       `json:"win_or_draw"`
+			UnderOver NullString `json:"under_over"`
 			Goals     struct {
-				Home string `json:"home"`
-				Away string `json:"away"`
+				Home NullFloat `json:"home"`
+				Away NullFloat `json:"away"`
 			} `json:"goals"`
 			Advice  string `json:"advice"`
 			Percent struct {
-				Home string `json:"home"`
-				Draw string `json:"draw"`
-				Away string `json:"away"`
+				Home Percentage `json:"home"`
+				Draw Percentage `json:"draw"`
+				Away Percentage `json:"away"`
 			} `json:"percent"`
 		} `json:"predictions"`
 		League struct {
@@ -1218,622 +1409,46 @@ type Predictions struct {
 			Season  int    `json:"season"`
 		} `json:"league"`
 		Teams struct {
-			Home struct {
-				ID    int    `json:"id"`
-				Name  string `json:"name"`
-				Logo  string `json:"logo"`
-				Last5 struct {
-					Form  string `json:"form"`
-					Att   string `json:"att"`
-					Def   string `json:"def"`
-					Goals struct {
-						For struct {
-							Total   int    `json:"total"`
-							Average string `json:"average"`
-						} `json:"for"`
-						Against struct {
-							Total   int    `json:"total"`
-							Average string `json:"average"`
-						} `json:"against"`
-					} `json:"goals"`
-				} `json:"last_5"`
-				League struct {
-					Form     string `json:"form"`
-					Fixtures struct {
-						Played struct {
-							Home  int `json:"home"`
-							Away  int `json:"away"`
-							Total int `json:"total"`
-						} `json:"played"`
-						Wins struct {
-							Home  int `json:"home"`
-							Away  int `json:"away"`
-							Total int `json:"total"`
-						} `json:"wins"`
-						Draws struct {
-							Home  int `json:"home"`
-							Away  int `json:"away"`
-							Total int `json:"total"`
-						} `json:"draws"`
-						Loses struct {
-							Home  int `json:"home"`
-							Away  int `json:"away"`
-							Total int `json:"total"`
-						} `json:"loses"`
-					} `json:"fixtures"`
-					Goals struct {
-						For struct {
-							Total struct {
-								Home  int `json:"home"`
-								Away  int `json:"away"`
-								Total int `json:"total"`
-							} `json:"total"`
-							Average struct {
-								Home  string `json:"home"`
-								Away  string `json:"away"`
-								Total string `json:"total"`
-							} `json:"average"`
-							Minute struct {
-								Zero15 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"0-15"`
-								One630 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"16-30"`
-								Three145 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"31-45"`
-								Four660 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"46-60"`
-								Six175 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"61-75"`
-								Seven690 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"76-90"`
-								Nine1105 struct {
-									Total      interface{} `json:"total"`
-									Percentage interface{} `json:"percentage"`
-								} `json:"91-105"`
-								One06120 struct {
-									Total      interface{} `json:"total"`
-									Percentage interface{} `json:"percentage"`
-								} `json:"106-120"`
-							} `json:"minute"`
-						} `json:"for"`
-						Against struct {
-							Total struct {
-								Home  int `json:"home"`
-								Away  int `json:"away"`
-								Total int `json:"total"`
-							} `json:"total"`
-							Average struct {
-								Home  string `json:"home"`
-								Away  string `json:"away"`
-								Total string `json:"total"`
-							} `json:"average"`
-							Minute struct {
-								Zero15 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"0-15"`
-								One630 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"16-30"`
-								Three145 struct {
-									Total      interface{} `json:"total"`
-									Percentage interface{} `json:"percentage"`
-								} `json:"31-45"`
-								Four660 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"46-60"`
-								Six175 struct {
-									Total      interface{} `json:"total"`
-									Percentage interface{} `json:"percentage"`
-								} `json:"61-75"`
-								Seven690 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"76-90"`
-								Nine1105 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"91-105"`
-								One06120 struct {
-									Total      interface{} `json:"total"`
-									Percentage interface{} `json:"percentage"`
-								} `json:"106-120"`
-							} `json:"minute"`
-						} `json:"against"`
-					} `json:"goals"`
-					Biggest struct {
-						Streak struct {
-							Wins  int `json:"wins"`
-							Draws int `json:"draws"`
-							Loses int `json:"loses"`
-						} `json:"streak"`
-						Wins struct {
-							Home string `json:"home"`
-							Away string `json:"away"`
-						} `json:"wins"`
-						Loses struct {
-							Home interface{} `json:"home"`
-							Away interface{} `json:"away"`
-						} `json:"loses"`
-						Goals struct {
-							For struct {
-								Home int `json:"home"`
-								Away int `json:"away"`
-							} `json:"for"`
-							Against struct {
-								Home int `json:"home"`
-								Away int `json:"away"`
-							} `json:"against"`
-						} `json:"goals"`
-					} `json:"biggest"`
-					CleanSheet struct {
-						Home  int `json:"home"`
-						Away  int `json:"away"`
-						Total int `json:"total"`
-					} `json:"clean_sheet"`
-					FailedToScore struct {
-						Home  int `json:"home"`
-						Away  int `json:"away"`
-						Total int `json:"total"`
-					} `json:"failed_to_score"`
-					Penalty struct {
-						Scored struct {
-							Total      int    `json:"total"`
-							Percentage string `json:"percentage"`
-						} `json:"scored"`
-						Missed struct {
-							Total      int    `json:"total"`
-							Percentage string `json:"percentage"`
-						} `json:"missed"`
-						Total int `json:"total"`
-					} `json:"penalty"`
-					Lineups []struct {
-						Formation string `json:"formation"`
-						Played    int    `json:"played"`
-					} `json:"lineups"`
-					Cards struct {
-						Yellow struct {
-							Zero15 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"0-15"`
-							One630 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"16-30"`
-							Three145 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"31-45"`
-							Four660 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"46-60"`
-							Six175 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"61-75"`
-							Seven690 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"76-90"`
-							Nine1105 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"91-105"`
-							One06120 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"106-120"`
-						} `json:"yellow"`
-						Red struct {
-							Zero15 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"0-15"`
-							One630 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"16-30"`
-							Three145 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"31-45"`
-							Four660 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"46-60"`
-							Six175 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"61-75"`
-							Seven690 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"76-90"`
-							Nine1105 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"91-105"`
-							One06120 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"106-120"`
-						} `json:"red"`
-					} `json:"cards"`
-				} `json:"league"`
-			} `json:"home"`
-			Away struct {
-				ID    int    `json:"id"`
-				Name  string `json:"name"`
-				Logo  string `json:"logo"`
-				Last5 struct {
-					Form  string `json:"form"`
-					Att   string `json:"att"`
-					Def   string `json:"def"`
-					Goals struct {
-						For struct {
-							Total   int    `json:"total"`
-							Average string `json:"average"`
-						} `json:"for"`
-						Against struct {
-							Total   int    `json:"total"`
-							Average string `json:"average"`
-						} `json:"against"`
-					} `json:"goals"`
-				} `json:"last_5"`
-				League struct {
-					Form     string `json:"form"`
-					Fixtures struct {
-						Played struct {
-							Home  int `json:"home"`
-							Away  int `json:"away"`
-							Total int `json:"total"`
-						} `json:"played"`
-						Wins struct {
-							Home  int `json:"home"`
-							Away  int `json:"away"`
-							Total int `json:"total"`
-						} `json:"wins"`
-						Draws struct {
-							Home  int `json:"home"`
-							Away  int `json:"away"`
-							Total int `json:"total"`
-						} `json:"draws"`
-						Loses struct {
-							Home  int `json:"home"`
-							Away  int `json:"away"`
-							Total int `json:"total"`
-						} `json:"loses"`
-					} `json:"fixtures"`
-					Goals struct {
-						For struct {
-							Total struct {
-								Home  int `json:"home"`
-								Away  int `json:"away"`
-								Total int `json:"total"`
-							} `json:"total"`
-							Average struct {
-								Home  string `json:"home"`
-								Away  string `json:"away"`
-								Total string `json:"total"`
-							} `json:"average"`
-							Minute struct {
-								Zero15 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"0-15"`
-								One630 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"16-30"`
-								Three145 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"31-45"`
-								Four660 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"46-60"`
-								Six175 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"61-75"`
-								Seven690 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"76-90"`
-								Nine1105 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"91-105"`
-								One06120 struct {
-									Total      interface{} `json:"total"`
-									Percentage interface{} `json:"percentage"`
-								} `json:"106-120"`
-							} `json:"minute"`
-						} `json:"for"`
-						Against struct {
-							Total struct {
-								Home  int `json:"home"`
-								Away  int `json:"away"`
-								Total int `json:"total"`
-							} `json:"total"`
-							Average struct {
-								Home  string `json:"home"`
-								Away  string `json:"away"`
-								Total string `json:"total"`
-							} `json:"average"`
-							Minute struct {
-								Zero15 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"0-15"`
-								One630 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"16-30"`
-								Three145 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"31-45"`
-								Four660 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"46-60"`
-								Six175 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"61-75"`
-								Seven690 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"76-90"`
-								Nine1105 struct {
-									Total      int    `json:"total"`
-									Percentage string `json:"percentage"`
-								} `json:"91-105"`
-								One06120 struct {
-									Total      interface{} `json:"total"`
-									Percentage interface{} `json:"percentage"`
-								} `json:"106-120"`
-							} `json:"minute"`
-						} `json:"against"`
-					} `json:"goals"`
-					Biggest struct {
-						Streak struct {
-							Wins  int `json:"wins"`
-							Draws int `json:"draws"`
-							Loses int `json:"loses"`
-						} `json:"streak"`
-						Wins struct {
-							Home string `json:"home"`
-							Away string `json:"away"`
-						} `json:"wins"`
-						Loses struct {
-							Home interface{} `json:"home"`
-							Away string      `json:"away"`
-						} `json:"loses"`
-						Goals struct {
-							For struct {
-								Home int `json:"home"`
-								Away int `json:"away"`
-							} `json:"for"`
-							Against struct {
-								Home int `json:"home"`
-								Away int `json:"away"`
-							} `json:"against"`
-						} `json:"goals"`
-					} `json:"biggest"`
-					CleanSheet struct {
-						Home  int `json:"home"`
-						Away  int `json:"away"`
-						Total int `json:"total"`
-					} `json:"clean_sheet"`
-					FailedToScore struct {
-						Home  int `json:"home"`
-						Away  int `json:"away"`
-						Total int `json:"total"`
-					} `json:"failed_to_score"`
-					Penalty struct {
-						Scored struct {
-							Total      int    `json:"total"`
-							Percentage string `json:"percentage"`
-						} `json:"scored"`
-						Missed struct {
-							Total      int    `json:"total"`
-							Percentage string `json:"percentage"`
-						} `json:"missed"`
-						Total int `json:"total"`
-					} `json:"penalty"`
-					Lineups []struct {
-						Formation string `json:"formation"`
-						Played    int    `json:"played"`
-					} `json:"lineups"`
-					Cards struct {
-						Yellow struct {
-							Zero15 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"0-15"`
-							One630 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"16-30"`
-							Three145 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"31-45"`
-							Four660 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"46-60"`
-							Six175 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"61-75"`
-							Seven690 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"76-90"`
-							Nine1105 struct {
-								Total      int    `json:"total"`
-								Percentage string `json:"percentage"`
-							} `json:"91-105"`
-							One06120 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"106-120"`
-						} `json:"yellow"`
-						Red struct {
-							Zero15 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"0-15"`
-							One630 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"16-30"`
-							Three145 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"31-45"`
-							Four660 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"46-60"`
-							Six175 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"61-75"`
-							Seven690 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"76-90"`
-							Nine1105 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"91-105"`
-							One06120 struct {
-								Total      interface{} `json:"total"`
-								Percentage interface{} `json:"percentage"`
-							} `json:"106-120"`
-						} `json:"red"`
-					} `json:"cards"`
-				} `json:"league"`
-			} `json:"away"`
+			Home PredictionTeam `json:"home"`
+			Away PredictionTeam `json:"away"`
 		} `json:"teams"`
 		Comparison struct {
-			Form struct {
-				Home string `json:"home"`
-				Away string `json:"away"`
-			} `json:"form"`
-			Att struct {
-				Home string `json:"home"`
-				Away string `json:"away"`
-			} `json:"att"`
-			Def struct {
-				Home string `json:"home"`
-				Away string `json:"away"`
-			} `json:"def"`
-			PoissonDistribution struct {
-				Home string `json:"home"`
-				Away string `json:"away"`
-			} `json:"poisson_distribution"`
-			H2H struct {
-				Home string `json:"home"`
-				Away string `json:"away"`
-			} `json:"h2h"`
-			Goals struct {
-				Home string `json:"home"`
-				Away string `json:"away"`
-			} `json:"goals"`
-			Total struct {
-				Home string `json:"home"`
-				Away string `json:"away"`
-			} `json:"total"`
+			Form                Comparison `json:"form"`
+			Att                 Comparison `json:"att"`
+			Def                 Comparison `json:"def"`
+			PoissonDistribution Comparison `json:"poisson_distribution"`
+			H2H                 Comparison `json:"h2h"`
+			Goals               Comparison `json:"goals"`
+			Total               Comparison `json:"total"`
 		} `json:"comparison"`
-		H2H []struct {
-			Fixture struct {
-				ID        int       `json:"id"`
-				Referee   string    `json:"referee"`
-				Timezone  string    `json:"timezone"`
-				Date      time.Time `json:"date"`
-				Timestamp int       `json:"timestamp"`
-				Periods   struct {
-					First  int `json:"first"`
-					Second int `json:"second"`
-				} `json:"periods"`
-				Venue struct {
-					ID   int    `json:"id"`
-					Name string `json:"name"`
-					City string `json:"city"`
-				} `json:"venue"`
-				Status struct {
-					Long    string `json:"long"`
-					Short   string `json:"short"`
-					Elapsed int    `json:"elapsed"`
-				} `json:"status"`
-			} `json:"fixture"`
-			League struct {
-				ID      int    `json:"id"`
-				Name    string `json:"name"`
-				Country string `json:"country"`
-				Logo    string `json:"logo"`
-				Flag    string `json:"flag"`
-				Season  int    `json:"season"`
-				Round   string `json:"round"`
-			} `json:"league"`
-			Teams struct {
-				Home struct {
-					ID     int    `json:"id"`
-					Name   string `json:"name"`
-					Logo   string `json:"logo"`
-					Winner bool   `json:"winner"`
-				} `json:"home"`
-				Away struct {
-					ID     int    `json:"id"`
-					Name   string `json:"name"`
-					Logo   string `json:"logo"`
-					Winner bool   `json:"winner"`
-				} `json:"away"`
-			} `json:"teams"`
-			Goals struct {
-				Home int `json:"home"`
-				Away int `json:"away"`
-			} `json:"goals"`
-			Score struct {
-				Halftime struct {
-					Home int `json:"home"`
-					Away int `json:"away"`
-				} `json:"halftime"`
-				Fulltime struct {
-					Home int `json:"home"`
-					Away int `json:"away"`
-				} `json:"fulltime"`
-				Extratime struct {
-					Home interface{} `json:"home"`
-					Away interface{} `json:"away"`
-				} `json:"extratime"`
-				Penalty struct {
-					Home interface{} `json:"home"`
-					Away interface{} `json:"away"`
-				} `json:"penalty"`
-			} `json:"score"`
-		} `json:"h2h"`
+		H2H []Fixture `json:"h2h"`
 	} `json:"response"`
 }
+
+type PredictionTeam struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Logo  string `json:"logo"`
+	Last5 struct {
+		Form  Percentage `json:"form"`
+		Att   Percentage `json:"att"`
+		Def   Percentage `json:"def"`
+		Goals struct {
+			For struct {
+				Total   int       `json:"total"`
+				Average NullFloat `json:"average"`
+			} `json:"for"`
+			Against struct {
+				Total   int       `json:"total"`
+				Average NullFloat `json:"average"`
+			} `json:"against"`
+		} `json:"goals"`
+	} `json:"last_5"`
+	League TeamSeason `json:"league"`
+}
+
+// Comparison splits 100% between the home and away team.
+type Comparison struct {
+	Home Percentage `json:"home"`
+	Away Percentage `json:"away"`
+}
@@ -0,0 +1,365 @@
+package apifootball
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/nero-15/calcio-app/recorder"
+)
+
+// go test ./apifootball -record re-records testdata/recordings from the live
+// API using APIFOOTBALL_TOKEN. Each recorded call counts against the daily quota.
+var record = flag.Bool("record", false, "record responses from the live API-Football")
+
+const recordings = "testdata/recordings"
+
+func newTestClient(t *testing.T) *APIClient {
+	t.Helper()
+	mode := recorder.Replay
+	token := "test-token"
+	if *record {
+		mode = recorder.Record
+		token = os.Getenv("APIFOOTBALL_TOKEN")
+		if token == "" {
+			t.Fatal("-record needs APIFOOTBALL_TOKEN")
+		}
+	}
+	return NewWithHTTPClient(token, "https://v3.football.api-sports.io/", recorder.New(recordings, mode).Client())
+}
+
+func TestGetResponses(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(t *testing.T, api *APIClient) int
+	}{
+		{"GetStatus", func(t *testing.T, api *APIClient) int {
+			status, err := api.GetStatus()
+			mustNot(t, err)
+			if status.Response.Requests.LimitDay == 0 || status.Response.Subscription.End.IsZero() {
+				t.Errorf("status not decoded: %+v", status.Response)
+			}
+			return status.Results
+		}},
+		{"GetLeagues", func(t *testing.T, api *APIClient) int {
+			leagues, err := api.GetLeagues()
+			mustNot(t, err)
+			if leagues.Response[0].League.ID == 0 || len(leagues.Response[0].Seasons) == 0 {
+				t.Errorf("league not decoded: %+v", leagues.Response[0])
+			}
+			return leagues.Results
+		}},
+		{"GetLeagueByLeagueId", func(t *testing.T, api *APIClient) int {
+			leagues, err := api.GetLeagueByLeagueId("135")
+			mustNot(t, err)
+			if leagues.Response[0].League.ID != 135 {
+				t.Errorf("league id = %d, want 135", leagues.Response[0].League.ID)
+			}
+			return leagues.Results
+		}},
+		{"GetStandingsByLeagueId", func(t *testing.T, api *APIClient) int {
+			standings, err := api.GetStandingsByLeagueId("135")
+			mustNot(t, err)
+			table := standings.Response[0].League.Standings[0]
+			if table[0].Rank != 1 || table[0].Team.ID == 0 || table[0].All.Played == 0 {
+				t.Errorf("standings row not decoded: %+v", table[0])
+			}
+			return standings.Results
+		}},
+		{"GetTopscorersByLeagueId", func(t *testing.T, api *APIClient) int {
+			topscorers, err := api.GetTopscorersByLeagueId("135")
+			mustNot(t, err)
+			top := topscorers.Response[0]
+			if top.Player.ID == 0 || top.Statistics[0].Goals.Total.Int == 0 {
+				t.Errorf("topscorer not decoded: %+v", top)
+			}
+			return topscorers.Results
+		}},
+		{"GetTopassistsByLeagueId", func(t *testing.T, api *APIClient) int {
+			topassists, err := api.GetTopassistsByLeagueId("135")
+			mustNot(t, err)
+			if topassists.Response[0].Player.ID == 0 || topassists.Response[0].Statistics[0].Goals.Assists.Int == 0 {
+				t.Errorf("topassist not decoded: %+v", topassists.Response[0])
+			}
+			return topassists.Results
+		}},
+		{"GetTopyellowcardsByLeagueId", func(t *testing.T, api *APIClient) int {
+			topyellowcards, err := api.GetTopyellowcardsByLeagueId("135")
+			mustNot(t, err)
+			if topyellowcards.Response[0].Player.ID == 0 || topyellowcards.Response[0].Statistics[0].Cards.Yellow == 0 {
+				t.Errorf("top yellow card not decoded: %+v", topyellowcards.Response[0])
+			}
+			return topyellowcards.Results
+		}},
+		{"GetTopredcardsByLeagueId", func(t *testing.T, api *APIClient) int {
+			topredcards, err := api.GetTopredcardsByLeagueId("135")
+			mustNot(t, err)
+			if topredcards.Response[0].Player.ID == 0 || topredcards.Response[0].Statistics[0].Cards.Red == 0 {
+				t.Errorf("top red card not decoded: %+v", topredcards.Response[0])
+			}
+			return topredcards.Results
+		}},
+		{"GetTeamsByLeagueId", func(t *testing.T, api *APIClient) int {
+			teams, err := api.GetTeamsByLeagueId("135")
+			mustNot(t, err)
+			if teams.Response[0].Team.ID == 0 || teams.Response[0].Venue.ID == 0 {
+				t.Errorf("team not decoded: %+v", teams.Response[0])
+			}
+			return teams.Results
+		}},
+		{"GetTeamsByLeagueIdAndTeamId", func(t *testing.T, api *APIClient) int {
+			teams, err := api.GetTeamsByLeagueIdAndTeamId("135", "505")
+			mustNot(t, err)
+			if teams.Response[0].Team.ID != 505 {
+				t.Errorf("team id = %d, want 505", teams.Response[0].Team.ID)
+			}
+			return teams.Results
+		}},
+		{"GetStatisticsByLeagueIdAndTeamId", func(t *testing.T, api *APIClient) int {
+			statistics, err := api.GetStatisticsByLeagueIdAndTeamId("135", "505")
+			mustNot(t, err)
+			if statistics.Response.Team.ID != 505 || statistics.Response.Fixtures.Played.Total == 0 || statistics.Response.Goals.For.Minute.Zero15.Total.Int == 0 {
+				t.Errorf("statistics not decoded: %+v", statistics.Response)
+			}
+			return statistics.Results
+		}},
+		{"GetPlayersByLeagueId", func(t *testing.T, api *APIClient) int {
+			players, err := api.GetPlayersByLeagueId("135", "1")
+			mustNot(t, err)
+			if players.Paging.Total < 2 || players.Response[0].Player.ID == 0 || players.Response[0].Statistics[0].League.ID != 135 {
+				t.Errorf("players page not decoded: %+v", players.Paging)
+			}
+			return players.Results
+		}},
+		{"GetPlayersByLeagueIdAndTeamId", func(t *testing.T, api *APIClient) int {
+			players, err := api.GetPlayersByLeagueIdAndTeamId("135", "505")
+			mustNot(t, err)
+			if players.Response[0].Player.ID == 0 || players.Response[0].Statistics[0].Games.Minutes.Int == 0 {
+				t.Errorf("player not decoded: %+v", players.Response[0])
+			}
+			return players.Results
+		}},
+		{"GetFixturesByLeagueIdAndTeamId", func(t *testing.T, api *APIClient) int {
+			fixtures, err := api.GetFixturesByLeagueIdAndTeamId("135", "505")
+			mustNot(t, err)
+			fixture := fixtures.Response[0]
+			if fixture.Fixture.ID == 0 || fixture.Fixture.Date.IsZero() || fixture.Teams.Home.ID == 0 {
+				t.Errorf("fixture not decoded: %+v", fixture)
+			}
+			return fixtures.Results
+		}},
+		{"GetFixturesByLeagueId", func(t *testing.T, api *APIClient) int {
+			fixtures, err := api.GetFixturesByLeagueId("135")
+			mustNot(t, err)
+			fixture := fixtures.Response[0]
+			if fixture.League.ID != 135 || fixture.League.Round == "" || fixture.Teams.Home.ID == 0 {
+				t.Errorf("fixture not decoded: %+v", fixture)
+			}
+			return fixtures.Results
+		}},
+		{"GetFixturesByTeamId", func(t *testing.T, api *APIClient) int {
+			fixtures, err := api.GetFixturesByTeamId("505")
+			mustNot(t, err)
+			fixture := fixtures.Response[0]
+			if fixture.Teams.Home.ID != 505 && fixture.Teams.Away.ID != 505 {
+				t.Errorf("fixture without the team: %+v", fixture.Teams)
+			}
+			return fixtures.Results
+		}},
+		{"GetFixtureByFixtureId", func(t *testing.T, api *APIClient) int {
+			fixtures, err := api.GetFixtureByFixtureId("731698")
+			mustNot(t, err)
+			if fixtures.Response[0].Fixture.ID != 731698 {
+				t.Errorf("fixture id = %d, want 731698", fixtures.Response[0].Fixture.ID)
+			}
+			return fixtures.Results
+		}},
+		{"GetInjuriesByLeagueIdAndTeamIdAndFixtureId", func(t *testing.T, api *APIClient) int {
+			injuries, err := api.GetInjuriesByLeagueIdAndTeamIdAndFixtureId("135", "505", "731698")
+			mustNot(t, err)
+			if injuries.Response[0].Player.ID == 0 || injuries.Response[0].Fixture.ID != 731698 {
+				t.Errorf("injury not decoded: %+v", injuries.Response[0])
+			}
+			return injuries.Results
+		}},
+		{"GetStatisticsByTeamIdAndFixtureId", func(t *testing.T, api *APIClient) int {
+			fixturesStatistics, err := api.GetStatisticsByTeamIdAndFixtureId("505", "731698")
+			mustNot(t, err)
+			if len(fixturesStatistics.Response[0].Statistics) == 0 {
+				t.Errorf("fixture statistics not decoded: %+v", fixturesStatistics.Response[0])
+			}
+			return fixturesStatistics.Results
+		}},
+		{"GetEventsByTeamIdAndFixtureId", func(t *testing.T, api *APIClient) int {
+			events, err := api.GetEventsByTeamIdAndFixtureId("505", "731698")
+			mustNot(t, err)
+			if events.Response[0].Time.Elapsed == 0 || events.Response[0].Type == "" {
+				t.Errorf("event not decoded: %+v", events.Response[0])
+			}
+			return events.Results
+		}},
+		{"GetLineupsByTeamIdAndFixtureId", func(t *testing.T, api *APIClient) int {
+			lineups, err := api.GetLineupsByTeamIdAndFixtureId("505", "731698")
+			mustNot(t, err)
+			lineup := lineups.Response[0]
+			if lineup.Formation == "" || len(lineup.Startxi) != 11 || lineup.Startxi[0].Player.Grid == "" {
+				t.Errorf("lineup not decoded: %+v", lineup)
+			}
+			return lineups.Results
+		}},
+		{"GetPlayersByTeamIdAndFixtureId", func(t *testing.T, api *APIClient) int {
+			fixturesPlayers, err := api.GetPlayersByTeamIdAndFixtureId("505", "731698")
+			mustNot(t, err)
+			if fixturesPlayers.Response[0].Players[0].Statistics[0].Games.Minutes.Int == 0 {
+				t.Errorf("fixture players not decoded: %+v", fixturesPlayers.Response[0])
+			}
+			return fixturesPlayers.Results
+		}},
+		{"GetCoachsByTeamId", func(t *testing.T, api *APIClient) int {
+			coachs, err := api.GetCoachsByTeamId("505")
+			mustNot(t, err)
+			if coachs.Response[0].ID == 0 || len(coachs.Response[0].Career) == 0 {
+				t.Errorf("coach not decoded: %+v", coachs.Response[0])
+			}
+			return coachs.Results
+		}},
+		{"GetSquadsByTeamId", func(t *testing.T, api *APIClient) int {
+			squads, err := api.GetSquadsByTeamId("505")
+			mustNot(t, err)
+			if len(squads.Response[0].Players) == 0 || squads.Response[0].Players[0].Number == 0 {
+				t.Errorf("squad not decoded: %+v", squads.Response[0])
+			}
+			return squads.Results
+		}},
+		{"GetHeadtoheadByLeagueIdAndH2hId", func(t *testing.T, api *APIClient) int {
+			resp, err := api.GetHeadtoheadByLeagueIdAndH2hId("135", "505-489")
+			mustNot(t, err)
+			var fixtures Fixtures
+			mustNot(t, json.Unmarshal(resp, &fixtures))
+			return fixtures.Results
+		}},
+		{"GetHeadtoheadByTeamIds", func(t *testing.T, api *APIClient) int {
+			fixtures, err := api.GetHeadtoheadByTeamIds("505", "489")
+			mustNot(t, err)
+			teams := fixtures.Response[0].Teams
+			if teams.Home.ID+teams.Away.ID != 505+489 {
+				t.Errorf("fixture not between the teams: %+v", teams)
+			}
+			return fixtures.Results
+		}},
+		{"GetVenues", func(t *testing.T, api *APIClient) int {
+			venues, err := api.GetVenues()
+			mustNot(t, err)
+			if venues.Response[0].Venue.ID == 0 || venues.Response[0].Venue.Capacity == 0 {
+				t.Errorf("venue not decoded: %+v", venues.Response[0])
+			}
+			return venues.Results
+		}},
+		{"GetVenueByVenueId", func(t *testing.T, api *APIClient) int {
+			venues, err := api.GetVenueByVenueId("907")
+			mustNot(t, err)
+			if venues.Response[0].Venue.ID != 907 {
+				t.Errorf("venue id = %d, want 907", venues.Response[0].Venue.ID)
+			}
+			return venues.Results
+		}},
+		{"GetPredictionsByFixtureId", func(t *testing.T, api *APIClient) int {
+			predictions, err := api.GetPredictionsByFixtureId("731698")
+			mustNot(t, err)
+			prediction := predictions.Response[0]
+			if !prediction.Predictions.Percent.Home.Valid || prediction.Teams.Home.ID == 0 || len(prediction.H2H) == 0 {
+				t.Errorf("prediction not decoded: %+v", prediction.Predictions)
+			}
+			return predictions.Results
+		}},
+		{"GetPlayersByPlayerId", func(t *testing.T, api *APIClient) int {
+			players, err := api.GetPlayersByPlayerId("198")
+			mustNot(t, err)
+			if players.Response[0].Player.ID != 198 || len(players.Response[0].Statistics) < 2 {
+				t.Errorf("player not decoded: %+v", players.Response[0])
+			}
+			return players.Results
+		}},
+		{"GetTransfersByPlayerId", func(t *testing.T, api *APIClient) int {
+			transfers, err := api.GetTransfersByPlayerId("198")
+			mustNot(t, err)
+			if len(transfers.Response[0].Transfers) == 0 || transfers.Response[0].Transfers[0].Teams.In.ID == 0 {
+				t.Errorf("transfers not decoded: %+v", transfers.Response[0])
+			}
+			return transfers.Results
+		}},
+		{"GetInjuriesByTeamId", func(t *testing.T, api *APIClient) int {
+			injuries, err := api.GetInjuriesByTeamId("505")
+			mustNot(t, err)
+			if injuries.Response[0].Player.ID == 0 || injuries.Response[0].Team.ID != 505 || injuries.Response[0].Fixture.ID == 0 {
+				t.Errorf("injury not decoded: %+v", injuries.Response[0])
+			}
+			return injuries.Results
+		}},
+		{"GetTransfersByTeamId", func(t *testing.T, api *APIClient) int {
+			transfers, err := api.GetTransfersByTeamId("505")
+			mustNot(t, err)
+			if transfers.Response[0].Player.ID == 0 || len(transfers.Response[0].Transfers) == 0 {
+				t.Errorf("transfers not decoded: %+v", transfers.Response[0])
+			}
+			return transfers.Results
+		}},
+		{"GetTrophiesByPlayerId", func(t *testing.T, api *APIClient) int {
+			trophies, err := api.GetTrophiesByPlayerId("198")
+			mustNot(t, err)
+			if trophies.Response[0].Trophy.League == "" || trophies.Response[0].Trophy.Place == "" {
+				t.Errorf("trophy not decoded: %+v", trophies.Response[0])
+			}
+			return trophies.Results
+		}},
+		{"GetSidelinedByPlayerId", func(t *testing.T, api *APIClient) int {
+			resp, err := api.GetSidelinedByPlayerId("198")
+			mustNot(t, err)
+			var sidelined CommonResponse
+			mustNot(t, json.Unmarshal(resp, &sidelined))
+			return sidelined.Results
+		}},
+	}
+
+	api := newTestClient(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if results := tt.call(t, api); results == 0 {
+				t.Errorf("results = 0")
+			}
+		})
+	}
+}
+
+func TestCommonResponseFields(t *testing.T) {
+	status, err := newTestClient(t).GetStatus()
+	mustNot(t, err)
+	// Routes pass responses through: parameters and errors keep their shape.
+	passthrough, err := json.Marshal(status)
+	mustNot(t, err)
+	var envelope map[string]json.RawMessage
+	mustNot(t, json.Unmarshal(passthrough, &envelope))
+	if string(envelope["parameters"]) != "[]" || string(envelope["errors"]) != "[]" {
+		t.Errorf("parameters %s, errors %s", envelope["parameters"], envelope["errors"])
+	}
+	if len(status.ErrorMessages()) != 0 {
+		t.Errorf("errors = %v", status.ErrorMessages())
+	}
+
+	var failed CommonResponse
+	mustNot(t, json.Unmarshal([]byte(`{"parameters": {"league": "135", "season": {"from": 2021}}, "errors": {"token": "Error/Missing application key."}}`), &failed))
+	if failed.ErrorMessages()["token"] != "Error/Missing application key." {
+		t.Errorf("errors = %v", failed.ErrorMessages())
+	}
+	if string(failed.Parameters) != `{"league": "135", "season": {"from": 2021}}` {
+		t.Errorf("parameters = %s", failed.Parameters)
+	}
+}
+
+func mustNot(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
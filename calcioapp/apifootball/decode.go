@@ -0,0 +1,361 @@
+package apifootball
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nero-15/calcio-app/logging"
+	"github.com/nero-15/calcio-app/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// DecodeMode decides what happens when a payload drifts from our structs.
+type DecodeMode int
+
+const (
+	// Lenient logs and counts drift, converts numbers sent as strings (and the
+	// reverse) and drops what cannot be converted.
+	Lenient DecodeMode = iota
+	// Strict logs and counts drift and fails the request.
+	Strict
+)
+
+// ParseDecodeMode reads the apiFootball.decode setting.
+func ParseDecodeMode(name string) (DecodeMode, error) {
+	switch strings.ToLower(name) {
+	case "", "lenient":
+		return Lenient, nil
+	case "strict":
+		return Strict, nil
+	}
+	return Lenient, fmt.Errorf("apifootball: unknown decode mode %q", name)
+}
+
+const (
+	DriftUnknownField = "unknown_field"
+	DriftTypeMismatch = "type_mismatch"
+)
+
+// Drift is one difference between a payload and the struct it is decoded
+// into. Array indexes are left out of Path, so each difference is reported
+// once per response, e.g. response[].statistics[].value.
+type Drift struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+type DriftError struct {
+	Endpoint string
+	Drifts   []Drift
+}
+
+func (e *DriftError) Error() string {
+	first := e.Drifts[0]
+	msg := fmt.Sprintf("apifootball: %s: schema drift: %s at %s (%s)", e.Endpoint, first.Kind, first.Path, first.Detail)
+	if len(e.Drifts) > 1 {
+		msg += fmt.Sprintf(" and %d more", len(e.Drifts)-1)
+	}
+	return msg
+}
+
+// WithDecodeMode returns a copy of the client decoding responses in mode.
+func (api *APIClient) WithDecodeMode(mode DecodeMode) *APIClient {
+	apiClient := *api
+	apiClient.decodeMode = mode
+	return &apiClient
+}
+
+// decode unmarshals a response of endpoint into v, reporting drift.
+func (api *APIClient) decode(endpoint string, data []byte, v interface{}) error {
+	var raw interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return fmt.Errorf("apifootball: %s: %v", endpoint, err)
+	}
+
+	c := driftChecker{seen: map[string]bool{}}
+	repaired := c.check("", raw, reflect.TypeOf(v).Elem())
+	if len(c.drifts) == 0 {
+		return json.Unmarshal(data, v)
+	}
+	reportDrift(api.ctx, endpoint, c.drifts)
+	if api.decodeMode == Strict {
+		return &DriftError{endpoint, c.drifts}
+	}
+	if data, err := json.Marshal(repaired); err == nil {
+		return json.Unmarshal(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// CheckDrift lists the differences between data and the type of v without
+// decoding it.
+func CheckDrift(data []byte, v interface{}) ([]Drift, error) {
+	var raw interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	c := driftChecker{seen: map[string]bool{}}
+	c.check("", raw, reflect.TypeOf(v).Elem())
+	return c.drifts, nil
+}
+
+func reportDrift(ctx context.Context, endpoint string, drifts []Drift) {
+	for _, drift := range drifts {
+		metrics.SchemaDrift("apifootball", endpoint, drift.Kind)
+		logging.FromContext(ctx).WithFields(logrus.Fields{
+			"provider": "apifootball",
+			"endpoint": endpoint,
+			"path":     drift.Path,
+			"kind":     drift.Kind,
+			"detail":   drift.Detail,
+		}).Warn("schema drift")
+	}
+}
+
+var (
+	intType        = reflect.TypeOf(0)
+	stringType     = reflect.TypeOf("")
+	nullIntType    = reflect.TypeOf(NullInt{})
+	nullStringType = reflect.TypeOf(NullString{})
+	nullFloatType  = reflect.TypeOf(NullFloat{})
+	percentageType = reflect.TypeOf(Percentage{})
+	statValueType  = reflect.TypeOf(StatisticValue{})
+	rawType        = reflect.TypeOf(json.RawMessage{})
+	timeType       = reflect.TypeOf(time.Time{})
+)
+
+// decodesAs maps types with their own UnmarshalJSON to the type whose json
+// they accept. nil accepts anything.
+var decodesAs = map[reflect.Type]reflect.Type{
+	nullIntType:    intType,
+	nullStringType: stringType,
+	nullFloatType:  nil,
+	percentageType: stringType,
+	statValueType:  nil,
+	rawType:        nil,
+	timeType:       stringType,
+}
+
+type driftChecker struct {
+	drifts []Drift
+	seen   map[string]bool
+}
+
+func (c *driftChecker) report(path string, kind string, detail string) {
+	if path == "" {
+		path = "."
+	}
+	if c.seen[kind+path] {
+		return
+	}
+	c.seen[kind+path] = true
+	c.drifts = append(c.drifts, Drift{path, kind, detail})
+}
+
+func (c *driftChecker) mismatch(path string, want string, x interface{}) {
+	c.report(path, DriftTypeMismatch, fmt.Sprintf("want %s, got %s", want, jsonKind(x)))
+}
+
+// check compares the decoded json x with t and returns x converted to what
+// t accepts, or nil where it cannot be converted.
+func (c *driftChecker) check(path string, x interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if x == nil {
+		return nil
+	}
+	if as, ok := decodesAs[t]; ok {
+		if as == nil {
+			return x
+		}
+		t = as
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return x
+	case reflect.String:
+		switch v := x.(type) {
+		case string:
+			return v
+		case json.Number:
+			c.mismatch(path, "string", x)
+			return v.String()
+		case bool:
+			c.mismatch(path, "string", x)
+			return strconv.FormatBool(v)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch v := x.(type) {
+		case json.Number:
+			if _, err := v.Int64(); err == nil {
+				return v
+			}
+			c.mismatch(path, "integer", x)
+			if f, err := v.Float64(); err == nil {
+				return json.Number(strconv.FormatInt(int64(f), 10))
+			}
+			return nil
+		case string:
+			c.mismatch(path, "integer", x)
+			if n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+				return json.Number(strconv.FormatInt(n, 10))
+			}
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch v := x.(type) {
+		case json.Number:
+			return v
+		case string:
+			c.mismatch(path, "number", x)
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+			}
+			return nil
+		}
+	case reflect.Bool:
+		switch v := x.(type) {
+		case bool:
+			return v
+		case string:
+			c.mismatch(path, "boolean", x)
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b
+			}
+			return nil
+		}
+	case reflect.Slice, reflect.Array:
+		if list, ok := x.([]interface{}); ok {
+			for i, item := range list {
+				list[i] = c.check(path+"[]", item, t.Elem())
+			}
+			return list
+		}
+	case reflect.Map:
+		if object, ok := x.(map[string]interface{}); ok {
+			for key, value := range object {
+				object[key] = c.check(path+".*", value, t.Elem())
+			}
+			return object
+		}
+	case reflect.Struct:
+		object, ok := x.(map[string]interface{})
+		if !ok {
+			break
+		}
+		fields := jsonFields(t)
+		for key, value := range object {
+			field, ok := fields.lookup(key)
+			if !ok {
+				c.report(join(path, key), DriftUnknownField, "field "+key+" of type "+jsonKind(value)+" is not decoded")
+				continue
+			}
+			object[key] = c.check(join(path, key), value, field)
+		}
+		return object
+	}
+	c.mismatch(path, kindName(t), x)
+	return nil
+}
+
+func join(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func jsonKind(x interface{}) string {
+	switch x.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+func kindName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.Kind().String()
+}
+
+// fieldSet maps json names to field types the way encoding/json does:
+// exact name first, then a case-insensitive match.
+type fieldSet map[string]reflect.Type
+
+func (f fieldSet) lookup(key string) (reflect.Type, bool) {
+	if t, ok := f[key]; ok {
+		return t, true
+	}
+	for name, t := range f {
+		if strings.EqualFold(name, key) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// jsonFields lists the json fields of a struct, promoting the fields of
+// untagged embedded structs. Shallower fields win.
+func jsonFields(t reflect.Type) fieldSet {
+	fields := fieldSet{}
+	depth := map[string]int{}
+	var walk func(t reflect.Type, level int)
+	walk = func(t reflect.Type, level int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name := strings.Split(tag, ",")[0]
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+				walk(ft, level+1)
+				continue
+			}
+			if f.PkgPath != "" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			if d, ok := depth[name]; ok && d <= level {
+				continue
+			}
+			depth[name] = level
+			fields[name] = f.Type
+		}
+	}
+	walk(t, 0)
+	return fields
+}
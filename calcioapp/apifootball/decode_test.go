@@ -0,0 +1,59 @@
+package apifootball
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const driftedEvents = `{"get":"fixtures/events","parameters":{"fixture":"1"},"errors":[],"results":1,"paging":{"current":1,"total":1},
+"response":[{"time":{"elapsed":"67","extra":null},"team":{"id":505,"name":"Inter","logo":""},"player":{"id":217,"name":"L. Martínez"},
+"assist":{"id":null,"name":null},"type":"Goal","detail":"Normal Goal","comments":null,"var":true}]}`
+
+func eventsClient(t *testing.T) *APIClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(driftedEvents))
+	}))
+	t.Cleanup(server.Close)
+	return New("test-token", server.URL+"/")
+}
+
+func TestCheckDrift(t *testing.T) {
+	drifts, err := CheckDrift([]byte(driftedEvents), &Events{})
+	mustNot(t, err)
+	want := map[string]string{
+		"response[].time.elapsed": DriftTypeMismatch,
+		"response[].var":          DriftUnknownField,
+	}
+	if len(drifts) != len(want) {
+		t.Fatalf("drifts = %+v, want %v", drifts, want)
+	}
+	for _, drift := range drifts {
+		if want[drift.Path] != drift.Kind {
+			t.Errorf("unexpected drift %+v", drift)
+		}
+	}
+}
+
+func TestLenientDecodingRepairsDrift(t *testing.T) {
+	events, err := eventsClient(t).GetEventsByTeamIdAndFixtureId("505", "1")
+	mustNot(t, err)
+	event := events.Response[0]
+	if event.Time.Elapsed != 67 {
+		t.Errorf("elapsed = %d, want the string converted to 67", event.Time.Elapsed)
+	}
+	if event.Assist.ID.Valid || event.Time.Extra.Valid || event.Comments.Valid {
+		t.Errorf("null fields decoded as set: %+v", event)
+	}
+}
+
+func TestStrictDecodingFailsOnDrift(t *testing.T) {
+	_, err := eventsClient(t).WithDecodeMode(Strict).GetEventsByTeamIdAndFixtureId("505", "1")
+	driftErr, ok := err.(*DriftError)
+	if !ok {
+		t.Fatalf("err = %v, want a *DriftError", err)
+	}
+	if driftErr.Endpoint != "fixtures/events" || len(driftErr.Drifts) != 2 {
+		t.Errorf("drift error = %+v", driftErr)
+	}
+}
@@ -0,0 +1,953 @@
+package fake
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	serieA      = 135
+	coppaItalia = 137
+	season      = 2021
+)
+
+type venue struct {
+	ID       int
+	Name     string
+	Address  string
+	City     string
+	Capacity int
+	Surface  string
+}
+
+type coach struct {
+	ID        int
+	Firstname string
+	Lastname  string
+	Age       int
+	BirthDate string
+	Place     string
+	Country   string
+	Career    []career
+}
+
+type career struct {
+	TeamID int
+	Start  string
+	End    string
+}
+
+type team struct {
+	ID         int
+	Name       string
+	Code       string
+	Founded    int
+	Venue      venue
+	Attack     float64
+	Defence    float64
+	Formations []string
+	Primary    string
+	Number     string
+	Keeper     string
+	Coaches    []*coach
+	Players    []*player
+}
+
+func (t *team) logo() string {
+	return fmt.Sprintf("https://media.api-sports.io/football/teams/%d.png", t.ID)
+}
+
+// coachAt returns the coach in charge on date.
+func (t *team) coachAt(date time.Time) *coach {
+	day := date.Format("2006-01-02")
+	for _, c := range t.Coaches {
+		for _, job := range c.Career {
+			if job.TeamID == t.ID && job.Start <= day && (job.End == "" || day <= job.End) {
+				return c
+			}
+		}
+	}
+	return t.Coaches[len(t.Coaches)-1]
+}
+
+type player struct {
+	ID          int
+	Firstname   string
+	Lastname    string
+	Age         int
+	BirthDate   string
+	Place       string
+	Nationality string
+	Height      int
+	Weight      int
+	Number      int
+	Position    string // Goalkeeper, Defender, Midfielder, Attacker
+	Team        *team
+	Injuries    []injury
+	Transfers   []transfer
+	Trophies    []trophy
+}
+
+func (p *player) name() string {
+	return p.Firstname[:1] + ". " + p.Lastname
+}
+
+func (p *player) shortPos() string {
+	return p.Position[:1]
+}
+
+type injury struct {
+	Type   string
+	Reason string
+	Start  time.Time
+	End    time.Time
+}
+
+type transfer struct {
+	Date   string
+	Type   string
+	InID   int
+	InName string
+	OutID  int
+	Out    string
+}
+
+type trophy struct {
+	League  string
+	Country string
+	Season  string
+	Place   string
+}
+
+type league struct {
+	ID     int
+	Name   string
+	Type   string
+	Rounds []string
+}
+
+type event struct {
+	Elapsed  int
+	Extra    int
+	Team     *team
+	Player   *player
+	Assist   *player
+	Type     string
+	Detail   string
+	Comments string
+}
+
+// appearance is one player's match.
+type appearance struct {
+	Player        *player
+	Team          *team
+	Starter       bool
+	Grid          string
+	Minutes       int
+	Rating        float64
+	Captain       bool
+	Goals         int
+	Assists       int
+	Conceded      int
+	Saves         int
+	Shots         int
+	ShotsOn       int
+	Passes        int
+	KeyPasses     int
+	Accuracy      int
+	Tackles       int
+	Blocks        int
+	Interceptions int
+	Duels         int
+	DuelsWon      int
+	Dribbles      int
+	DribblesWon   int
+	Fouls         int
+	Drawn         int
+	Yellow        int
+	Red           int
+	PenScored     int
+	PenMissed     int
+}
+
+type sideStats struct {
+	ShotsOn     int
+	ShotsOff    int
+	Blocked     int
+	Fouls       int
+	Corners     int
+	Offsides    int
+	Possession  int
+	Yellow      int
+	Red         int
+	Saves       int
+	Passes      int
+	PassesOk    int
+	SubsOnBench int
+}
+
+type match struct {
+	ID        int
+	League    *league
+	Round     string
+	Date      time.Time
+	Home      *team
+	Away      *team
+	Referee   string
+	Status    string // FT, AET, PEN, NS, PST
+	Goals     [2]int
+	Halftime  [2]int
+	Extratime *[2]int
+	Penalty   *[2]int
+	Formation [2]string
+	Events    []event
+	Players   [2][]*appearance
+	Stats     [2]sideStats
+}
+
+func (m *match) played() bool {
+	return m.Status == "FT" || m.Status == "AET" || m.Status == "PEN"
+}
+
+func (m *match) side(teamId int) int {
+	if m.Away.ID == teamId {
+		return 1
+	}
+	return 0
+}
+
+func (m *match) team(side int) *team {
+	if side == 1 {
+		return m.Away
+	}
+	return m.Home
+}
+
+// winner is 0 for home, 1 for away and -1 for a draw or unplayed match.
+func (m *match) winner() int {
+	if !m.played() {
+		return -1
+	}
+	home, away := m.Goals[0], m.Goals[1]
+	if m.Penalty != nil {
+		home, away = m.Penalty[0], m.Penalty[1]
+	}
+	switch {
+	case home > away:
+		return 0
+	case away > home:
+		return 1
+	}
+	return -1
+}
+
+type dataset struct {
+	now     time.Time
+	leagues []*league
+	teams   []*team
+	players []*player
+	matches []*match
+}
+
+func (d *dataset) league(id int) *league {
+	for _, l := range d.leagues {
+		if l.ID == id {
+			return l
+		}
+	}
+	return nil
+}
+
+func (d *dataset) teamById(id int) *team {
+	for _, t := range d.teams {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func (d *dataset) playerById(id int) *player {
+	for _, p := range d.players {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (d *dataset) matchById(id int) *match {
+	for _, m := range d.matches {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+var teamSeeds = []struct {
+	id       int
+	name     string
+	code     string
+	founded  int
+	venue    venue
+	attack   float64
+	defence  float64
+	primary  string
+	number   string
+	keeper   string
+	coach    [2]string
+	formOpts []string
+}{
+	{505, "Inter", "INT", 1908, venue{907, "Stadio Giuseppe Meazza", "Via Piccolomini 5", "Milano", 80018, "grass"}, 1.45, 0.70, "0033a0", "ffffff", "ffcc00", [2]string{"Simone", "Inzaghi"}, []string{"3-5-2", "3-4-1-2"}},
+	{489, "AC Milan", "MIL", 1899, venue{907, "Stadio Giuseppe Meazza", "Via Piccolomini 5", "Milano", 80018, "grass"}, 1.30, 0.70, "fb090b", "000000", "2dd42d", [2]string{"Stefano", "Pioli"}, []string{"4-2-3-1", "4-3-3"}},
+	{492, "Napoli", "NAP", 1904, venue{943, "Stadio Diego Armando Maradona", "Via Giambattista Marino", "Napoli", 60240, "grass"}, 1.35, 0.72, "12a0d7", "ffffff", "000000", [2]string{"Luciano", "Spalletti"}, []string{"4-3-3", "4-2-3-1"}},
+	{496, "Juventus", "JUV", 1897, venue{909, "Allianz Stadium", "Corso Gaetano Scirea, 50", "Torino", 45666, "grass"}, 1.15, 0.75, "ffffff", "000000", "6a0dad", [2]string{"Massimiliano", "Allegri"}, []string{"4-4-2", "4-3-3"}},
+	{487, "Lazio", "LAZ", 1900, venue{910, "Stadio Olimpico", "Viale dei Gladiatori, 2", "Roma", 70634, "grass"}, 1.30, 1.00, "87d8f7", "000000", "ffd700", [2]string{"Maurizio", "Sarri"}, []string{"4-3-3"}},
+	{497, "AS Roma", "ROM", 1927, venue{910, "Stadio Olimpico", "Viale dei Gladiatori, 2", "Roma", 70634, "grass"}, 1.10, 0.85, "8e1f2f", "f0bc42", "00a651", [2]string{"José", "Mourinho"}, []string{"3-4-2-1", "4-2-3-1"}},
+	{502, "Fiorentina", "FIO", 1926, venue{902, "Stadio Artemio Franchi", "Viale Manfredo Fanti, 4", "Firenze", 47282, "grass"}, 1.15, 0.95, "482e92", "ffffff", "ffff00", [2]string{"Vincenzo", "Italiano"}, []string{"4-3-3"}},
+	{499, "Atalanta", "ATA", 1907, venue{12275, "Gewiss Stadium", "Viale Giulio Cesare, 18", "Bergamo", 21300, "grass"}, 1.25, 0.90, "1e71b8", "ffffff", "a0a0a0", [2]string{"Gian Piero", "Gasperini"}, []string{"3-4-2-1", "3-4-1-2"}},
+	{504, "Verona", "VER", 1903, venue{12276, "Stadio Marc'Antonio Bentegodi", "Piazzale Olimpia", "Verona", 39211, "grass"}, 1.10, 1.10, "fcd116", "002f6c", "00a651", [2]string{"Igor", "Tudor"}, []string{"3-4-2-1"}},
+	{503, "Torino", "TOR", 1906, venue{906, "Stadio Olimpico Grande Torino", "Via Filadelfia, 96/b", "Torino", 28177, "grass"}, 0.95, 0.90, "8b1a1a", "ffffff", "ffd700", [2]string{"Ivan", "Jurić"}, []string{"3-4-2-1"}},
+	{488, "Sassuolo", "SAS", 1920, venue{935, "Mapei Stadium - Città del Tricolore", "Piazza Azzuri d'Italia, 1", "Reggio Emilia", 23717, "grass"}, 1.15, 1.15, "00a651", "000000", "ffff00", [2]string{"Alessio", "Dionisi"}, []string{"4-2-3-1"}},
+	{494, "Udinese", "UDI", 1896, venue{12279, "Dacia Arena", "Piazzale Repubblica Argentina, 3", "Udine", 25144, "grass"}, 0.95, 1.05, "ffffff", "000000", "ff8c00", [2]string{"Gabriele", "Cioffi"}, []string{"3-5-2"}},
+	{500, "Bologna", "BOL", 1909, venue{12277, "Stadio Renato Dall'Ara", "Via Andrea Costa, 174", "Bologna", 38279, "grass"}, 0.95, 1.05, "1a2f48", "ffffff", "00a651", [2]string{"Siniša", "Mihajlović"}, []string{"3-4-2-1", "4-2-3-1"}},
+	{511, "Empoli", "EMP", 1920, venue{12280, "Stadio Carlo Castellani", "Viale dei Cappuccini, 24", "Empoli", 16800, "grass"}, 0.95, 1.20, "0055a4", "ffffff", "ffd700", [2]string{"Aurelio", "Andreazzoli"}, []string{"4-3-1-2"}},
+	{498, "Sampdoria", "SAM", 1946, venue{904, "Stadio Luigi Ferraris", "Via Giovanni de Prà, 1", "Genova", 36599, "grass"}, 0.90, 1.15, "1b5497", "ffffff", "ffd700", [2]string{"Roberto", "D'Aversa"}, []string{"4-4-2", "4-3-1-2"}},
+	{515, "Spezia", "SPE", 1906, venue{12281, "Stadio Alberto Picco", "Viale Nicolò Fieschi", "La Spezia", 10336, "grass"}, 0.85, 1.25, "ffffff", "000000", "00a651", [2]string{"Thiago", "Motta"}, []string{"4-3-3"}},
+	{514, "Salernitana", "SAL", 1919, venue{12282, "Stadio Arechi", "Via Salvador Allende", "Salerno", 37245, "grass"}, 0.75, 1.35, "8b1a1a", "ffffff", "ffd700", [2]string{"Davide", "Nicola"}, []string{"3-5-2"}},
+	{490, "Cagliari", "CAG", 1920, venue{12283, "Unipol Domus", "Via Raimondo Carta Raspi", "Cagliari", 16416, "grass"}, 0.85, 1.30, "b8122b", "ffffff", "00a651", [2]string{"Walter", "Mazzarri"}, []string{"3-5-2"}},
+	{495, "Genoa", "GEN", 1893, venue{904, "Stadio Luigi Ferraris", "Via Giovanni de Prà, 1", "Genova", 36599, "grass"}, 0.75, 1.20, "c8102e", "ffffff", "ffd700", [2]string{"Alexander", "Blessin"}, []string{"4-2-3-1", "3-5-2"}},
+	{517, "Venezia", "VEN", 1907, venue{12284, "Stadio Pier Luigi Penzo", "Sestiere Castello", "Venezia", 11150, "grass"}, 0.80, 1.30, "000000", "ff6600", "00a651", [2]string{"Paolo", "Zanetti"}, []string{"4-3-3"}},
+}
+
+// knownPlayers keeps the ids used in examples and comments around the app.
+var knownPlayers = map[int][]struct {
+	id        int
+	firstname string
+	lastname  string
+	position  string
+	number    int
+}{
+	505: {
+		{198, "Milan", "Škriniar", "Defender", 37},
+		{217, "Lautaro Javier", "Martínez", "Attacker", 10},
+		{30530, "Marcelo", "Brozović", "Midfielder", 77},
+	},
+}
+
+var firstnames = []string{"Alessandro", "Andrea", "Antonio", "Davide", "Federico", "Francesco", "Gianluca", "Giorgio", "Giovanni", "Lorenzo", "Luca", "Marco", "Matteo", "Mattia", "Nicolò", "Pietro", "Riccardo", "Simone", "Stefano", "Tommaso", "Dušan", "Rafael", "Theo", "Victor", "Sergej", "Kalidou", "Hakan", "Ciro", "Paulo", "Dries"}
+var lastnames = []string{"Rossi", "Russo", "Ferrari", "Esposito", "Bianchi", "Romano", "Colombo", "Ricci", "Marino", "Greco", "Bruno", "Gallo", "Conti", "De Luca", "Mancini", "Costa", "Giordano", "Rizzo", "Lombardi", "Moretti", "Barbieri", "Fontana", "Santoro", "Mariani", "Rinaldi", "Caruso", "Ferrara", "Galli", "Martini", "Leone", "Longo", "Gentile", "Martinelli", "Vitale", "Serra", "Coppola", "De Santis", "D'Angelo", "Marchetti", "Parisi"}
+var nationalities = []string{"Italy", "Italy", "Italy", "Argentina", "Brazil", "France", "Croatia", "Serbia", "Netherlands", "Spain", "Portugal", "Belgium"}
+var referees = []string{"D. Orsato", "M. Guida", "D. Doveri", "M. Mariani", "F. Maresca", "G. Valeri", "L. Massa", "P. Valeri", "M. Di Bello", "A. Irrati"}
+
+// newDataset builds a deterministic Serie A and Coppa Italia 2021 season.
+// Matches kicking off before now are played, later ones are not started.
+func newDataset(seed int64, now time.Time) *dataset {
+	rnd := rand.New(rand.NewSource(seed))
+	d := &dataset{now: now}
+
+	regular := &league{ID: serieA, Name: "Serie A", Type: "League"}
+	for round := 1; round <= 38; round++ {
+		regular.Rounds = append(regular.Rounds, "Regular Season - "+strconv.Itoa(round))
+	}
+	cup := &league{ID: coppaItalia, Name: "Coppa Italia", Type: "Cup", Rounds: []string{"Round of 16", "Quarter-finals", "Semi-finals", "Final"}}
+	d.leagues = []*league{regular, cup}
+
+	coachId := 2400
+	for i, seed := range teamSeeds {
+		t := &team{
+			ID: seed.id, Name: seed.name, Code: seed.code, Founded: seed.founded, Venue: seed.venue,
+			Attack: seed.attack, Defence: seed.defence, Formations: seed.formOpts,
+			Primary: seed.primary, Number: seed.number, Keeper: seed.keeper,
+		}
+		coachId++
+		c := &coach{
+			ID: coachId, Firstname: seed.coach[0], Lastname: seed.coach[1], Age: 40 + rnd.Intn(25),
+			BirthDate: fmt.Sprintf("%d-%02d-%02d", 1955+rnd.Intn(25), 1+rnd.Intn(12), 1+rnd.Intn(28)),
+			Place:     "Italy", Country: "Italy",
+		}
+		c.Career = []career{{TeamID: t.ID, Start: "2021-07-01"}}
+		t.Coaches = []*coach{c}
+		// Two clubs change coach in January so the tactical history has a break in it.
+		if i == 14 || i == 18 {
+			c.Career[0].End = "2022-01-16"
+			coachId++
+			next := &coach{
+				ID: coachId, Firstname: firstnames[rnd.Intn(20)], Lastname: lastnames[rnd.Intn(len(lastnames))], Age: 40 + rnd.Intn(20),
+				BirthDate: fmt.Sprintf("%d-%02d-%02d", 1965+rnd.Intn(15), 1+rnd.Intn(12), 1+rnd.Intn(28)),
+				Place:     "Italy", Country: "Italy",
+				Career: []career{{TeamID: t.ID, Start: "2022-01-17"}},
+			}
+			t.Coaches = append(t.Coaches, next)
+			t.Formations = []string{t.Formations[0], "3-5-2"}
+		}
+		d.teams = append(d.teams, t)
+	}
+
+	for i, t := range d.teams {
+		d.buildSquad(rnd, t, i)
+	}
+
+	d.buildSerieA(rnd, regular)
+	d.buildCoppaItalia(rnd, cup)
+	sort.SliceStable(d.matches, func(i, j int) bool { return d.matches[i].Date.Before(d.matches[j].Date) })
+	for _, m := range d.matches {
+		if m.played() {
+			d.play(rnd, m)
+		}
+	}
+	return d
+}
+
+func (d *dataset) buildSquad(rnd *rand.Rand, t *team, index int) {
+	layout := []struct {
+		position string
+		count    int
+	}{{"Goalkeeper", 3}, {"Defender", 7}, {"Midfielder", 7}, {"Attacker", 5}}
+
+	known := knownPlayers[t.ID]
+	used := map[int]bool{}
+	for _, k := range known {
+		used[k.number] = true
+	}
+	nextNumber := func(position string) int {
+		start := map[string]int{"Goalkeeper": 1, "Defender": 2, "Midfielder": 4, "Attacker": 7}[position]
+		for n := start; ; n++ {
+			if !used[n] {
+				used[n] = true
+				return n
+			}
+		}
+	}
+
+	id := 100000 + index*100
+	for _, slot := range layout {
+		count := slot.count
+		for _, k := range known {
+			if k.position == slot.position {
+				p := d.newPlayer(rnd, t, k.id, k.firstname, k.lastname, k.position, k.number)
+				t.Players = append(t.Players, p)
+				count--
+			}
+		}
+		for i := 0; i < count; i++ {
+			id++
+			p := d.newPlayer(rnd, t, id, firstnames[rnd.Intn(len(firstnames))], lastnames[rnd.Intn(len(lastnames))], slot.position, nextNumber(slot.position))
+			t.Players = append(t.Players, p)
+		}
+	}
+}
+
+func (d *dataset) newPlayer(rnd *rand.Rand, t *team, id int, firstname string, lastname string, position string, number int) *player {
+	age := 19 + rnd.Intn(16)
+	p := &player{
+		ID: id, Firstname: firstname, Lastname: lastname, Age: age,
+		BirthDate:   fmt.Sprintf("%d-%02d-%02d", 2021-age, 1+rnd.Intn(12), 1+rnd.Intn(28)),
+		Nationality: nationalities[rnd.Intn(len(nationalities))],
+		Height:      172 + rnd.Intn(22), Weight: 66 + rnd.Intn(20),
+		Number: number, Position: position, Team: t,
+	}
+	p.Place = map[string]string{"Italy": "Roma", "Argentina": "Buenos Aires", "Brazil": "São Paulo", "France": "Paris", "Croatia": "Zagreb", "Serbia": "Beograd", "Netherlands": "Amsterdam", "Spain": "Madrid", "Portugal": "Lisboa", "Belgium": "Bruxelles"}[p.Nationality]
+
+	if rnd.Float64() < 0.25 {
+		start := time.Date(2021, time.Month(8+rnd.Intn(8)), 1+rnd.Intn(28), 0, 0, 0, 0, time.UTC)
+		reason := []string{"Muscle Injury", "Knee Injury", "Ankle Injury", "Hamstring Injury", "Illness"}[rnd.Intn(5)]
+		p.Injuries = append(p.Injuries, injury{Type: "Missing Fixture", Reason: reason, Start: start, End: start.AddDate(0, 0, 7+rnd.Intn(35))})
+	}
+	if rnd.Float64() < 0.5 {
+		from := d.teams[rnd.Intn(len(d.teams))]
+		if from != t {
+			fee := []string{"Free", "Loan", fmt.Sprintf("€ %.1fM", 1+rnd.Float64()*40)}[rnd.Intn(3)]
+			p.Transfers = append(p.Transfers, transfer{
+				Date: fmt.Sprintf("%d-07-01", 2016+rnd.Intn(6)), Type: fee,
+				InID: t.ID, InName: t.Name, OutID: from.ID, Out: from.Name,
+			})
+		}
+	}
+	if t.ID == 505 || t.ID == 489 {
+		p.Trophies = append(p.Trophies, trophy{League: "Serie A", Country: "Italy", Season: map[int]string{505: "2020/2021", 489: "2010/2011"}[t.ID], Place: "Winner"})
+	}
+	d.players = append(d.players, p)
+	return p
+}
+
+func kickoff(date time.Time, slot int) time.Time {
+	slots := []struct {
+		day    int
+		hour   int
+		minute int
+	}{{0, 14, 0}, {0, 17, 0}, {0, 19, 45}, {1, 12, 30}, {1, 15, 0}, {1, 15, 0}, {1, 18, 0}, {1, 20, 45}, {2, 18, 30}, {2, 20, 45}}
+	s := slots[slot%len(slots)]
+	return time.Date(date.Year(), date.Month(), date.Day()+s.day, s.hour, s.minute, 0, 0, time.UTC)
+}
+
+// buildSerieA schedules a double round robin with the circle method, one
+// round per week from the last Saturday of August.
+func (d *dataset) buildSerieA(rnd *rand.Rand, l *league) {
+	n := len(d.teams)
+	order := make([]*team, n)
+	copy(order, d.teams)
+	rnd.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+	rounds := make([][][2]*team, 0, 2*(n-1))
+	for r := 0; r < n-1; r++ {
+		var pairs [][2]*team
+		for i := 0; i < n/2; i++ {
+			home, away := order[i], order[n-1-i]
+			if (r+i)%2 == 1 {
+				home, away = away, home
+			}
+			pairs = append(pairs, [2]*team{home, away})
+		}
+		rounds = append(rounds, pairs)
+		order = append([]*team{order[0], order[n-1]}, order[1:n-1]...)
+	}
+	for r := 0; r < n-1; r++ {
+		var pairs [][2]*team
+		for _, pair := range rounds[r] {
+			pairs = append(pairs, [2]*team{pair[1], pair[0]})
+		}
+		rounds = append(rounds, pairs)
+	}
+
+	saturday := time.Date(2021, 8, 21, 0, 0, 0, 0, time.UTC)
+	id := 731698
+	for r, pairs := range rounds {
+		week := saturday.AddDate(0, 0, 7*r)
+		if r >= 19 {
+			week = week.AddDate(0, 0, 14) // winter break
+		}
+		for i, pair := range pairs {
+			m := &match{
+				ID: id, League: l, Round: l.Rounds[r], Date: kickoff(week, i),
+				Home: pair[0], Away: pair[1], Referee: referees[rnd.Intn(len(referees))] + ", Italy",
+			}
+			id++
+			d.schedule(rnd, m)
+			d.matches = append(d.matches, m)
+		}
+	}
+}
+
+// buildCoppaItalia plays a single leg knockout between the first 16 clubs.
+func (d *dataset) buildCoppaItalia(rnd *rand.Rand, l *league) {
+	dates := []time.Time{
+		time.Date(2022, 1, 18, 20, 0, 0, 0, time.UTC),
+		time.Date(2022, 2, 8, 20, 0, 0, 0, time.UTC),
+		time.Date(2022, 3, 1, 20, 0, 0, 0, time.UTC),
+		time.Date(2022, 5, 11, 19, 0, 0, 0, time.UTC),
+	}
+	alive := make([]*team, 16)
+	copy(alive, d.teams[:16])
+	rnd.Shuffle(len(alive), func(i, j int) { alive[i], alive[j] = alive[j], alive[i] })
+
+	id := 790001
+	for r, round := range l.Rounds {
+		var next []*team
+		for i := 0; i+1 < len(alive); i += 2 {
+			m := &match{
+				ID: id, League: l, Round: round, Date: dates[r].Add(time.Duration(i) * 3 * time.Hour / 2),
+				Home: alive[i], Away: alive[i+1], Referee: referees[rnd.Intn(len(referees))] + ", Italy",
+			}
+			id++
+			d.schedule(rnd, m)
+			d.matches = append(d.matches, m)
+			if !m.played() {
+				continue
+			}
+			next = append(next, m.team(m.winner()))
+		}
+		if len(next) == 0 {
+			break
+		}
+		alive = next
+	}
+}
+
+// schedule decides the status and final score of m.
+func (d *dataset) schedule(rnd *rand.Rand, m *match) {
+	if !m.Date.Before(d.now) {
+		m.Status = "NS"
+		if rnd.Float64() < 0.01 {
+			m.Status = "PST"
+		}
+		return
+	}
+	if m.League.ID == serieA && rnd.Float64() < 0.005 {
+		m.Status = "PST"
+		return
+	}
+
+	m.Status = "FT"
+	home := 1.45 * m.Home.Attack * m.Away.Defence
+	away := 1.15 * m.Away.Attack * m.Home.Defence
+	m.Goals = [2]int{poisson(rnd, home), poisson(rnd, away)}
+	for side := 0; side < 2; side++ {
+		m.Halftime[side] = binomial(rnd, m.Goals[side], 0.45)
+	}
+	m.Formation = [2]string{pickFormation(rnd, m.Home, m.Date), pickFormation(rnd, m.Away, m.Date)}
+
+	if m.League.Type == "Cup" && m.Goals[0] == m.Goals[1] {
+		extra := [2]int{poisson(rnd, home/3), poisson(rnd, away/3)}
+		m.Extratime = &extra
+		m.Goals[0] += extra[0]
+		m.Goals[1] += extra[1]
+		m.Status = "AET"
+		if extra[0] == extra[1] {
+			m.Status = "PEN"
+			penalty := [2]int{3 + rnd.Intn(3), 0}
+			penalty[1] = penalty[0] - 1 + 2*rnd.Intn(2)
+			if penalty[1] < 0 {
+				penalty[1] = penalty[0] + 1
+			}
+			m.Penalty = &penalty
+		}
+	}
+}
+
+func pickFormation(rnd *rand.Rand, t *team, date time.Time) string {
+	if len(t.Coaches) > 1 && t.coachAt(date) == t.Coaches[1] {
+		return t.Formations[len(t.Formations)-1]
+	}
+	if len(t.Formations) > 1 && rnd.Float64() < 0.2 {
+		return t.Formations[1]
+	}
+	return t.Formations[0]
+}
+
+// play fills in lineups, events, player and team statistics for a finished match.
+func (d *dataset) play(rnd *rand.Rand, m *match) {
+	length := 90
+	if m.Extratime != nil {
+		length = 120
+	}
+
+	for side := 0; side < 2; side++ {
+		t := m.team(side)
+		starters, bench := lineup(rnd, t, m.Formation[side], m.Date)
+		var apps []*appearance
+		for i, p := range starters {
+			apps = append(apps, &appearance{Player: p, Team: t, Starter: true, Grid: gridFor(m.Formation[side], i), Minutes: length, Captain: i == 1})
+		}
+		subs := 0
+		for _, p := range bench {
+			app := &appearance{Player: p, Team: t}
+			if subs < 3 && p.Position != "Goalkeeper" && rnd.Float64() < 0.5 {
+				minute := 55 + rnd.Intn(30)
+				// Replace an outfield starter of the same position if possible.
+				for _, out := range apps[1:11] {
+					if out.Minutes == length && out.Player.Position == p.Position {
+						out.Minutes = minute
+						app.Minutes = length - minute
+						subs++
+						m.Events = append(m.Events, event{Elapsed: minute, Team: t, Player: out.Player, Assist: p, Type: "subst", Detail: "Substitution " + strconv.Itoa(subs)})
+						break
+					}
+				}
+			}
+			apps = append(apps, app)
+		}
+		m.Players[side] = apps
+	}
+
+	for side := 0; side < 2; side++ {
+		t := m.team(side)
+		apps := m.Players[side]
+		for g := 0; g < m.Goals[side]; g++ {
+			minute, extra := goalMinute(rnd, m, side, g)
+			scorer := pickWeighted(rnd, apps, minute, map[string]float64{"Attacker": 5, "Midfielder": 2, "Defender": 0.6, "Goalkeeper": 0})
+			event := event{Elapsed: minute, Extra: extra, Team: t, Player: scorer.Player, Type: "Goal", Detail: "Normal Goal"}
+			scorer.Goals++
+			switch roll := rnd.Float64(); {
+			case roll < 0.1:
+				event.Detail = "Penalty"
+				scorer.PenScored++
+			case roll < 0.13:
+				event.Detail = "Own Goal"
+				opponent := pickWeighted(rnd, m.Players[1-side], minute, map[string]float64{"Defender": 3, "Midfielder": 1, "Goalkeeper": 0.3})
+				event.Player = opponent.Player
+				scorer.Goals--
+			default:
+				if rnd.Float64() < 0.7 {
+					assist := pickWeighted(rnd, apps, minute, map[string]float64{"Attacker": 2, "Midfielder": 3, "Defender": 1})
+					if assist != scorer {
+						assist.Assists++
+						event.Assist = assist.Player
+					}
+				}
+			}
+			m.Events = append(m.Events, event)
+		}
+		if rnd.Float64() < 0.04 {
+			missed := pickWeighted(rnd, apps, 60, map[string]float64{"Attacker": 4, "Midfielder": 1})
+			missed.PenMissed++
+			m.Events = append(m.Events, event{Elapsed: 10 + rnd.Intn(80), Team: t, Player: missed.Player, Type: "Goal", Detail: "Missed Penalty"})
+		}
+
+		yellows := poisson(rnd, 2.1)
+		for i := 0; i < yellows; i++ {
+			minute := 5 + rnd.Intn(length-5)
+			booked := pickWeighted(rnd, apps, minute, map[string]float64{"Defender": 3, "Midfielder": 3, "Attacker": 1, "Goalkeeper": 0.2})
+			booked.Yellow++
+			m.Stats[side].Yellow++
+			m.Events = append(m.Events, event{Elapsed: minute, Team: t, Player: booked.Player, Type: "Card", Detail: "Yellow Card", Comments: "Foul"})
+		}
+		if rnd.Float64() < 0.05 {
+			minute := 20 + rnd.Intn(length-20)
+			sent := pickWeighted(rnd, apps, minute, map[string]float64{"Defender": 3, "Midfielder": 2, "Attacker": 1})
+			sent.Red++
+			m.Stats[side].Red++
+			m.Events = append(m.Events, event{Elapsed: minute, Team: t, Player: sent.Player, Type: "Card", Detail: "Red Card", Comments: "Professional foul"})
+		}
+	}
+	sort.SliceStable(m.Events, func(i, j int) bool {
+		return m.Events[i].Elapsed*10+m.Events[i].Extra < m.Events[j].Elapsed*10+m.Events[j].Extra
+	})
+
+	possession := 50 + int(20*(m.Home.Attack-m.Away.Attack)) + rnd.Intn(11) - 5
+	if possession < 30 {
+		possession = 30
+	}
+	if possession > 70 {
+		possession = 70
+	}
+	for side := 0; side < 2; side++ {
+		s := &m.Stats[side]
+		s.Possession = possession
+		if side == 1 {
+			s.Possession = 100 - possession
+		}
+		s.ShotsOn = m.Goals[side] + poisson(rnd, 2.5)
+		s.ShotsOff = poisson(rnd, 5)
+		s.Blocked = poisson(rnd, 3)
+		s.Fouls = poisson(rnd, 12)
+		s.Corners = poisson(rnd, 5)
+		s.Offsides = poisson(rnd, 2)
+		s.Passes = s.Possession*10 + rnd.Intn(80)
+		s.PassesOk = s.Passes * (75 + rnd.Intn(15)) / 100
+	}
+	for side := 0; side < 2; side++ {
+		m.Stats[side].Saves = m.Stats[1-side].ShotsOn - m.Goals[1-side]
+		if m.Stats[side].Saves < 0 {
+			m.Stats[side].Saves = 0
+		}
+	}
+
+	for side := 0; side < 2; side++ {
+		s := m.Stats[side]
+		for _, app := range m.Players[side] {
+			if app.Minutes == 0 {
+				continue
+			}
+			share := float64(app.Minutes) / 90 / 11
+			app.Passes = int(float64(s.Passes) * share * (0.6 + rnd.Float64()*0.8))
+			app.Accuracy = s.PassesOk * 100 / s.Passes
+			app.KeyPasses = poisson(rnd, map[string]float64{"Attacker": 1, "Midfielder": 1.2, "Defender": 0.4, "Goalkeeper": 0}[app.Player.Position])
+			app.Fouls = poisson(rnd, 1.1*share*11)
+			app.Drawn = poisson(rnd, 1.1*share*11)
+			app.Duels = poisson(rnd, 9*share*11)
+			app.DuelsWon = binomial(rnd, app.Duels, 0.5)
+			switch app.Player.Position {
+			case "Goalkeeper":
+				app.Saves = s.Saves
+				app.Conceded = m.Goals[1-side]
+				app.Passes = 20 + rnd.Intn(20)
+			case "Defender":
+				app.Tackles = poisson(rnd, 2.2*share*11)
+				app.Interceptions = poisson(rnd, 1.5*share*11)
+				app.Blocks = poisson(rnd, 0.6*share*11)
+			case "Midfielder":
+				app.Tackles = poisson(rnd, 1.8*share*11)
+				app.Interceptions = poisson(rnd, 0.9*share*11)
+				app.Dribbles = poisson(rnd, 1.5*share*11)
+			case "Attacker":
+				app.Tackles = poisson(rnd, 0.5*share*11)
+				app.Dribbles = poisson(rnd, 2.5*share*11)
+			}
+			app.DribblesWon = binomial(rnd, app.Dribbles, 0.55)
+			app.ShotsOn = app.Goals + poisson(rnd, map[string]float64{"Attacker": 0.8, "Midfielder": 0.3, "Defender": 0.1, "Goalkeeper": 0}[app.Player.Position]*share*11)
+			app.Shots = app.ShotsOn + poisson(rnd, map[string]float64{"Attacker": 1.2, "Midfielder": 0.6, "Defender": 0.2, "Goalkeeper": 0}[app.Player.Position]*share*11)
+
+			rating := 6.4 + 0.9*float64(app.Goals) + 0.6*float64(app.Assists) + 0.15*float64(app.KeyPasses) - 1.2*float64(app.Red) - 0.1*float64(app.Yellow) + rnd.NormFloat64()*0.35
+			if m.winner() == side {
+				rating += 0.3
+			} else if m.winner() == 1-side {
+				rating -= 0.3
+			}
+			if app.Player.Position == "Goalkeeper" || app.Player.Position == "Defender" {
+				rating -= 0.2 * float64(m.Goals[1-side])
+				rating += 0.1 * float64(app.Saves)
+			}
+			app.Rating = math.Max(4.5, math.Min(9.8, rating))
+		}
+	}
+}
+
+// lineup picks eleven available players for the formation plus seven substitutes.
+func lineup(rnd *rand.Rand, t *team, formation string, date time.Time) ([]*player, []*player) {
+	available := map[string][]*player{}
+	for _, p := range t.Players {
+		out := false
+		for _, inj := range p.Injuries {
+			if !date.Before(inj.Start) && date.Before(inj.End) {
+				out = true
+			}
+		}
+		if !out {
+			available[p.Position] = append(available[p.Position], p)
+		}
+	}
+	for _, list := range available {
+		// Regulars first, with some rotation.
+		rnd.Shuffle(len(list), func(i, j int) {
+			if rnd.Float64() < 0.25 {
+				list[i], list[j] = list[j], list[i]
+			}
+		})
+	}
+
+	rows := formationRows(formation)
+	need := map[string]int{"Goalkeeper": 1, "Defender": rows[0], "Attacker": rows[len(rows)-1]}
+	for _, n := range rows[1 : len(rows)-1] {
+		need["Midfielder"] += n
+	}
+
+	var starters, rest []*player
+	take := func(position string, n int) {
+		list := available[position]
+		for i := 0; i < len(list); i++ {
+			if i < n {
+				starters = append(starters, list[i])
+			} else {
+				rest = append(rest, list[i])
+			}
+		}
+		// Fill shortages from the neighbouring line.
+		for i := len(list); i < n; i++ {
+			fallback := map[string]string{"Defender": "Midfielder", "Midfielder": "Defender", "Attacker": "Midfielder"}[position]
+			for _, p := range available[fallback] {
+				if !contains(starters, p) {
+					starters = append(starters, p)
+					break
+				}
+			}
+		}
+	}
+	take("Goalkeeper", 1)
+	take("Defender", need["Defender"])
+	take("Midfielder", need["Midfielder"])
+	take("Attacker", need["Attacker"])
+
+	var bench []*player
+	for _, p := range rest {
+		if len(bench) < 7 && !contains(starters, p) {
+			bench = append(bench, p)
+		}
+	}
+	return starters, bench
+}
+
+func contains(list []*player, p *player) bool {
+	for _, q := range list {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
+
+func formationRows(formation string) []int {
+	var rows []int
+	for _, part := range strings.Split(formation, "-") {
+		n, _ := strconv.Atoi(part)
+		rows = append(rows, n)
+	}
+	return rows
+}
+
+// gridFor returns the "row:column" pitch position of the i-th starter,
+// the way the lineups endpoint reports it (goalkeeper is 1:1).
+func gridFor(formation string, i int) string {
+	if i == 0 {
+		return "1:1"
+	}
+	i--
+	for row, n := range formationRows(formation) {
+		if i < n {
+			return fmt.Sprintf("%d:%d", row+2, n-i)
+		}
+		i -= n
+	}
+	return ""
+}
+
+func goalMinute(rnd *rand.Rand, m *match, side int, goal int) (int, int) {
+	if goal < m.Halftime[side] {
+		minute := 1 + rnd.Intn(45)
+		if minute == 45 && rnd.Float64() < 0.5 {
+			return 45, 1 + rnd.Intn(4)
+		}
+		return minute, 0
+	}
+	if m.Extratime != nil && goal >= m.Goals[side]-m.Extratime[side] {
+		return 91 + rnd.Intn(30), 0
+	}
+	minute := 46 + rnd.Intn(45)
+	if minute == 90 && rnd.Float64() < 0.6 {
+		return 90, 1 + rnd.Intn(6)
+	}
+	return minute, 0
+}
+
+// pickWeighted picks a player on the pitch at minute, weighted by position.
+func pickWeighted(rnd *rand.Rand, apps []*appearance, minute int, weights map[string]float64) *appearance {
+	var pool []*appearance
+	var total float64
+	for _, app := range apps {
+		if app.Minutes == 0 || weights[app.Player.Position] == 0 {
+			continue
+		}
+		pool = append(pool, app)
+		total += weights[app.Player.Position]
+	}
+	if len(pool) == 0 {
+		return apps[0]
+	}
+	x := rnd.Float64() * total
+	for _, app := range pool {
+		x -= weights[app.Player.Position]
+		if x <= 0 {
+			return app
+		}
+	}
+	return pool[len(pool)-1]
+}
+
+func poisson(rnd *rand.Rand, lambda float64) int {
+	l := math.Exp(-lambda)
+	k := 0
+	p := 1.0
+	for {
+		p *= rnd.Float64()
+		if p <= l {
+			return k
+		}
+		k++
+	}
+}
+
+func binomial(rnd *rand.Rand, n int, p float64) int {
+	k := 0
+	for i := 0; i < n; i++ {
+		if rnd.Float64() < p {
+			k++
+		}
+	}
+	return k
+}
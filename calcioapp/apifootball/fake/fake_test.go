@@ -0,0 +1,117 @@
+package fake_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+)
+
+func newClient(t *testing.T, opts fake.Options) (*apifootball.APIClient, *fake.Server) {
+	server := fake.New(opts)
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	return apifootball.New("test-token", ts.URL+"/"), server
+}
+
+func TestStandingsAreConsistentWithFixtures(t *testing.T) {
+	client, _ := newClient(t, fake.Options{})
+
+	standings, err := client.GetStandingsByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if standings.Results != 1 || len(standings.Response[0].League.Standings[0]) != 20 {
+		t.Fatalf("expected one table of 20 teams, got %+v", standings.Response)
+	}
+	table := standings.Response[0].League.Standings[0]
+	for i, row := range table {
+		if row.Rank != i+1 {
+			t.Errorf("row %d has rank %d", i, row.Rank)
+		}
+		if row.Points != 3*row.All.Win+row.All.Draw {
+			t.Errorf("%s: points %d do not match the record", row.Team.Name, row.Points)
+		}
+		if i > 0 && row.Points > table[i-1].Points {
+			t.Errorf("%s is ranked below a team with fewer points", row.Team.Name)
+		}
+	}
+
+	fixtures, err := client.GetFixturesByLeagueIdAndTeamId("135", "505")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fixtures.Results != 38 {
+		t.Errorf("Inter plays %d league fixtures, want 38", fixtures.Results)
+	}
+}
+
+func TestPlayersArePaged(t *testing.T) {
+	client, _ := newClient(t, fake.Options{})
+
+	players, err := client.GetPlayersByLeagueIdAndTeamId("135", "505")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if players.Paging.Current != 1 || players.Paging.Total != 2 || players.Results != 20 {
+		t.Errorf("paging = %+v with %d results, want page 1 of 2 with 20 results", players.Paging, players.Results)
+	}
+}
+
+func TestQuotaExhaustion(t *testing.T) {
+	client, server := newClient(t, fake.Options{DailyLimit: 2})
+
+	for i := 0; i < 2; i++ {
+		if leagues, err := client.GetLeagues(); err != nil || leagues.Results == 0 {
+			t.Fatalf("request %d: %v, %d results", i, err, leagues.Results)
+		}
+	}
+	leagues, err := client.GetLeagues()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leagues.Results != 0 || leagues.ErrorMessages()["requests"] == "" {
+		t.Errorf("expected a requests error once the quota is used, got %s", leagues.Errors)
+	}
+
+	status, err := client.GetStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Response.Requests.Current != 2 || server.Used() != 2 {
+		t.Errorf("status reports %d requests used, want 2", status.Response.Requests.Current)
+	}
+}
+
+func TestInjectedFailures(t *testing.T) {
+	client, server := newClient(t, fake.Options{})
+	server.FailNext("standings", http.StatusServiceUnavailable, 1)
+
+	if _, err := client.GetStandingsByLeagueId("135"); err == nil {
+		t.Error("expected the injected failure to surface as an error")
+	}
+	if _, err := client.GetStandingsByLeagueId("135"); err != nil {
+		t.Errorf("only the next request should fail: %v", err)
+	}
+}
+
+func TestUnknownParameter(t *testing.T) {
+	ts := httptest.NewServer(fake.New(fake.Options{}))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/standings?season=2021&leage=135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var standings apifootball.Standings
+	if err := json.NewDecoder(resp.Body).Decode(&standings); err != nil {
+		t.Fatal(err)
+	}
+	if standings.Results != 0 || standings.ErrorMessages()["leage"] == "" {
+		t.Errorf("expected an error for the misspelled parameter, got %s", standings.Errors)
+	}
+}
@@ -0,0 +1,1396 @@
+package fake
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const media = "https://media.api-sports.io/football"
+
+var buckets = []struct {
+	key      string
+	from, to int
+}{{"0-15", 0, 15}, {"16-30", 16, 30}, {"31-45", 31, 45}, {"46-60", 46, 60}, {"61-75", 61, 75}, {"76-90", 76, 90}, {"91-105", 91, 105}, {"106-120", 106, 120}}
+
+func teamRef(t *team) M {
+	return M{"id": t.ID, "name": t.Name, "logo": t.logo()}
+}
+
+func leagueRef(l *league) M {
+	flag := "https://media.api-sports.io/flags/it.svg"
+	return M{"id": l.ID, "name": l.Name, "country": "Italy", "logo": fmt.Sprintf("%s/leagues/%d.png", media, l.ID), "flag": flag, "season": season}
+}
+
+func playerRef(p *player) M {
+	if p == nil {
+		return M{"id": nil, "name": nil}
+	}
+	return M{"id": p.ID, "name": p.name()}
+}
+
+func nullable(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+func percent(part int, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.2f%%", 100*float64(part)/float64(total))
+}
+
+func (s *Server) status(q query) result {
+	s.mu.Lock()
+	used, limit := s.used, s.opts.DailyLimit
+	s.mu.Unlock()
+	if limit < 0 {
+		limit = 7500
+	}
+	return result{response: M{
+		"account":      M{"firstname": "Local", "lastname": "Developer", "email": "dev@localhost"},
+		"subscription": M{"plan": "Free", "end": s.opts.Now.AddDate(0, 3, 0).Format(time.RFC3339), "active": true},
+		"requests":     M{"current": used, "limit_day": limit},
+	}}
+}
+
+func (s *Server) leagues(q query) result {
+	var response []M
+	for _, l := range s.data.leagues {
+		if q.has("id") && q.int("id") != l.ID ||
+			q.has("name") && !strings.EqualFold(q.get("name"), l.Name) ||
+			q.has("country") && !strings.EqualFold(q.get("country"), "Italy") ||
+			q.has("code") && !strings.EqualFold(q.get("code"), "IT") ||
+			q.has("type") && !strings.EqualFold(q.get("type"), l.Type) ||
+			!q.matchesSeason() {
+			continue
+		}
+		if q.has("team") {
+			found := false
+			for _, m := range s.data.matches {
+				if m.League == l && (m.Home.ID == q.int("team") || m.Away.ID == q.int("team")) {
+					found = true
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+		response = append(response, M{
+			"league":  M{"id": l.ID, "name": l.Name, "type": l.Type, "logo": fmt.Sprintf("%s/leagues/%d.png", media, l.ID)},
+			"country": M{"name": "Italy", "code": "IT", "flag": "https://media.api-sports.io/flags/it.svg"},
+			"seasons": []M{{
+				"year": season, "start": "2021-08-21", "end": "2022-05-22", "current": true,
+				"coverage": M{
+					"fixtures":  M{"events": true, "lineups": true, "statistics_fixtures": true, "statistics_players": true},
+					"standings": l.Type == "League", "players": true, "top_scorers": true, "top_assists": true, "top_cards": true,
+					"injuries": true, "predictions": true, "odds": false,
+				},
+			}},
+		})
+	}
+	return result{response: response}
+}
+
+func (s *Server) teamsIn(leagueId int) []*team {
+	seen := map[int]bool{}
+	for _, m := range s.data.matches {
+		if leagueId == 0 || m.League.ID == leagueId {
+			seen[m.Home.ID] = true
+			seen[m.Away.ID] = true
+		}
+	}
+	var teams []*team
+	for _, t := range s.data.teams {
+		if seen[t.ID] {
+			teams = append(teams, t)
+		}
+	}
+	return teams
+}
+
+func venueJson(v venue) M {
+	return M{"id": v.ID, "name": v.Name, "address": v.Address, "city": v.City, "country": "Italy", "capacity": v.Capacity, "surface": v.Surface, "image": fmt.Sprintf("%s/venues/%d.png", media, v.ID)}
+}
+
+func (s *Server) teams(q query) result {
+	if !q.matchesSeason() {
+		return result{}
+	}
+	var response []M
+	for _, t := range s.teamsIn(q.int("league")) {
+		if q.has("id") && q.int("id") != t.ID ||
+			q.has("name") && !strings.EqualFold(q.get("name"), t.Name) ||
+			q.has("search") && !strings.Contains(strings.ToLower(t.Name), strings.ToLower(q.get("search"))) ||
+			q.has("code") && !strings.EqualFold(q.get("code"), t.Code) ||
+			q.has("venue") && q.int("venue") != t.Venue.ID {
+			continue
+		}
+		response = append(response, M{
+			"team":  M{"id": t.ID, "name": t.Name, "code": t.Code, "country": "Italy", "founded": t.Founded, "national": false, "logo": t.logo()},
+			"venue": venueJson(t.Venue),
+		})
+	}
+	return result{response: response}
+}
+
+func (s *Server) venues(q query) result {
+	seen := map[int]bool{}
+	var response []M
+	for _, t := range s.data.teams {
+		v := t.Venue
+		if seen[v.ID] ||
+			q.has("id") && q.int("id") != v.ID ||
+			q.has("name") && !strings.EqualFold(q.get("name"), v.Name) ||
+			q.has("city") && !strings.EqualFold(q.get("city"), v.City) ||
+			q.has("country") && !strings.EqualFold(q.get("country"), "Italy") ||
+			q.has("search") && !strings.Contains(strings.ToLower(v.Name), strings.ToLower(q.get("search"))) {
+			continue
+		}
+		seen[v.ID] = true
+		response = append(response, venueJson(v))
+	}
+	return result{response: response}
+}
+
+// matchesFor returns the played and unplayed matches of a league, optionally
+// restricted to a team, in kickoff order.
+func (s *Server) matchesFor(leagueId int, teamId int) []*match {
+	var matches []*match
+	for _, m := range s.data.matches {
+		if leagueId != 0 && m.League.ID != leagueId {
+			continue
+		}
+		if teamId != 0 && m.Home.ID != teamId && m.Away.ID != teamId {
+			continue
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+type record struct {
+	played, win, draw, lose, gf, ga int
+}
+
+func (r *record) add(gf int, ga int) {
+	r.played++
+	r.gf += gf
+	r.ga += ga
+	switch {
+	case gf > ga:
+		r.win++
+	case gf < ga:
+		r.lose++
+	default:
+		r.draw++
+	}
+}
+
+func (r record) json() M {
+	return M{"played": r.played, "win": r.win, "draw": r.draw, "lose": r.lose, "goals": M{"for": r.gf, "against": r.ga}}
+}
+
+type row struct {
+	team            *team
+	all, home, away record
+	form            string
+}
+
+func (r *row) points() int {
+	return 3*r.all.win + r.all.draw
+}
+
+func (s *Server) standings(q query) result {
+	if !q.matchesSeason() {
+		return result{}
+	}
+	l := s.data.league(q.int("league"))
+	if q.has("league") && (l == nil || l.Type != "League") {
+		return result{}
+	}
+	if l == nil {
+		l = s.data.league(serieA)
+	}
+
+	rows := map[int]*row{}
+	for _, t := range s.teamsIn(l.ID) {
+		rows[t.ID] = &row{team: t}
+	}
+	for _, m := range s.matchesFor(l.ID, 0) {
+		if !m.played() {
+			continue
+		}
+		home, away := rows[m.Home.ID], rows[m.Away.ID]
+		home.all.add(m.Goals[0], m.Goals[1])
+		home.home.add(m.Goals[0], m.Goals[1])
+		away.all.add(m.Goals[1], m.Goals[0])
+		away.away.add(m.Goals[1], m.Goals[0])
+		home.form = resultLetter(m.Goals[0], m.Goals[1]) + home.form
+		away.form = resultLetter(m.Goals[1], m.Goals[0]) + away.form
+	}
+	table := make([]*row, 0, len(rows))
+	for _, r := range rows {
+		table = append(table, r)
+	}
+	sort.Slice(table, func(i, j int) bool {
+		a, b := table[i], table[j]
+		if a.points() != b.points() {
+			return a.points() > b.points()
+		}
+		if a.all.gf-a.all.ga != b.all.gf-b.all.ga {
+			return a.all.gf-a.all.ga > b.all.gf-b.all.ga
+		}
+		if a.all.gf != b.all.gf {
+			return a.all.gf > b.all.gf
+		}
+		return a.team.Name < b.team.Name
+	})
+
+	var standings []M
+	for i, r := range table {
+		if q.has("team") && q.int("team") != r.team.ID {
+			continue
+		}
+		form := r.form
+		if len(form) > 5 {
+			form = form[:5]
+		}
+		standings = append(standings, M{
+			"rank": i + 1, "team": teamRef(r.team), "points": r.points(), "goalsDiff": r.all.gf - r.all.ga,
+			"group": l.Name, "form": form, "status": "same", "description": zone(i + 1),
+			"all": r.all.json(), "home": r.home.json(), "away": r.away.json(),
+			"update": s.opts.Now.Format(time.RFC3339),
+		})
+	}
+	league := leagueRef(l)
+	league["standings"] = [][]M{standings}
+	return result{response: []M{{"league": league}}}
+}
+
+func zone(rank int) interface{} {
+	switch {
+	case rank <= 4:
+		return "Promotion - Champions League (Group Stage)"
+	case rank == 5:
+		return "Promotion - Europa League (Group Stage)"
+	case rank == 6:
+		return "Promotion - Europa Conference League (Qualification)"
+	case rank >= 18:
+		return "Relegation - Serie B"
+	}
+	return nil
+}
+
+func resultLetter(gf int, ga int) string {
+	switch {
+	case gf > ga:
+		return "W"
+	case gf < ga:
+		return "L"
+	}
+	return "D"
+}
+
+func (s *Server) rounds(q query) result {
+	l := s.data.league(q.int("league"))
+	if l == nil || !q.matchesSeason() {
+		return result{}
+	}
+	var response []string
+	for _, round := range l.Rounds {
+		for _, m := range s.data.matches {
+			if m.League == l && m.Round == round {
+				response = append(response, round)
+				break
+			}
+		}
+	}
+	if q.get("current") == "true" {
+		current := ""
+		for _, m := range s.matchesFor(l.ID, 0) {
+			if m.played() {
+				current = m.Round
+			}
+		}
+		response = []string{current}
+	}
+	return result{response: toList(response)}
+}
+
+func toList(items []string) []interface{} {
+	list := make([]interface{}, len(items))
+	for i, item := range items {
+		list[i] = item
+	}
+	return list
+}
+
+func fixtureJson(m *match) M {
+	statuses := map[string]string{"FT": "Match Finished", "AET": "Match Finished After Extra Time", "PEN": "Match Finished After Penalty", "NS": "Not Started", "PST": "Match Postponed"}
+	var first, second, elapsed interface{}
+	if m.played() {
+		first = m.Date.Unix()
+		second = m.Date.Add(time.Hour).Unix()
+		elapsed = 90
+		if m.Extratime != nil {
+			elapsed = 120
+		}
+	}
+	homeWinner, awayWinner := interface{}(nil), interface{}(nil)
+	if w := m.winner(); w >= 0 {
+		homeWinner, awayWinner = w == 0, w == 1
+	}
+	score := func(pair *[2]int) M {
+		if pair == nil {
+			return M{"home": nil, "away": nil}
+		}
+		return M{"home": pair[0], "away": pair[1]}
+	}
+	var goals, halftime, fulltime M
+	if m.played() {
+		goals = score(&m.Goals)
+		halftime = score(&m.Halftime)
+		regular := m.Goals
+		if m.Extratime != nil {
+			regular[0] -= m.Extratime[0]
+			regular[1] -= m.Extratime[1]
+		}
+		fulltime = score(&regular)
+	} else {
+		goals, halftime, fulltime = score(nil), score(nil), score(nil)
+	}
+	league := leagueRef(m.League)
+	league["round"] = m.Round
+	return M{
+		"fixture": M{
+			"id": m.ID, "referee": m.Referee, "timezone": "UTC", "date": m.Date.Format(time.RFC3339), "timestamp": m.Date.Unix(),
+			"periods": M{"first": first, "second": second},
+			"venue":   M{"id": m.Home.Venue.ID, "name": m.Home.Venue.Name, "city": m.Home.Venue.City},
+			"status":  M{"long": statuses[m.Status], "short": m.Status, "elapsed": elapsed},
+		},
+		"league": league,
+		"teams": M{
+			"home": M{"id": m.Home.ID, "name": m.Home.Name, "logo": m.Home.logo(), "winner": homeWinner},
+			"away": M{"id": m.Away.ID, "name": m.Away.Name, "logo": m.Away.logo(), "winner": awayWinner},
+		},
+		"goals": goals,
+		"score": M{"halftime": halftime, "fulltime": fulltime, "extratime": score(m.Extratime), "penalty": score(m.Penalty)},
+	}
+}
+
+// filterMatches applies the filters shared by fixtures and fixtures/headtohead.
+func (s *Server) filterMatches(matches []*match, q query) ([]*match, map[string]string) {
+	if !q.matchesSeason() {
+		return nil, nil
+	}
+	var from, to time.Time
+	var err error
+	if q.has("from") != q.has("to") {
+		return nil, map[string]string{"from": "The From and To fields are required together."}
+	}
+	if q.has("from") {
+		if from, err = time.Parse("2006-01-02", q.get("from")); err != nil {
+			return nil, map[string]string{"from": "The From field must be a valid date (YYYY-MM-DD)."}
+		}
+		if to, err = time.Parse("2006-01-02", q.get("to")); err != nil {
+			return nil, map[string]string{"to": "The To field must be a valid date (YYYY-MM-DD)."}
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	statuses := map[string]bool{}
+	for _, status := range strings.Split(q.get("status"), "-") {
+		if status != "" {
+			statuses[status] = true
+		}
+	}
+
+	var filtered []*match
+	for _, m := range matches {
+		if q.has("league") && q.int("league") != m.League.ID ||
+			q.has("round") && q.get("round") != m.Round ||
+			q.has("venue") && q.int("venue") != m.Home.Venue.ID ||
+			q.has("date") && q.get("date") != m.Date.Format("2006-01-02") ||
+			q.has("from") && (m.Date.Before(from) || !m.Date.Before(to)) ||
+			len(statuses) > 0 && !statuses[m.Status] {
+			continue
+		}
+		filtered = append(filtered, m)
+	}
+	if q.has("last") {
+		var played []*match
+		for _, m := range filtered {
+			if m.played() {
+				played = append(played, m)
+			}
+		}
+		if n := q.int("last"); n < len(played) {
+			played = played[len(played)-n:]
+		}
+		filtered = played
+	}
+	if q.has("next") {
+		var upcoming []*match
+		for _, m := range filtered {
+			if m.Status == "NS" {
+				upcoming = append(upcoming, m)
+			}
+		}
+		if n := q.int("next"); n < len(upcoming) {
+			upcoming = upcoming[:n]
+		}
+		filtered = upcoming
+	}
+	return filtered, nil
+}
+
+func (s *Server) fixtures(q query) result {
+	var matches []*match
+	switch {
+	case q.has("id"):
+		if m := s.data.matchById(q.int("id")); m != nil {
+			matches = []*match{m}
+		}
+	case q.has("ids"):
+		for _, id := range strings.Split(q.get("ids"), "-") {
+			n, _ := strconv.Atoi(id)
+			if m := s.data.matchById(n); m != nil {
+				matches = append(matches, m)
+			}
+		}
+	case q.has("live"):
+		return result{}
+	default:
+		if !q.has("league") && !q.has("team") && !q.has("date") && !q.has("from") && !q.has("round") {
+			return result{errors: map[string]string{"required": "At least one parameter is required."}}
+		}
+		matches = s.matchesFor(0, q.int("team"))
+	}
+	matches, errors := s.filterMatches(matches, q)
+	if errors != nil {
+		return result{errors: errors}
+	}
+	response := make([]M, 0, len(matches))
+	for _, m := range matches {
+		response = append(response, fixtureJson(m))
+	}
+	return result{response: response}
+}
+
+func (s *Server) headtohead(q query) result {
+	ids := strings.Split(q.get("h2h"), "-")
+	if len(ids) != 2 {
+		return result{errors: map[string]string{"h2h": "The H2h field must contain two team ids separated by a dash."}}
+	}
+	a, _ := strconv.Atoi(ids[0])
+	b, _ := strconv.Atoi(ids[1])
+	var matches []*match
+	for _, m := range s.data.matches {
+		if m.Home.ID == a && m.Away.ID == b || m.Home.ID == b && m.Away.ID == a {
+			matches = append(matches, m)
+		}
+	}
+	matches, errors := s.filterMatches(matches, q)
+	if errors != nil {
+		return result{errors: errors}
+	}
+	response := make([]M, 0, len(matches))
+	for _, m := range matches {
+		response = append(response, fixtureJson(m))
+	}
+	return result{response: response}
+}
+
+// fixtureSides returns the sides of m selected by the optional team parameter.
+func fixtureSides(m *match, q query) []int {
+	if q.has("team") {
+		switch q.int("team") {
+		case m.Home.ID:
+			return []int{0}
+		case m.Away.ID:
+			return []int{1}
+		}
+		return nil
+	}
+	return []int{0, 1}
+}
+
+func (s *Server) fixtureStatistics(q query) result {
+	m := s.data.matchById(q.int("fixture"))
+	if m == nil || !m.played() {
+		return result{}
+	}
+	var response []M
+	for _, side := range fixtureSides(m, q) {
+		st := m.Stats[side]
+		stats := []M{
+			{"type": "Shots on Goal", "value": st.ShotsOn},
+			{"type": "Shots off Goal", "value": st.ShotsOff},
+			{"type": "Total Shots", "value": st.ShotsOn + st.ShotsOff + st.Blocked},
+			{"type": "Blocked Shots", "value": st.Blocked},
+			{"type": "Shots insidebox", "value": nullable(st.ShotsOn + st.Blocked/2)},
+			{"type": "Shots outsidebox", "value": nullable(st.ShotsOff)},
+			{"type": "Fouls", "value": st.Fouls},
+			{"type": "Corner Kicks", "value": st.Corners},
+			{"type": "Offsides", "value": nullable(st.Offsides)},
+			{"type": "Ball Possession", "value": strconv.Itoa(st.Possession) + "%"},
+			{"type": "Yellow Cards", "value": nullable(st.Yellow)},
+			{"type": "Red Cards", "value": nullable(st.Red)},
+			{"type": "Goalkeeper Saves", "value": nullable(st.Saves)},
+			{"type": "Total passes", "value": st.Passes},
+			{"type": "Passes accurate", "value": st.PassesOk},
+			{"type": "Passes %", "value": strconv.Itoa(st.PassesOk*100/st.Passes) + "%"},
+		}
+		if q.has("type") {
+			var filtered []M
+			for _, stat := range stats {
+				if strings.EqualFold(stat["type"].(string), q.get("type")) {
+					filtered = append(filtered, stat)
+				}
+			}
+			stats = filtered
+		}
+		response = append(response, M{"team": teamRef(m.team(side)), "statistics": stats})
+	}
+	return result{response: response}
+}
+
+func (s *Server) events(q query) result {
+	m := s.data.matchById(q.int("fixture"))
+	if m == nil || !m.played() {
+		return result{}
+	}
+	var response []M
+	for _, e := range m.Events {
+		if q.has("team") && q.int("team") != e.Team.ID ||
+			q.has("player") && q.int("player") != e.Player.ID ||
+			q.has("type") && !strings.EqualFold(q.get("type"), e.Type) {
+			continue
+		}
+		var extra, comments interface{}
+		if e.Extra != 0 {
+			extra = e.Extra
+		}
+		if e.Comments != "" {
+			comments = e.Comments
+		}
+		response = append(response, M{
+			"time": M{"elapsed": e.Elapsed, "extra": extra}, "team": teamRef(e.Team),
+			"player": playerRef(e.Player), "assist": playerRef(e.Assist),
+			"type": e.Type, "detail": e.Detail, "comments": comments,
+		})
+	}
+	return result{response: response}
+}
+
+func (s *Server) lineups(q query) result {
+	m := s.data.matchById(q.int("fixture"))
+	if m == nil || !m.played() {
+		return result{}
+	}
+	var response []M
+	for _, side := range fixtureSides(m, q) {
+		t := m.team(side)
+		c := t.coachAt(m.Date)
+		var startXI, substitutes []M
+		for _, app := range m.Players[side] {
+			entry := M{"player": M{"id": app.Player.ID, "name": app.Player.name(), "number": app.Player.Number, "pos": app.Player.shortPos(), "grid": nil}}
+			if app.Starter {
+				entry["player"].(M)["grid"] = app.Grid
+				startXI = append(startXI, entry)
+			} else {
+				substitutes = append(substitutes, entry)
+			}
+		}
+		response = append(response, M{
+			"team": M{"id": t.ID, "name": t.Name, "logo": t.logo(), "colors": M{
+				"player":     M{"primary": t.Primary, "number": t.Number, "border": t.Primary},
+				"goalkeeper": M{"primary": t.Keeper, "number": "000000", "border": t.Keeper},
+			}},
+			"coach":       M{"id": c.ID, "name": c.Firstname[:1] + ". " + c.Lastname, "photo": fmt.Sprintf("%s/coachs/%d.png", media, c.ID)},
+			"formation":   m.Formation[side],
+			"startXI":     startXI,
+			"substitutes": substitutes,
+		})
+	}
+	return result{response: response}
+}
+
+func (s *Server) fixturePlayers(q query) result {
+	m := s.data.matchById(q.int("fixture"))
+	if m == nil || !m.played() {
+		return result{}
+	}
+	var response []M
+	for _, side := range fixtureSides(m, q) {
+		var players []M
+		for _, app := range m.Players[side] {
+			var minutes, rating interface{}
+			if app.Minutes > 0 {
+				minutes = app.Minutes
+				rating = strconv.FormatFloat(app.Rating, 'f', 1, 64)
+			}
+			players = append(players, M{
+				"player": M{"id": app.Player.ID, "name": app.Player.name(), "photo": fmt.Sprintf("%s/players/%d.png", media, app.Player.ID)},
+				"statistics": []M{{
+					"games":    M{"minutes": minutes, "number": app.Player.Number, "position": app.Player.shortPos(), "rating": rating, "captain": app.Captain, "substitute": !app.Starter},
+					"offsides": nil,
+					"shots":    M{"total": nullable(app.Shots), "on": nullable(app.ShotsOn)},
+					"goals":    M{"total": nullable(app.Goals), "conceded": app.Conceded, "assists": nullable(app.Assists), "saves": nullable(app.Saves)},
+					"passes":   M{"total": nullable(app.Passes), "key": nullable(app.KeyPasses), "accuracy": nullableString(app.Accuracy)},
+					"tackles":  M{"total": nullable(app.Tackles), "blocks": nullable(app.Blocks), "interceptions": nullable(app.Interceptions)},
+					"duels":    M{"total": nullable(app.Duels), "won": nullable(app.DuelsWon)},
+					"dribbles": M{"attempts": nullable(app.Dribbles), "success": nullable(app.DribblesWon), "past": nil},
+					"fouls":    M{"drawn": nullable(app.Drawn), "committed": nullable(app.Fouls)},
+					"cards":    M{"yellow": app.Yellow, "red": app.Red},
+					"penalty":  M{"won": nil, "commited": nil, "scored": app.PenScored, "missed": app.PenMissed, "saved": 0},
+				}},
+			})
+		}
+		t := m.team(side)
+		response = append(response, M{"team": M{"id": t.ID, "name": t.Name, "logo": t.logo(), "update": m.Date.Add(3 * time.Hour).Format(time.RFC3339)}, "players": players})
+	}
+	return result{response: response}
+}
+
+func nullableString(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return strconv.Itoa(n)
+}
+
+func (s *Server) injuries(q query) result {
+	if !q.has("league") && !q.has("fixture") && !q.has("team") && !q.has("player") && !q.has("date") {
+		return result{errors: map[string]string{"required": "At least one parameter is required."}}
+	}
+	if !q.matchesSeason() {
+		return result{}
+	}
+	var response []M
+	for _, m := range s.data.matches {
+		if q.has("fixture") && q.int("fixture") != m.ID ||
+			q.has("league") && q.int("league") != m.League.ID ||
+			q.has("date") && q.get("date") != m.Date.Format("2006-01-02") {
+			continue
+		}
+		for side := 0; side < 2; side++ {
+			t := m.team(side)
+			if q.has("team") && q.int("team") != t.ID {
+				continue
+			}
+			for _, p := range t.Players {
+				if q.has("player") && q.int("player") != p.ID {
+					continue
+				}
+				for _, inj := range p.Injuries {
+					if m.Date.Before(inj.Start) || !m.Date.Before(inj.End) {
+						continue
+					}
+					league := leagueRef(m.League)
+					response = append(response, M{
+						"player":  M{"id": p.ID, "name": p.name(), "photo": fmt.Sprintf("%s/players/%d.png", media, p.ID), "type": inj.Type, "reason": inj.Reason},
+						"team":    teamRef(t),
+						"fixture": M{"id": m.ID, "timezone": "UTC", "date": m.Date.Format(time.RFC3339), "timestamp": m.Date.Unix()},
+						"league":  league,
+					})
+				}
+			}
+		}
+	}
+	return result{response: response}
+}
+
+func coachName(c *coach) string {
+	return c.Firstname[:1] + ". " + c.Lastname
+}
+
+func (s *Server) coachs(q query) result {
+	if !q.has("id") && !q.has("team") && !q.has("search") {
+		return result{errors: map[string]string{"required": "At least one parameter is required."}}
+	}
+	var response []M
+	for _, t := range s.data.teams {
+		for _, c := range t.Coaches {
+			if q.has("id") && q.int("id") != c.ID ||
+				q.has("team") && q.int("team") != t.ID ||
+				q.has("search") && !strings.Contains(strings.ToLower(c.Lastname), strings.ToLower(q.get("search"))) {
+				continue
+			}
+			current := t.coachAt(s.opts.Now)
+			var careers []M
+			for _, job := range c.Career {
+				var end interface{}
+				if job.End != "" {
+					end = job.End
+				}
+				careers = append(careers, M{"team": teamRef(s.data.teamById(job.TeamID)), "start": job.Start, "end": end})
+			}
+			teamNow := M{"id": nil, "name": nil, "logo": nil}
+			if current == c {
+				teamNow = teamRef(t)
+			}
+			response = append(response, M{
+				"id": c.ID, "name": coachName(c), "firstname": c.Firstname, "lastname": c.Lastname, "age": c.Age,
+				"birth":       M{"date": c.BirthDate, "place": c.Place, "country": c.Country},
+				"nationality": c.Country, "height": nil, "weight": nil,
+				"photo": fmt.Sprintf("%s/coachs/%d.png", media, c.ID), "team": teamNow, "career": careers,
+			})
+		}
+	}
+	return result{response: response}
+}
+
+func playerJson(p *player, now time.Time) M {
+	injured := false
+	for _, inj := range p.Injuries {
+		if !now.Before(inj.Start) && now.Before(inj.End) {
+			injured = true
+		}
+	}
+	return M{
+		"id": p.ID, "name": p.name(), "firstname": p.Firstname, "lastname": p.Lastname, "age": p.Age,
+		"birth":       M{"date": p.BirthDate, "place": p.Place, "country": p.Nationality},
+		"nationality": p.Nationality, "height": fmt.Sprintf("%d cm", p.Height), "weight": fmt.Sprintf("%d kg", p.Weight),
+		"injured": injured, "photo": fmt.Sprintf("%s/players/%d.png", media, p.ID),
+	}
+}
+
+// seasonStats sums a player's appearances in one competition.
+type seasonStats struct {
+	apps, lineups, minutes, in, out, bench           int
+	ratingSum                                        float64
+	rated                                            int
+	shots, shotsOn, goals, conceded, assists, saves  int
+	passes, key, accuracySum                         int
+	tackles, blocks, interceptions, duels, duelsWon  int
+	dribbles, dribblesWon, drawn, fouls, yellow, red int
+	penScored, penMissed, captain                    int
+}
+
+func (s *Server) statsByLeague(p *player, leagueId int) map[*league]*seasonStats {
+	stats := map[*league]*seasonStats{}
+	for _, m := range s.data.matches {
+		if !m.played() || leagueId != 0 && m.League.ID != leagueId {
+			continue
+		}
+		side := m.side(p.Team.ID)
+		if m.team(side) != p.Team {
+			continue
+		}
+		for _, app := range m.Players[side] {
+			if app.Player != p {
+				continue
+			}
+			st := stats[m.League]
+			if st == nil {
+				st = &seasonStats{}
+				stats[m.League] = st
+			}
+			if app.Minutes == 0 {
+				st.bench++
+				continue
+			}
+			st.apps++
+			if app.Starter {
+				st.lineups++
+				if app.Minutes < 90 {
+					st.out++
+				}
+			} else {
+				st.in++
+			}
+			if app.Captain {
+				st.captain++
+			}
+			st.minutes += app.Minutes
+			st.ratingSum += app.Rating
+			st.rated++
+			st.shots += app.Shots
+			st.shotsOn += app.ShotsOn
+			st.goals += app.Goals
+			st.conceded += app.Conceded
+			st.assists += app.Assists
+			st.saves += app.Saves
+			st.passes += app.Passes
+			st.key += app.KeyPasses
+			st.accuracySum += app.Accuracy
+			st.tackles += app.Tackles
+			st.blocks += app.Blocks
+			st.interceptions += app.Interceptions
+			st.duels += app.Duels
+			st.duelsWon += app.DuelsWon
+			st.dribbles += app.Dribbles
+			st.dribblesWon += app.DribblesWon
+			st.drawn += app.Drawn
+			st.fouls += app.Fouls
+			st.yellow += app.Yellow
+			st.red += app.Red
+			st.penScored += app.PenScored
+			st.penMissed += app.PenMissed
+		}
+	}
+	return stats
+}
+
+func statisticJson(p *player, l *league, st *seasonStats) M {
+	var rating interface{}
+	accuracy := 0
+	if st.rated > 0 {
+		rating = strconv.FormatFloat(st.ratingSum/float64(st.rated), 'f', 6, 64)
+		accuracy = st.accuracySum / st.rated
+	}
+	var saves interface{}
+	if p.Position == "Goalkeeper" {
+		saves = st.saves
+	}
+	league := leagueRef(l)
+	return M{
+		"team":        teamRef(p.Team),
+		"league":      league,
+		"games":       M{"appearences": st.apps, "lineups": st.lineups, "minutes": st.minutes, "number": nil, "position": p.Position, "rating": rating, "captain": st.captain > st.apps/2 && st.apps > 0},
+		"substitutes": M{"in": st.in, "out": st.out, "bench": st.bench},
+		"shots":       M{"total": st.shots, "on": st.shotsOn},
+		"goals":       M{"total": st.goals, "conceded": st.conceded, "assists": st.assists, "saves": saves},
+		"passes":      M{"total": st.passes, "key": st.key, "accuracy": accuracy},
+		"tackles":     M{"total": st.tackles, "blocks": nullable(st.blocks), "interceptions": st.interceptions},
+		"duels":       M{"total": st.duels, "won": st.duelsWon},
+		"dribbles":    M{"attempts": st.dribbles, "success": st.dribblesWon, "past": nil},
+		"fouls":       M{"drawn": st.drawn, "committed": st.fouls},
+		"cards":       M{"yellow": st.yellow, "yellowred": 0, "red": st.red},
+		"penalty":     M{"won": nil, "commited": nil, "scored": st.penScored, "missed": st.penMissed, "saved": nil},
+	}
+}
+
+type playerLine struct {
+	player *player
+	league *league
+	stats  *seasonStats
+}
+
+func (s *Server) playerEntry(p *player, leagueId int) M {
+	byLeague := s.statsByLeague(p, leagueId)
+	var statistics []M
+	for _, l := range s.data.leagues {
+		if st, ok := byLeague[l]; ok {
+			statistics = append(statistics, statisticJson(p, l, st))
+		}
+	}
+	if statistics == nil {
+		statistics = []M{}
+	}
+	return M{"player": playerJson(p, s.opts.Now), "statistics": statistics}
+}
+
+func (s *Server) players(q query) result {
+	if !q.has("id") && !q.has("team") && !q.has("league") {
+		return result{errors: map[string]string{"required": "At least one of the Id, Team or League fields is required."}}
+	}
+	if !q.matchesSeason() {
+		return result{}
+	}
+	var entries []M
+	for _, p := range s.data.players {
+		if q.has("id") && q.int("id") != p.ID ||
+			q.has("team") && q.int("team") != p.Team.ID ||
+			q.has("search") && !strings.Contains(strings.ToLower(p.Lastname), strings.ToLower(q.get("search"))) {
+			continue
+		}
+		if q.has("league") {
+			found := false
+			for _, t := range s.teamsIn(q.int("league")) {
+				if t == p.Team {
+					found = true
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+		entries = append(entries, s.playerEntry(p, q.int("league")))
+	}
+	if entries == nil {
+		entries = []M{}
+	}
+	page := 1
+	if q.has("page") {
+		page = q.int("page")
+	}
+	return s.paginate(entries, page)
+}
+
+func (s *Server) squads(q query) result {
+	if !q.has("team") && !q.has("player") {
+		return result{errors: map[string]string{"required": "At least one parameter is required."}}
+	}
+	var response []M
+	for _, t := range s.data.teams {
+		if q.has("team") && q.int("team") != t.ID {
+			continue
+		}
+		var players []M
+		for _, p := range t.Players {
+			if q.has("player") && q.int("player") != p.ID {
+				continue
+			}
+			players = append(players, M{"id": p.ID, "name": p.name(), "age": p.Age, "number": p.Number, "position": p.Position, "photo": fmt.Sprintf("%s/players/%d.png", media, p.ID)})
+		}
+		if players != nil {
+			response = append(response, M{"team": teamRef(t), "players": players})
+		}
+	}
+	return result{response: response}
+}
+
+// top ranks the players of a league by key, most first, like the top
+// scorers/assists/cards endpoints (20 entries).
+func (s *Server) top(q query, key func(st *seasonStats) int) result {
+	l := s.data.league(q.int("league"))
+	if l == nil || !q.matchesSeason() {
+		return result{}
+	}
+	var lines []playerLine
+	for _, p := range s.data.players {
+		if st, ok := s.statsByLeague(p, l.ID)[l]; ok && key(st) > 0 {
+			lines = append(lines, playerLine{p, l, st})
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		a, b := key(lines[i].stats), key(lines[j].stats)
+		if a != b {
+			return a > b
+		}
+		return lines[i].stats.minutes < lines[j].stats.minutes
+	})
+	if len(lines) > 20 {
+		lines = lines[:20]
+	}
+	response := make([]M, 0, len(lines))
+	for _, line := range lines {
+		response = append(response, M{"player": playerJson(line.player, s.opts.Now), "statistics": []M{statisticJson(line.player, line.league, line.stats)}})
+	}
+	return result{response: response}
+}
+
+func (s *Server) topscorers(q query) result {
+	return s.top(q, func(st *seasonStats) int { return st.goals })
+}
+
+func (s *Server) topassists(q query) result {
+	return s.top(q, func(st *seasonStats) int { return st.assists })
+}
+
+func (s *Server) topyellowcards(q query) result {
+	return s.top(q, func(st *seasonStats) int { return st.yellow })
+}
+
+func (s *Server) topredcards(q query) result {
+	return s.top(q, func(st *seasonStats) int { return st.red })
+}
+
+func (s *Server) transfers(q query) result {
+	if !q.has("player") && !q.has("team") {
+		return result{errors: map[string]string{"required": "At least one parameter is required."}}
+	}
+	var response []M
+	for _, p := range s.data.players {
+		if len(p.Transfers) == 0 || q.has("player") && q.int("player") != p.ID {
+			continue
+		}
+		var transfers []M
+		for _, tr := range p.Transfers {
+			if q.has("team") && q.int("team") != tr.InID && q.int("team") != tr.OutID {
+				continue
+			}
+			transfers = append(transfers, M{"date": tr.Date, "type": tr.Type, "teams": M{
+				"in":  teamRef(s.data.teamById(tr.InID)),
+				"out": teamRef(s.data.teamById(tr.OutID)),
+			}})
+		}
+		if transfers == nil {
+			continue
+		}
+		response = append(response, M{"player": playerRef(p), "update": s.opts.Now.Format(time.RFC3339), "transfers": transfers})
+	}
+	return result{response: response}
+}
+
+func (s *Server) trophies(q query) result {
+	if !q.has("player") && !q.has("coach") {
+		return result{errors: map[string]string{"required": "At least one parameter is required."}}
+	}
+	var response []M
+	if p := s.data.playerById(q.int("player")); p != nil {
+		for _, t := range p.Trophies {
+			response = append(response, M{"league": t.League, "country": t.Country, "season": t.Season, "place": t.Place})
+		}
+	}
+	return result{response: response}
+}
+
+func (s *Server) sidelined(q query) result {
+	if !q.has("player") && !q.has("coach") {
+		return result{errors: map[string]string{"required": "At least one parameter is required."}}
+	}
+	var response []M
+	if p := s.data.playerById(q.int("player")); p != nil {
+		for _, inj := range p.Injuries {
+			response = append(response, M{"type": inj.Reason, "start": inj.Start.Format("2006-01-02"), "end": inj.End.Format("2006-01-02")})
+		}
+	}
+	return result{response: response}
+}
+
+// teamStats builds the teams/statistics object from the matches of a team
+// in a league that kicked off before the given date.
+func (s *Server) teamStats(l *league, t *team, before time.Time) M {
+	type split struct{ home, away int }
+	var played, wins, draws, loses, gf, ga, clean, failed split
+	var forMinutes, againstMinutes, yellowMinutes, redMinutes [8]int
+	var form string
+	var streak, bestStreak [3]int // wins, draws, loses
+	bigWin, bigLose := [2]string{}, [2]string{}
+	bigWinMargin, bigLoseMargin := [2]int{}, [2]int{}
+	var mostFor, mostAgainst [2]int
+	formations := map[string]int{}
+	var penScored, penMissed int
+
+	for _, m := range s.matchesFor(l.ID, t.ID) {
+		if !m.played() || !m.Date.Before(before) {
+			continue
+		}
+		side := m.side(t.ID)
+		own, other := m.Goals[side], m.Goals[1-side]
+		inc := func(sp *split, n int) {
+			if side == 0 {
+				sp.home += n
+			} else {
+				sp.away += n
+			}
+		}
+		inc(&played, 1)
+		inc(&gf, own)
+		inc(&ga, other)
+		if other == 0 {
+			inc(&clean, 1)
+		}
+		if own == 0 {
+			inc(&failed, 1)
+		}
+		score := fmt.Sprintf("%d-%d", m.Goals[0], m.Goals[1])
+		outcome := 1
+		switch {
+		case own > other:
+			inc(&wins, 1)
+			outcome = 0
+			if own-other > bigWinMargin[side] {
+				bigWinMargin[side], bigWin[side] = own-other, score
+			}
+		case own < other:
+			inc(&loses, 1)
+			outcome = 2
+			if other-own > bigLoseMargin[side] {
+				bigLoseMargin[side], bigLose[side] = other-own, score
+			}
+		default:
+			inc(&draws, 1)
+		}
+		for i := range streak {
+			if i == outcome {
+				streak[i]++
+				if streak[i] > bestStreak[i] {
+					bestStreak[i] = streak[i]
+				}
+			} else {
+				streak[i] = 0
+			}
+		}
+		form += []string{"W", "D", "L"}[outcome]
+		if own > mostFor[side] {
+			mostFor[side] = own
+		}
+		if other > mostAgainst[side] {
+			mostAgainst[side] = other
+		}
+		formations[m.Formation[side]]++
+		for _, e := range m.Events {
+			minute := e.Elapsed
+			if e.Extra > 0 && minute == 90 {
+				minute = 91
+			}
+			b := bucketOf(minute)
+			switch {
+			case e.Type == "Goal" && e.Detail == "Missed Penalty":
+				if e.Team == t {
+					penMissed++
+				}
+			case e.Type == "Goal" && e.Team == t:
+				forMinutes[b]++
+				if e.Detail == "Penalty" {
+					penScored++
+				}
+			case e.Type == "Goal":
+				againstMinutes[b]++
+			case e.Type == "Card" && e.Team == t && e.Detail == "Yellow Card":
+				yellowMinutes[b]++
+			case e.Type == "Card" && e.Team == t:
+				redMinutes[b]++
+			}
+		}
+	}
+
+	hat := func(sp split) M { return M{"home": sp.home, "away": sp.away, "total": sp.home + sp.away} }
+	avg := func(goals split, games split) M {
+		f := func(g, n int) string {
+			if n == 0 {
+				return "0.0"
+			}
+			return strconv.FormatFloat(float64(g)/float64(n), 'f', 1, 64)
+		}
+		return M{"home": f(goals.home, games.home), "away": f(goals.away, games.away), "total": f(goals.home+goals.away, games.home+games.away)}
+	}
+	minutes := func(counts [8]int) M {
+		total := 0
+		for _, n := range counts {
+			total += n
+		}
+		out := M{}
+		for i, b := range buckets {
+			if counts[i] == 0 {
+				out[b.key] = M{"total": nil, "percentage": nil}
+			} else {
+				out[b.key] = M{"total": counts[i], "percentage": percent(counts[i], total)}
+			}
+		}
+		return out
+	}
+	nullString := func(s string) interface{} {
+		if s == "" {
+			return nil
+		}
+		return s
+	}
+	var lineups []M
+	for _, formation := range sortedFormations(formations) {
+		lineups = append(lineups, M{"formation": formation, "played": formations[formation]})
+	}
+	penTotal := penScored + penMissed
+
+	league := leagueRef(l)
+	return M{
+		"league":   league,
+		"team":     teamRef(t),
+		"form":     form,
+		"fixtures": M{"played": hat(played), "wins": hat(wins), "draws": hat(draws), "loses": hat(loses)},
+		"goals": M{
+			"for":     M{"total": hat(gf), "average": avg(gf, played), "minute": minutes(forMinutes)},
+			"against": M{"total": hat(ga), "average": avg(ga, played), "minute": minutes(againstMinutes)},
+		},
+		"biggest": M{
+			"streak": M{"wins": bestStreak[0], "draws": bestStreak[1], "loses": bestStreak[2]},
+			"wins":   M{"home": nullString(bigWin[0]), "away": nullString(bigWin[1])},
+			"loses":  M{"home": nullString(bigLose[0]), "away": nullString(bigLose[1])},
+			"goals": M{
+				"for":     M{"home": mostFor[0], "away": mostFor[1]},
+				"against": M{"home": mostAgainst[0], "away": mostAgainst[1]},
+			},
+		},
+		"clean_sheet":     hat(clean),
+		"failed_to_score": hat(failed),
+		"penalty": M{
+			"scored": M{"total": penScored, "percentage": percent(penScored, penTotal)},
+			"missed": M{"total": penMissed, "percentage": percent(penMissed, penTotal)},
+			"total":  penTotal,
+		},
+		"lineups": lineups,
+		"cards":   M{"yellow": minutes(yellowMinutes), "red": minutes(redMinutes)},
+	}
+}
+
+func bucketOf(minute int) int {
+	for i, b := range buckets {
+		if minute <= b.to {
+			return i
+		}
+	}
+	return len(buckets) - 1
+}
+
+func sortedFormations(formations map[string]int) []string {
+	var keys []string
+	for key := range formations {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if formations[keys[i]] != formations[keys[j]] {
+			return formations[keys[i]] > formations[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func (s *Server) teamStatistics(q query) result {
+	l := s.data.league(q.int("league"))
+	t := s.data.teamById(q.int("team"))
+	if l == nil || t == nil || !q.matchesSeason() {
+		return result{}
+	}
+	before := s.opts.Now
+	if q.has("date") {
+		date, err := time.Parse("2006-01-02", q.get("date"))
+		if err != nil {
+			return result{errors: map[string]string{"date": "The Date field must be a valid date (YYYY-MM-DD)."}}
+		}
+		before = date.AddDate(0, 0, 1)
+	}
+	return result{response: s.teamStats(l, t, before)}
+}
+
+// predictions derives the provider style prediction from the team strengths
+// and the form before kickoff.
+func (s *Server) predictions(q query) result {
+	m := s.data.matchById(q.int("fixture"))
+	if m == nil {
+		return result{}
+	}
+	home := 1.45 * m.Home.Attack * m.Away.Defence
+	away := 1.15 * m.Away.Attack * m.Home.Defence
+	var pHome, pDraw, pAway float64
+	for i := 0; i <= 10; i++ {
+		for j := 0; j <= 10; j++ {
+			p := poissonPmf(home, i) * poissonPmf(away, j)
+			switch {
+			case i > j:
+				pHome += p
+			case i < j:
+				pAway += p
+			default:
+				pDraw += p
+			}
+		}
+	}
+	// The provider rounds to steps of 5% and moves the draw into the favourite's double chance.
+	round5 := func(p float64) int { return int(math.Round(p*20)) * 5 }
+	percentHome, percentAway := round5(pHome), round5(pAway)
+	percentDraw := 100 - percentHome - percentAway
+
+	winner, comment := m.Home, "Win or draw"
+	if pAway > pHome {
+		winner = m.Away
+	}
+	if math.Abs(pHome-pAway) > 0.3 {
+		comment = "Win"
+	}
+	winOrDraw := comment == "Win or draw"
+	total := home + away
+	underOver := "-3.5"
+	if total > 2.8 {
+		underOver = "+2.5"
+	}
+	advice := "Double chance : " + winner.Name + " or draw"
+	if !winOrDraw {
+		advice = "Winner : " + winner.Name
+	}
+
+	var h2h []M
+	for _, other := range s.data.matches {
+		if other.played() && other.Date.Before(m.Date) &&
+			(other.Home == m.Home && other.Away == m.Away || other.Home == m.Away && other.Away == m.Home) {
+			h2h = append(h2h, fixtureJson(other))
+		}
+	}
+	if h2h == nil {
+		h2h = []M{}
+	}
+
+	teamJson := func(t *team, lambda float64, against float64) M {
+		stats := s.teamStats(m.League, t, m.Date)
+		last5, gf, ga := "", 0, 0
+		var recent []*match
+		for _, other := range s.matchesFor(0, t.ID) {
+			if other.played() && other.Date.Before(m.Date) {
+				recent = append(recent, other)
+			}
+		}
+		if len(recent) > 5 {
+			recent = recent[len(recent)-5:]
+		}
+		points := 0
+		for _, other := range recent {
+			side := other.side(t.ID)
+			gf += other.Goals[side]
+			ga += other.Goals[1-side]
+			switch resultLetter(other.Goals[side], other.Goals[1-side]) {
+			case "W":
+				points += 3
+			case "D":
+				points++
+			}
+		}
+		last5 = strconv.Itoa(points*100/15) + "%"
+		avg := func(n int) string {
+			if len(recent) == 0 {
+				return "0.0"
+			}
+			return strconv.FormatFloat(float64(n)/float64(len(recent)), 'f', 1, 64)
+		}
+		delete(stats, "league")
+		delete(stats, "team")
+		return M{
+			"id": t.ID, "name": t.Name, "logo": t.logo(),
+			"last_5": M{
+				"form": last5, "att": strconv.Itoa(int(math.Min(100, lambda*50))) + "%", "def": strconv.Itoa(int(math.Max(0, 100-against*50))) + "%",
+				"goals": M{"for": M{"total": gf, "average": avg(gf)}, "against": M{"total": ga, "average": avg(ga)}},
+			},
+			"league": stats,
+		}
+	}
+	share := func(a, b float64) M {
+		if a+b == 0 {
+			return M{"home": "50%", "away": "50%"}
+		}
+		h := int(math.Round(100 * a / (a + b)))
+		return M{"home": strconv.Itoa(h) + "%", "away": strconv.Itoa(100-h) + "%"}
+	}
+	league := leagueRef(m.League)
+	return result{response: []M{{
+		"predictions": M{
+			"winner":      M{"id": winner.ID, "name": winner.Name, "comment": comment},
+			"win_or_draw": winOrDraw,
+			"under_over":  underOver,
+			"goals":       M{"home": fmt.Sprintf("-%.1f", math.Ceil(home)+0.5), "away": fmt.Sprintf("-%.1f", math.Ceil(away)+0.5)},
+			"advice":      advice,
+			"percent":     M{"home": strconv.Itoa(percentHome) + "%", "draw": strconv.Itoa(percentDraw) + "%", "away": strconv.Itoa(percentAway) + "%"},
+		},
+		"league": league,
+		"teams": M{
+			"home": teamJson(m.Home, home, away),
+			"away": teamJson(m.Away, away, home),
+		},
+		"comparison": M{
+			"form":                 share(m.Home.Attack/m.Home.Defence, m.Away.Attack/m.Away.Defence),
+			"att":                  share(m.Home.Attack, m.Away.Attack),
+			"def":                  share(1/m.Home.Defence, 1/m.Away.Defence),
+			"poisson_distribution": share(pHome, pAway),
+			"h2h":                  share(1, 1),
+			"goals":                share(home, away),
+			"total":                share(pHome+pDraw/2, pAway+pDraw/2),
+		},
+		"h2h": h2h,
+	}}}
+}
+
+func poissonPmf(lambda float64, k int) float64 {
+	p := math.Exp(-lambda)
+	for i := 1; i <= k; i++ {
+		p *= lambda / float64(i)
+	}
+	return p
+}
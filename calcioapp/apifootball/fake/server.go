@@ -0,0 +1,346 @@
+// Package fake is a stand-in for the API-Football v3 service. It serves a
+// generated Serie A and Coppa Italia 2021 season with the same endpoints,
+// query parameters, paging and envelope as the real API, and can inject
+// errors, latency and quota exhaustion. Use it from tests with
+// httptest.NewServer(fake.New(fake.Options{})) or run cmd/fakeapifootball.
+package fake
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Options struct {
+	// Seed makes the generated season reproducible. Defaults to 1.
+	Seed int64
+	// Now splits the season into played and upcoming matches. Defaults to 2022-03-15.
+	Now time.Time
+	// Token, when set, must be sent in the x-apisports-key header.
+	Token string
+	// DailyLimit is the request quota. Defaults to 100; negative means unlimited.
+	DailyLimit int
+	// Latency is added to every response, plus a random amount up to Jitter.
+	Latency time.Duration
+	Jitter  time.Duration
+	// FailureRate is the share of requests answered with a 500.
+	FailureRate float64
+	// PageSize is the page length of paged endpoints. Defaults to 20.
+	PageSize int
+}
+
+type Server struct {
+	data *dataset
+
+	mu       sync.Mutex
+	opts     Options
+	rnd      *rand.Rand
+	used     int
+	failures map[string][]int
+}
+
+func New(opts Options) *Server {
+	if opts.Seed == 0 {
+		opts.Seed = 1
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+	}
+	if opts.DailyLimit == 0 {
+		opts.DailyLimit = 100
+	}
+	if opts.PageSize == 0 {
+		opts.PageSize = 20
+	}
+	return &Server{
+		data:     newDataset(opts.Seed, opts.Now),
+		opts:     opts,
+		rnd:      rand.New(rand.NewSource(opts.Seed)),
+		failures: map[string][]int{},
+	}
+}
+
+// FailNext answers the next times requests to endpoint (e.g. "fixtures")
+// with status.
+func (s *Server) FailNext(endpoint string, status int, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < times; i++ {
+		s.failures[endpoint] = append(s.failures[endpoint], status)
+	}
+}
+
+func (s *Server) SetLatency(latency time.Duration, jitter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts.Latency = latency
+	s.opts.Jitter = jitter
+}
+
+func (s *Server) SetFailureRate(rate float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts.FailureRate = rate
+}
+
+// ExhaustQuota uses up the rest of the daily quota.
+func (s *Server) ExhaustQuota() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used = s.opts.DailyLimit
+}
+
+func (s *Server) ResetQuota() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used = 0
+}
+
+// Used returns how many requests counted against the quota.
+func (s *Server) Used() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.used
+}
+
+type envelope struct {
+	Get        string      `json:"get"`
+	Parameters interface{} `json:"parameters"`
+	Errors     interface{} `json:"errors"`
+	Results    int         `json:"results"`
+	Paging     struct {
+		Current int `json:"current"`
+		Total   int `json:"total"`
+	} `json:"paging"`
+	Response interface{} `json:"response"`
+}
+
+// result is what an endpoint handler produces.
+type result struct {
+	response interface{}
+	page     int
+	pages    int
+	errors   map[string]string
+}
+
+type endpoint struct {
+	params   []string
+	required []string
+	handle   func(s *Server, q query) result
+}
+
+var endpoints = map[string]endpoint{
+	"status":                 {nil, nil, (*Server).status},
+	"leagues":                {[]string{"id", "name", "country", "code", "season", "type", "current", "team"}, nil, (*Server).leagues},
+	"teams":                  {[]string{"id", "name", "league", "season", "country", "code", "venue", "search"}, nil, (*Server).teams},
+	"teams/statistics":       {[]string{"league", "season", "team", "date"}, []string{"league", "season", "team"}, (*Server).teamStatistics},
+	"venues":                 {[]string{"id", "name", "city", "country", "search"}, nil, (*Server).venues},
+	"standings":              {[]string{"league", "season", "team"}, []string{"season"}, (*Server).standings},
+	"fixtures/rounds":        {[]string{"league", "season", "current"}, []string{"league", "season"}, (*Server).rounds},
+	"fixtures":               {[]string{"id", "ids", "live", "date", "league", "season", "team", "last", "next", "from", "to", "round", "status", "venue", "timezone"}, nil, (*Server).fixtures},
+	"fixtures/headtohead":    {[]string{"h2h", "date", "league", "season", "last", "next", "from", "to", "status", "venue", "timezone"}, []string{"h2h"}, (*Server).headtohead},
+	"fixtures/statistics":    {[]string{"fixture", "team", "type"}, []string{"fixture"}, (*Server).fixtureStatistics},
+	"fixtures/events":        {[]string{"fixture", "team", "player", "type"}, []string{"fixture"}, (*Server).events},
+	"fixtures/lineups":       {[]string{"fixture", "team", "player", "type"}, []string{"fixture"}, (*Server).lineups},
+	"fixtures/players":       {[]string{"fixture", "team"}, []string{"fixture"}, (*Server).fixturePlayers},
+	"injuries":               {[]string{"league", "season", "fixture", "team", "player", "date", "timezone"}, nil, (*Server).injuries},
+	"predictions":            {[]string{"fixture"}, []string{"fixture"}, (*Server).predictions},
+	"coachs":                 {[]string{"id", "team", "search"}, nil, (*Server).coachs},
+	"players":                {[]string{"id", "team", "league", "season", "search", "page"}, []string{"season"}, (*Server).players},
+	"players/squads":         {[]string{"team", "player"}, nil, (*Server).squads},
+	"players/topscorers":     {[]string{"league", "season"}, []string{"league", "season"}, (*Server).topscorers},
+	"players/topassists":     {[]string{"league", "season"}, []string{"league", "season"}, (*Server).topassists},
+	"players/topyellowcards": {[]string{"league", "season"}, []string{"league", "season"}, (*Server).topyellowcards},
+	"players/topredcards":    {[]string{"league", "season"}, []string{"league", "season"}, (*Server).topredcards},
+	"transfers":              {[]string{"player", "team"}, nil, (*Server).transfers},
+	"trophies":               {[]string{"player", "coach"}, nil, (*Server).trophies},
+	"sidelined":              {[]string{"player", "coach"}, nil, (*Server).sidelined},
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(r.URL.Path, "/")
+	ep, ok := endpoints[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	latency := s.opts.Latency
+	if s.opts.Jitter > 0 {
+		latency += time.Duration(s.rnd.Int63n(int64(s.opts.Jitter)))
+	}
+	failWith := 0
+	if pending := s.failures[name]; len(pending) > 0 {
+		failWith = pending[0]
+		s.failures[name] = pending[1:]
+	} else if s.opts.FailureRate > 0 && s.rnd.Float64() < s.opts.FailureRate {
+		failWith = http.StatusInternalServerError
+	}
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if failWith != 0 {
+		http.Error(w, http.StatusText(failWith), failWith)
+		return
+	}
+
+	q := query{r.URL.Query()}
+	env := envelope{Get: name, Parameters: q.parameters(), Errors: []string{}, Response: []interface{}{}}
+	env.Paging.Current, env.Paging.Total = 1, 1
+
+	errors := s.authorize(r, name)
+	if errors == nil {
+		errors = validate(ep, q)
+	}
+	if errors == nil {
+		res := ep.handle(s, q)
+		errors = res.errors
+		if res.response != nil {
+			env.Response = res.response
+		}
+		if res.pages > 0 {
+			env.Paging.Current, env.Paging.Total = res.page, res.pages
+		}
+	}
+	if errors != nil {
+		env.Errors = errors
+		env.Response = []interface{}{}
+	}
+	env.Results = count(env.Response)
+
+	s.mu.Lock()
+	limit, remaining := s.opts.DailyLimit, s.opts.DailyLimit-s.used
+	s.mu.Unlock()
+	if limit > 0 {
+		w.Header().Set("x-ratelimit-requests-limit", strconv.Itoa(limit))
+		w.Header().Set("x-ratelimit-requests-remaining", strconv.Itoa(remaining))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(env)
+}
+
+// authorize checks the token and counts the request against the quota.
+// The status endpoint is free, as on the real service.
+func (s *Server) authorize(r *http.Request, name string) map[string]string {
+	if s.opts.Token != "" && r.Header.Get("x-apisports-key") != s.opts.Token {
+		return map[string]string{"token": "Error/Missing application key. Go to https://www.api-football.com/documentation-v3 to learn how to get your API application key."}
+	}
+	if name == "status" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.opts.DailyLimit > 0 && s.used >= s.opts.DailyLimit {
+		return map[string]string{"requests": "You have reached the request limit for the day, Go to https://dashboard.api-football.com to upgrade your plan."}
+	}
+	s.used++
+	return nil
+}
+
+func validate(ep endpoint, q query) map[string]string {
+	errors := map[string]string{}
+	for key := range q.values {
+		if !containsString(ep.params, key) {
+			errors[key] = "The " + strings.Title(key) + " field do not exist."
+		}
+	}
+	for _, key := range ep.required {
+		if q.get(key) == "" {
+			errors[key] = "The " + strings.Title(key) + " field is required."
+		}
+	}
+	if len(errors) == 0 {
+		return nil
+	}
+	return errors
+}
+
+func count(response interface{}) int {
+	switch r := response.(type) {
+	case []interface{}:
+		return len(r)
+	case []M:
+		return len(r)
+	case M:
+		return len(r)
+	}
+	return 1
+}
+
+// M is a json object under construction.
+type M = map[string]interface{}
+
+type query struct {
+	values url.Values
+}
+
+func (q query) get(key string) string {
+	return q.values.Get(key)
+}
+
+func (q query) has(key string) bool {
+	return q.values.Get(key) != ""
+}
+
+func (q query) int(key string) int {
+	n, _ := strconv.Atoi(q.values.Get(key))
+	return n
+}
+
+// parameters mirrors the request parameters the way the api echoes them:
+// an object, or an empty array when there are none.
+func (q query) parameters() interface{} {
+	if len(q.values) == 0 {
+		return []string{}
+	}
+	params := map[string]string{}
+	for key := range q.values {
+		params[key] = q.values.Get(key)
+	}
+	return params
+}
+
+// matchesSeason reports whether the optional season parameter is 2021.
+func (q query) matchesSeason() bool {
+	return !q.has("season") || q.int("season") == season
+}
+
+func (s *Server) paginate(items []M, page int) result {
+	if page < 1 {
+		page = 1
+	}
+	pages := (len(items) + s.opts.PageSize - 1) / s.opts.PageSize
+	if pages == 0 {
+		pages = 1
+	}
+	if page > pages {
+		return result{errors: map[string]string{"page": "The Page field must not be greater than " + strconv.Itoa(pages) + "."}}
+	}
+	start := (page - 1) * s.opts.PageSize
+	end := start + s.opts.PageSize
+	if end > len(items) {
+		end = len(items)
+	}
+	return result{response: items[start:end], page: page, pages: pages}
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package apifootball
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NullInt is a number the API sends as null when it does not apply, e.g.
+// the assist of an unassisted goal or the extra time score of a match
+// decided in 90 minutes. Valid is false for null.
+type NullInt struct {
+	Int   int
+	Valid bool
+}
+
+func (n NullInt) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Int)
+}
+
+func (n *NullInt) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullInt{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.Int); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// NullString is a string the API sends as null when it is unknown.
+type NullString struct {
+	String string
+	Valid  bool
+}
+
+func (n NullString) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.String)
+}
+
+func (n *NullString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullString{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.String); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// NullFloat is a decimal the API sends as a number or as a string such as
+// "1.5" (goal averages, ratings). It marshals back the way it arrived.
+type NullFloat struct {
+	Float float64
+	Valid bool
+	text  string
+}
+
+func NewNullFloat(f float64) NullFloat {
+	return NullFloat{Float: f, Valid: true}
+}
+
+func (n NullFloat) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	if n.text != "" {
+		return json.Marshal(n.text)
+	}
+	return json.Marshal(n.Float)
+}
+
+func (n *NullFloat) UnmarshalJSON(data []byte) error {
+	*n = NullFloat{}
+	if string(data) == "null" {
+		return nil
+	}
+	var text string
+	if json.Unmarshal(data, &text) != nil {
+		if err := json.Unmarshal(data, &n.Float); err != nil {
+			return err
+		}
+		n.Valid = true
+		return nil
+	}
+	if text == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return fmt.Errorf("apifootball: invalid decimal %q", text)
+	}
+	*n = NullFloat{f, true, text}
+	return nil
+}
+
+// Percentage is a share the API sends as a string such as "45%" or
+// "16.67%", or null. Value is in percent: 45 for "45%".
+type Percentage struct {
+	Value float64
+	Valid bool
+	text  string
+}
+
+func NewPercentage(value float64) Percentage {
+	return Percentage{Value: value, Valid: true}
+}
+
+// Ratio returns the share between 0 and 1.
+func (p Percentage) Ratio() float64 {
+	return p.Value / 100
+}
+
+func (p Percentage) String() string {
+	if !p.Valid {
+		return ""
+	}
+	if p.text != "" {
+		return p.text
+	}
+	return strconv.FormatFloat(p.Value, 'f', -1, 64) + "%"
+}
+
+func (p Percentage) MarshalJSON() ([]byte, error) {
+	if !p.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(p.String())
+}
+
+func (p *Percentage) UnmarshalJSON(data []byte) error {
+	*p = Percentage{}
+	if string(data) == "null" {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	value, err := parsePercentage(text)
+	if err != nil {
+		return err
+	}
+	*p = Percentage{value, true, text}
+	return nil
+}
+
+func parsePercentage(text string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(text), "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("apifootball: invalid percentage %q", text)
+	}
+	return value, nil
+}
+
+// StatisticValue is a value of fixtures/statistics: a count, a percentage
+// such as "55%" for ball possession, or null.
+type StatisticValue struct {
+	Value   float64
+	Percent bool
+	Valid   bool
+}
+
+func (v StatisticValue) MarshalJSON() ([]byte, error) {
+	if !v.Valid {
+		return []byte("null"), nil
+	}
+	number := strconv.FormatFloat(v.Value, 'f', -1, 64)
+	if v.Percent {
+		return json.Marshal(number + "%")
+	}
+	return []byte(number), nil
+}
+
+func (v *StatisticValue) UnmarshalJSON(data []byte) error {
+	*v = StatisticValue{}
+	if string(data) == "null" {
+		return nil
+	}
+	var text string
+	if json.Unmarshal(data, &text) != nil {
+		if err := json.Unmarshal(data, &v.Value); err != nil {
+			return err
+		}
+		v.Valid = true
+		return nil
+	}
+	value, err := parsePercentage(text)
+	if err != nil {
+		return err
+	}
+	*v = StatisticValue{value, strings.HasSuffix(text, "%"), true}
+	return nil
+}
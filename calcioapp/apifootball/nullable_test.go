@@ -0,0 +1,61 @@
+package apifootball
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNullableRoundTrip(t *testing.T) {
+	tests := []struct {
+		payload string
+		v       interface{}
+	}{
+		{`{"home":null,"away":2}`, &Score{}},
+		{`{"total":null,"percentage":null}`, &MinuteBucket{}},
+		{`{"total":3,"percentage":"16.67%"}`, &MinuteBucket{}},
+		{`{"total":{"home":12,"away":9,"total":21},"average":{"home":"1.5","away":"1.0","total":null},` +
+			`"minute":{"0-15":{"total":2,"percentage":"9.52%"},"16-30":{"total":null,"percentage":null},` +
+			`"31-45":{"total":4,"percentage":"19.05%"},"46-60":{"total":3,"percentage":"14.29%"},` +
+			`"61-75":{"total":5,"percentage":"23.81%"},"76-90":{"total":6,"percentage":"28.57%"},` +
+			`"91-105":{"total":1,"percentage":"4.76%"},"106-120":{"total":null,"percentage":null}}}`, &SeasonGoals{}},
+		{`{"home":"45%","away":"55%"}`, &Comparison{}},
+		{`[{"type":"Shots on Goal","value":5},{"type":"Ball Possession","value":"55%"},{"type":"Red Cards","value":null}]`, &[]struct {
+			Type  string         `json:"type"`
+			Value StatisticValue `json:"value"`
+		}{}},
+	}
+	for _, tt := range tests {
+		mustNot(t, json.Unmarshal([]byte(tt.payload), tt.v))
+		out, err := json.Marshal(tt.v)
+		mustNot(t, err)
+		if string(out) != tt.payload {
+			t.Errorf("round trip changed the payload:\n got %s\nwant %s", out, tt.payload)
+		}
+	}
+}
+
+func TestPercentage(t *testing.T) {
+	var p Percentage
+	mustNot(t, json.Unmarshal([]byte(`"16.67%"`), &p))
+	if !p.Valid || p.Value != 16.67 || p.String() != "16.67%" {
+		t.Errorf("percentage = %+v", p)
+	}
+	mustNot(t, json.Unmarshal([]byte(`null`), &p))
+	if p.Valid {
+		t.Errorf("null percentage is valid")
+	}
+	if out, _ := json.Marshal(NewPercentage(45)); string(out) != `"45%"` {
+		t.Errorf("marshalled %s, want \"45%%\"", out)
+	}
+}
+
+func TestUnplayedFixtureHasNoGoals(t *testing.T) {
+	var fixture Fixture
+	mustNot(t, json.Unmarshal([]byte(`{"goals":{"home":null,"away":null},"score":{"fulltime":{"home":0,"away":0}}}`), &fixture))
+	if fixture.Goals.Home.Valid || fixture.Goals.Away.Valid {
+		t.Errorf("goals of an unplayed fixture = %+v", fixture.Goals)
+	}
+	if !fixture.Score.Fulltime.Home.Valid || fixture.Score.Fulltime.Home.Int != 0 {
+		t.Errorf("0-0 fulltime score = %+v", fixture.Score.Fulltime)
+	}
+}
@@ -0,0 +1,296 @@
+// Package atom writes Atom (RFC 4287) feeds of a team's results, transfers
+// and injuries. Entry ids are derived from the provider's ids only, so a
+// reader refreshing a feed updates the entries it has instead of adding
+// them again.
+package atom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+// ContentType is the media type of a feed.
+const ContentType = "application/atom+xml; charset=utf-8"
+
+// MaxEntries caps the entries of a feed, newest first.
+const MaxEntries = 50
+
+// tag builds a tag URI (RFC 4151) under the app's authority.
+func tag(format string, args ...interface{}) string {
+	return "tag:calcio-app,2021:" + fmt.Sprintf(format, args...)
+}
+
+type Link struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type Person struct {
+	Name string `xml:"name"`
+}
+
+type Category struct {
+	Term string `xml:"term,attr"`
+}
+
+type Text struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type Entry struct {
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    time.Time  `xml:"updated"`
+	Published  time.Time  `xml:"published"`
+	Links      []Link     `xml:"link"`
+	Categories []Category `xml:"category"`
+	Summary    Text       `xml:"summary"`
+}
+
+type Feed struct {
+	XMLName xml.Name  `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string    `xml:"id"`
+	Title   string    `xml:"title"`
+	Updated time.Time `xml:"updated"`
+	Icon    string    `xml:"icon,omitempty"`
+	Links   []Link    `xml:"link"`
+	Author  Person    `xml:"author"`
+	Entries []Entry   `xml:"entry"`
+}
+
+func newFeed(id string, title string, icon string) Feed {
+	return Feed{ID: id, Title: title, Icon: icon, Author: Person{"calcio-app"}, Entries: []Entry{}}
+}
+
+// finish sorts the entries newest first, keeps MaxEntries of them and dates
+// the feed with the newest.
+func (f *Feed) finish() {
+	sort.SliceStable(f.Entries, func(i, j int) bool { return f.Entries[i].Updated.After(f.Entries[j].Updated) })
+	if len(f.Entries) > MaxEntries {
+		f.Entries = f.Entries[:MaxEntries]
+	}
+	if len(f.Entries) > 0 {
+		f.Updated = f.Entries[0].Updated
+	}
+}
+
+// Write writes the feed, served at self. now dates a feed without entries.
+func Write(w io.Writer, feed Feed, self string, now time.Time) error {
+	feed.Links = append([]Link{{Rel: "self", Type: "application/atom+xml", Href: self}}, feed.Links...)
+	if feed.Updated.IsZero() {
+		feed.Updated = now.UTC().Truncate(time.Second)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func score(s apifootball.Score) string {
+	if !s.Home.Valid || !s.Away.Valid {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d", s.Home.Int, s.Away.Int)
+}
+
+func day(t time.Time) string {
+	return t.UTC().Format("2 January 2006")
+}
+
+// Results is the feed of the finished fixtures of a team, linking each to
+// its match report under base.
+func Results(teamId int, fixtures []apifootball.Fixture, base string) Feed {
+	feed := newFeed(tag("team/%d/results", teamId), fmt.Sprintf("Team %d results", teamId), "")
+	for _, f := range fixtures {
+		var goalsFor, goalsAgainst int
+		switch teamId {
+		case f.Teams.Home.ID:
+			feed.Title, feed.Icon = f.Teams.Home.Name+" results", f.Teams.Home.Logo
+			goalsFor, goalsAgainst = f.Goals.Home.Int, f.Goals.Away.Int
+		case f.Teams.Away.ID:
+			feed.Title, feed.Icon = f.Teams.Away.Name+" results", f.Teams.Away.Logo
+			goalsFor, goalsAgainst = f.Goals.Away.Int, f.Goals.Home.Int
+		default:
+			continue
+		}
+		if !standings.Finished(f) {
+			continue
+		}
+		result := "draw"
+		if goalsFor > goalsAgainst {
+			result = "win"
+		} else if goalsFor < goalsAgainst {
+			result = "loss"
+		}
+
+		title := fmt.Sprintf("%s %s %s", f.Teams.Home.Name, score(f.Goals), f.Teams.Away.Name)
+		summary := f.League.Name
+		if f.League.Round != "" {
+			summary += ", " + f.League.Round
+		}
+		summary += ". " + f.Fixture.Status.Long + ": " + title
+		if halftime := score(f.Score.Halftime); halftime != "" {
+			summary += " (HT " + halftime + ")"
+		}
+		if penalties := score(f.Score.Penalty); penalties != "" {
+			summary += ", penalties " + penalties
+		}
+		summary += "."
+		date := f.Fixture.Date.UTC()
+		feed.Entries = append(feed.Entries, Entry{
+			ID:         tag("fixture/%d/result", f.Fixture.ID),
+			Title:      title,
+			Updated:    date,
+			Published:  date,
+			Links:      []Link{{Rel: "alternate", Type: "text/html", Href: fmt.Sprintf("%s/api/fixture/%d/report?format=html", base, f.Fixture.ID)}},
+			Categories: []Category{{f.League.Name}, {result}},
+			Summary:    Text{"text", summary},
+		})
+	}
+	feed.finish()
+	return feed
+}
+
+// Transfers is the feed of the transfers to and from a team. The provider
+// dates them to the day, which is also the last field of their id.
+func Transfers(teamId int, transfers apifootball.Transfers, base string) Feed {
+	feed := newFeed(tag("team/%d/transfers", teamId), fmt.Sprintf("Team %d transfers", teamId), "")
+	seen := map[string]bool{}
+	for _, r := range transfers.Response {
+		for _, t := range r.Transfers {
+			in, out := t.Teams.In, t.Teams.Out
+			var title, category string
+			switch teamId {
+			case in.ID:
+				feed.Title, feed.Icon = in.Name+" transfers", in.Logo
+				title, category = fmt.Sprintf("%s joins from %s", r.Player.Name, out.Name), "arrival"
+			case out.ID:
+				feed.Title, feed.Icon = out.Name+" transfers", out.Logo
+				title, category = fmt.Sprintf("%s leaves for %s", r.Player.Name, in.Name), "departure"
+			default:
+				continue
+			}
+			id := tag("transfer/%d/%s/%d-%d", r.Player.ID, t.Date, out.ID, in.ID)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			date, err := time.Parse("2006-01-02", t.Date)
+			if err != nil {
+				date = r.Update.UTC()
+			}
+			summary := fmt.Sprintf("%s moved from %s to %s on %s", r.Player.Name, out.Name, in.Name, day(date))
+			if t.Type != "" && t.Type != "N/A" {
+				summary += " (" + t.Type + ")"
+			}
+			feed.Entries = append(feed.Entries, Entry{
+				ID:         id,
+				Title:      title,
+				Updated:    date,
+				Published:  date,
+				Links:      []Link{{Rel: "related", Type: "application/json", Href: fmt.Sprintf("%s/api/apiFootball/player/%d/transfers", base, r.Player.ID)}},
+				Categories: []Category{{category}},
+				Summary:    Text{"text", summary + "."},
+			})
+		}
+	}
+	feed.finish()
+	return feed
+}
+
+// absence is a run of fixtures of the team a player missed for one reason.
+type absence struct {
+	player          apifootball.Player
+	kind, reason    string
+	first, last     time.Time
+	firstId, lastId int
+	position        int
+	missed          int
+}
+
+// Injuries is the feed of the absences of a team's players. The provider
+// lists a player once per fixture missed: consecutive fixtures of the team
+// missed for the same reason make one entry. Its id is that of the first
+// fixture missed, and it is updated as the absence lasts.
+func Injuries(teamId int, injuries apifootball.Injuries, fixtures []apifootball.Fixture, base string) Feed {
+	feed := newFeed(tag("team/%d/injuries", teamId), fmt.Sprintf("Team %d injuries", teamId), "")
+	sorted := make([]apifootball.Fixture, len(fixtures))
+	copy(sorted, fixtures)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Fixture.Date.Before(sorted[j].Fixture.Date) })
+	positions := map[int]int{}
+	for i, f := range sorted {
+		positions[f.Fixture.ID] = i
+		if f.Teams.Home.ID == teamId {
+			feed.Title, feed.Icon = f.Teams.Home.Name+" injuries", f.Teams.Home.Logo
+		} else if f.Teams.Away.ID == teamId {
+			feed.Title, feed.Icon = f.Teams.Away.Name+" injuries", f.Teams.Away.Logo
+		}
+	}
+
+	rows := make([]int, 0, len(injuries.Response))
+	for i, r := range injuries.Response {
+		if r.Team.ID == teamId {
+			rows = append(rows, i)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return injuries.Response[rows[i]].Fixture.Date.Before(injuries.Response[rows[j]].Fixture.Date)
+	})
+	var absences []*absence
+	current := map[int]*absence{}
+	for _, i := range rows {
+		r := injuries.Response[i]
+		position, known := positions[r.Fixture.ID]
+		if !known {
+			// Fixtures the team list does not have, e.g. of another
+			// season, do not break an absence.
+			position = -1
+		}
+		a, ok := current[r.Player.ID]
+		if ok && a.lastId == r.Fixture.ID {
+			continue
+		}
+		if ok && a.reason == r.Player.Reason && a.kind == r.Player.Type && (position < 0 || a.position < 0 || position == a.position+1) {
+			a.last, a.lastId, a.position = r.Fixture.Date, r.Fixture.ID, position
+			a.missed++
+			continue
+		}
+		a = &absence{r.Player.Player, r.Player.Type, r.Player.Reason, r.Fixture.Date, r.Fixture.Date, r.Fixture.ID, r.Fixture.ID, position, 1}
+		current[r.Player.ID] = a
+		absences = append(absences, a)
+	}
+
+	for _, a := range absences {
+		matches := "1 match, on " + day(a.first)
+		if a.missed > 1 {
+			matches = strconv.Itoa(a.missed) + " matches, from " + day(a.first) + " to " + day(a.last)
+		}
+		feed.Entries = append(feed.Entries, Entry{
+			ID:         tag("injury/%d/%d", a.player.ID, a.firstId),
+			Title:      fmt.Sprintf("%s out: %s", a.player.Name, a.reason),
+			Updated:    a.last.UTC(),
+			Published:  a.first.UTC(),
+			Links:      []Link{{Rel: "related", Type: "application/json", Href: fmt.Sprintf("%s/api/apiFootball/player/%d/sidelined", base, a.player.ID)}},
+			Categories: []Category{{a.kind}},
+			Summary:    Text{"text", fmt.Sprintf("%s (%s) misses %s.", a.player.Name, a.reason, matches)},
+		})
+	}
+	feed.finish()
+	return feed
+}
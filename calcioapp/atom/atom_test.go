@@ -0,0 +1,132 @@
+package atom
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+	"github.com/nero-15/calcio-app/internal/fixturetest"
+)
+
+// fixture is a 2-1 of the first round, where team 1 is Inter.
+func fixture(id int, day int, home int, away int) apifootball.Fixture {
+	f := fixturetest.Finished(id, time.Date(2021, time.September, day, 18, 45, 0, 0, time.UTC), home, away, 2, 1)
+	f.League.Round = "Regular Season - 1"
+	if home == 1 {
+		f.Teams.Home.Name = "Inter"
+	}
+	if away == 1 {
+		f.Teams.Away.Name = "Inter"
+	}
+	return f
+}
+
+func TestInjuries(t *testing.T) {
+	fixtures := []apifootball.Fixture{fixture(1, 1, 1, 2), fixture(2, 8, 3, 1), fixture(3, 15, 1, 4), fixture(4, 22, 5, 1), fixture(5, 29, 1, 6)}
+	var injuries apifootball.Injuries
+	// Player 7 misses 1 and 2, plays 3 and misses 4 for the same reason;
+	// player 8 misses 3 to 5, listed twice for 4 and out of order.
+	json.Unmarshal([]byte(`{"response": [
+		{"player": {"id": 7, "name": "A", "type": "Missing Fixture", "reason": "Knee Injury"}, "team": {"id": 1, "name": "Inter"}, "fixture": {"id": 1, "date": "2021-09-01T18:45:00Z"}},
+		{"player": {"id": 8, "name": "B", "type": "Missing Fixture", "reason": "Illness"}, "team": {"id": 1, "name": "Inter"}, "fixture": {"id": 4, "date": "2021-09-22T18:45:00Z"}},
+		{"player": {"id": 7, "name": "A", "type": "Missing Fixture", "reason": "Knee Injury"}, "team": {"id": 1, "name": "Inter"}, "fixture": {"id": 2, "date": "2021-09-08T18:45:00Z"}},
+		{"player": {"id": 8, "name": "B", "type": "Missing Fixture", "reason": "Illness"}, "team": {"id": 1, "name": "Inter"}, "fixture": {"id": 3, "date": "2021-09-15T18:45:00Z"}},
+		{"player": {"id": 8, "name": "B", "type": "Missing Fixture", "reason": "Illness"}, "team": {"id": 1, "name": "Inter"}, "fixture": {"id": 4, "date": "2021-09-22T18:45:00Z"}},
+		{"player": {"id": 7, "name": "A", "type": "Missing Fixture", "reason": "Knee Injury"}, "team": {"id": 1, "name": "Inter"}, "fixture": {"id": 4, "date": "2021-09-22T18:45:00Z"}},
+		{"player": {"id": 8, "name": "B", "type": "Missing Fixture", "reason": "Illness"}, "team": {"id": 1, "name": "Inter"}, "fixture": {"id": 5, "date": "2021-09-29T18:45:00Z"}},
+		{"player": {"id": 9, "name": "C", "type": "Missing Fixture", "reason": "Illness"}, "team": {"id": 2, "name": "Other"}, "fixture": {"id": 1, "date": "2021-09-01T18:45:00Z"}}]}`), &injuries)
+
+	feed := Injuries(1, injuries, fixtures, "http://localhost")
+	if feed.Title != "Inter injuries" || len(feed.Entries) != 3 {
+		t.Fatalf("feed %q has %d entries: %+v", feed.Title, len(feed.Entries), feed.Entries)
+	}
+	want := []struct {
+		id, summary string
+	}{
+		{"tag:calcio-app,2021:injury/8/3", "B (Illness) misses 3 matches, from 15 September 2021 to 29 September 2021."},
+		{"tag:calcio-app,2021:injury/7/4", "A (Knee Injury) misses 1 match, on 22 September 2021."},
+		{"tag:calcio-app,2021:injury/7/1", "A (Knee Injury) misses 2 matches, from 1 September 2021 to 8 September 2021."},
+	}
+	for i, w := range want {
+		if e := feed.Entries[i]; e.ID != w.id || e.Summary.Body != w.summary {
+			t.Errorf("entry %d = %s %q, want %s %q", i, e.ID, e.Summary.Body, w.id, w.summary)
+		}
+	}
+	if !feed.Updated.Equal(fixtures[4].Fixture.Date) {
+		t.Errorf("feed updated %v", feed.Updated)
+	}
+}
+
+func TestTransfers(t *testing.T) {
+	var transfers apifootball.Transfers
+	json.Unmarshal([]byte(`{"response": [{"player": {"id": 7, "name": "A"}, "update": "2021-10-01T00:00:00Z", "transfers": [
+		{"date": "2021-07-01", "type": "Loan", "teams": {"in": {"id": 1, "name": "Inter"}, "out": {"id": 2, "name": "Other"}}},
+		{"date": "2021-07-01", "type": "Loan", "teams": {"in": {"id": 1, "name": "Inter"}, "out": {"id": 2, "name": "Other"}}},
+		{"date": "2019-01-15", "type": "N/A", "teams": {"in": {"id": 2, "name": "Other"}, "out": {"id": 3, "name": "Third"}}},
+		{"date": "2018-08-01", "type": "€ 5M", "teams": {"in": {"id": 3, "name": "Third"}, "out": {"id": 1, "name": "Inter"}}}]}]}`), &transfers)
+
+	feed := Transfers(1, transfers, "http://localhost")
+	if len(feed.Entries) != 2 {
+		t.Fatalf("entries = %+v", feed.Entries)
+	}
+	if e := feed.Entries[0]; e.ID != "tag:calcio-app,2021:transfer/7/2021-07-01/2-1" || e.Title != "A joins from Other" || e.Summary.Body != "A moved from Other to Inter on 1 July 2021 (Loan)." {
+		t.Errorf("arrival = %+v", e)
+	}
+	if e := feed.Entries[1]; e.Title != "A leaves for Third" || e.Categories[0].Term != "departure" {
+		t.Errorf("departure = %+v", e)
+	}
+}
+
+func TestFeeds(t *testing.T) {
+	ts := httptest.NewServer(fake.New(fake.Options{DailyLimit: -1}))
+	defer ts.Close()
+	client := apifootball.New("test-token", ts.URL+"/")
+
+	fixtures, err := client.GetFixturesByTeamId("505")
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfers, err := client.GetTransfersByTeamId("505")
+	if err != nil {
+		t.Fatal(err)
+	}
+	injuries, err := client.GetInjuriesByTeamId("505")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, feed := range []Feed{
+		Results(505, fixtures.Response, "http://localhost"),
+		Transfers(505, transfers, "http://localhost"),
+		Injuries(505, injuries, fixtures.Response, "http://localhost"),
+	} {
+		if len(feed.Entries) == 0 || !strings.HasPrefix(feed.Title, "Inter ") {
+			t.Errorf("%s: %q with %d entries", feed.ID, feed.Title, len(feed.Entries))
+		}
+		var b bytes.Buffer
+		if err := Write(&b, feed, "http://localhost/feed.atom", time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		var parsed struct {
+			XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+			Entries []struct {
+				ID string `xml:"id"`
+			} `xml:"entry"`
+		}
+		if err := xml.Unmarshal(b.Bytes(), &parsed); err != nil {
+			t.Fatalf("%s: %v", feed.ID, err)
+		}
+		ids := map[string]bool{}
+		for _, e := range parsed.Entries {
+			if ids[e.ID] {
+				t.Errorf("%s: entry id %s repeated", feed.ID, e.ID)
+			}
+			ids[e.ID] = true
+		}
+	}
+}
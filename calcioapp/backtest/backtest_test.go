@@ -0,0 +1,173 @@
+package backtest
+
+import (
+	"context"
+	"math"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+)
+
+var kickoff = time.Date(2021, 9, 12, 18, 0, 0, 0, time.UTC)
+
+func finished(id int, home int, away int) apifootball.Fixture {
+	var f apifootball.Fixture
+	f.Fixture.ID = id
+	f.Fixture.Date = kickoff
+	f.Fixture.Status.Short = "FT"
+	f.Score.Fulltime.Home = apifootball.NullInt{Int: home, Valid: true}
+	f.Score.Fulltime.Away = apifootball.NullInt{Int: away, Valid: true}
+	f.Goals = f.Score.Fulltime
+	return f
+}
+
+func prediction(id int, source string, home float64, draw float64, away float64) Record {
+	return Record{
+		FixtureID: id, League: League{135, "Serie A", 2021}, Kickoff: kickoff,
+		CapturedAt: kickoff.Add(-time.Hour), Source: source, Home: home, Draw: draw, Away: away,
+	}
+}
+
+func TestStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "predictions.json")
+	store, err := Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	late := prediction(1, SourceProvider, 0.5, 0.3, 0.2)
+	late.CapturedAt = kickoff
+	if stored, err := store.Add(late); stored || err != ErrAfterKickoff {
+		t.Errorf("Add after kickoff = %v, %v", stored, err)
+	}
+
+	early, latest := prediction(1, SourceProvider, 0.4, 0.3, 0.3), prediction(1, SourceProvider, 0.6, 0.2, 0.2)
+	early.CapturedAt = kickoff.Add(-48 * time.Hour)
+	store.Add(latest)
+	if stored, _ := store.Add(early); stored {
+		t.Error("an earlier capture replaced a later one")
+	}
+	store.Add(prediction(1, SourceModel, 0.5, 0.25, 0.25))
+	records := store.Records()
+	if len(records) != 2 || records[1] != latest {
+		t.Errorf("records = %+v", records)
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Records(); !reflect.DeepEqual(got, records) {
+		t.Errorf("reopened records = %+v", got)
+	}
+	if err := (&Store{}).Save(); err == nil {
+		t.Error("a store without file was saved")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	aet := finished(3, 1, 1)
+	aet.Fixture.Status.Short = "AET"
+	aet.Goals.Home.Int = 2
+	upcoming := finished(4, 0, 0)
+	upcoming.Score.Fulltime = apifootball.Score{}
+	fixtures := []apifootball.Fixture{finished(1, 2, 0), finished(2, 0, 1), aet, upcoming}
+
+	third := 1.0 / 3
+	records := []Record{
+		prediction(1, SourceProvider, 1, 0, 0),
+		prediction(2, SourceProvider, 0, 0, 1),
+		prediction(3, SourceProvider, 0, 1, 0),
+		prediction(4, SourceProvider, 1, 0, 0),
+		prediction(1, SourceModel, third, third, third),
+		prediction(2, SourceModel, 40, 30, 30),
+	}
+	report := Evaluate(records, fixtures, Filter{})
+	if report.Pending != 1 || len(report.Scores) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	model, provider := report.Scores[0], report.Scores[1]
+	if provider.Source != SourceProvider || provider.Predictions != 3 || provider.Brier != 0 || provider.LogLoss != 0 {
+		t.Errorf("a perfect provider scored %+v", provider)
+	}
+	// Fixture 2 is 0.4/0.3/0.3 once normalized and ended in an away win.
+	wantBrier := (2.0/3 + (0.4*0.4 + 0.3*0.3 + 0.7*0.7)) / 2
+	wantLogLoss := (math.Log(3) - math.Log(0.3)) / 2
+	if math.Abs(model.Brier-wantBrier) > 1e-9 || math.Abs(model.LogLoss-wantLogLoss) > 1e-9 {
+		t.Errorf("model scored %f and %f, want %f and %f", model.Brier, model.LogLoss, wantBrier, wantLogLoss)
+	}
+
+	var counted int
+	for _, bucket := range provider.Calibration {
+		counted += bucket.Count
+		if bucket.Predicted != bucket.Observed {
+			t.Errorf("perfect predictions miscalibrated: %+v", bucket)
+		}
+	}
+	if counted != 9 || len(provider.Calibration) != 2 {
+		t.Errorf("calibration = %+v", provider.Calibration)
+	}
+
+	if only := Evaluate(records, fixtures, Filter{Source: SourceModel, Season: 2021, LeagueID: 135}); len(only.Scores) != 1 {
+		t.Errorf("filtered report = %+v", only)
+	}
+}
+
+func TestCaptureAndBackfill(t *testing.T) {
+	server := httptest.NewServer(fake.New(fake.Options{DailyLimit: -1}))
+	defer server.Close()
+	client := apifootball.New("test-token", server.URL+"/")
+	store, err := Open(filepath.Join(t.TempDir(), "predictions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+	capturer := &Capturer{Client: client, Store: store, Now: func() time.Time { return now }}
+	added, err := capturer.Capture(context.Background(), 135)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added == 0 || added%2 != 0 {
+		t.Errorf("captured %d predictions, want one per source and fixture", added)
+	}
+	for _, record := range store.Records() {
+		if !record.CapturedAt.Before(record.Kickoff) || record.Kickoff.Sub(now) > 7*24*time.Hour {
+			t.Errorf("captured %+v", record)
+		}
+	}
+	// Capturing again an hour earlier keeps the later predictions.
+	earlier := &Capturer{Client: client, Store: store, Now: func() time.Time { return now.Add(-time.Hour) }}
+	if again, err := earlier.Capture(context.Background(), 135); err != nil || again != 0 {
+		t.Errorf("earlier capture stored %d predictions, %v", again, err)
+	}
+	unsaved, _ := Open("")
+	if _, err := (&Capturer{Client: client, Store: unsaved, Now: capturer.Now}).Capture(context.Background(), 135); err == nil {
+		t.Error("a store that cannot be saved did not fail the capture")
+	}
+
+	fixtures, err := client.GetFixturesByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Backfill(store, fixtures.Response) == 0 {
+		t.Fatal("nothing backfilled")
+	}
+	report, err := Run(context.Background(), client, store, Filter{LeagueID: 135})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Pending != added || len(report.Scores) != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	if score := report.Scores[0]; score.Source != SourceModel || score.Brier >= 2.0/3 {
+		t.Errorf("backfilled model did no better than a coin: %+v", score)
+	}
+}
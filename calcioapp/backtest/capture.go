@@ -0,0 +1,146 @@
+package backtest
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/logging"
+	"github.com/nero-15/calcio-app/outcome"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+// Capturer records the predictions of upcoming fixtures.
+type Capturer struct {
+	Client *apifootball.APIClient
+	Store  *Store
+	// Window is how far ahead fixtures are captured. Defaults to 7 days;
+	// every fixture costs a request to the predictions endpoint.
+	Window time.Duration
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+func (c *Capturer) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// Capture stores the provider's and the model's predictions of the league's
+// fixtures kicking off within the window, saves the store and returns the
+// number of predictions stored.
+func (c *Capturer) Capture(ctx context.Context, leagueId int) (int, error) {
+	window := c.Window
+	if window <= 0 {
+		window = 7 * 24 * time.Hour
+	}
+	now := c.now()
+	client := c.Client.WithContext(ctx)
+	fixtures, err := client.GetFixturesByLeagueId(strconv.Itoa(leagueId))
+	if err != nil {
+		return 0, err
+	}
+	model := outcome.Fit(fixtures.Response, now, outcome.Options{})
+
+	added := 0
+	for _, fixture := range fixtures.Response {
+		kickoff := fixture.Fixture.Date
+		if !kickoff.After(now) || kickoff.Sub(now) > window || !upcoming(fixture) {
+			continue
+		}
+		prediction := model.Predict(fixture.Teams.Home.ID, fixture.Teams.Away.ID).Outcome
+		if stored, _ := c.Store.Add(record(fixture, SourceModel, now, prediction.Home, prediction.Draw, prediction.Away)); stored {
+			added++
+		}
+
+		predictions, err := client.GetPredictionsByFixtureId(strconv.Itoa(fixture.Fixture.ID))
+		if err != nil || len(predictions.Response) == 0 {
+			logging.FromContext(ctx).WithError(err).WithField("fixture", fixture.Fixture.ID).Warn("backtest: no provider prediction")
+			continue
+		}
+		percent := predictions.Response[0].Predictions.Percent
+		if !percent.Home.Valid || !percent.Draw.Valid || !percent.Away.Valid {
+			continue
+		}
+		if stored, _ := c.Store.Add(record(fixture, SourceProvider, now, percent.Home.Ratio(), percent.Draw.Ratio(), percent.Away.Ratio())); stored {
+			added++
+		}
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	return added, c.Store.Save()
+}
+
+// Backfill stores the model's predictions of the finished fixtures, each
+// fitted only to the fixtures finished before its kickoff day, so the model
+// has a history to compare with from the start. It returns the number of
+// predictions stored; the caller saves the store.
+func Backfill(store *Store, fixtures []apifootball.Fixture) int {
+	models := map[time.Time]outcome.Model{}
+	added := 0
+	for _, fixture := range fixtures {
+		if !standings.Finished(fixture) {
+			continue
+		}
+		kickoff := fixture.Fixture.Date
+		day := time.Date(kickoff.Year(), kickoff.Month(), kickoff.Day(), 0, 0, 0, 0, kickoff.Location())
+		model, ok := models[day]
+		if !ok {
+			model = outcome.Fit(fixtures, day, outcome.Options{})
+			models[day] = model
+		}
+		if model.Fixtures == 0 {
+			continue
+		}
+		prediction := model.Predict(fixture.Teams.Home.ID, fixture.Teams.Away.ID).Outcome
+		if stored, _ := store.Add(record(fixture, SourceModel, day, prediction.Home, prediction.Draw, prediction.Away)); stored {
+			added++
+		}
+	}
+	return added
+}
+
+// Run fetches the fixtures of the leagues in the store and evaluates
+// the records passing filter.
+func Run(ctx context.Context, client *apifootball.APIClient, store *Store, filter Filter) (Report, error) {
+	records := store.Records()
+	var fixtures []apifootball.Fixture
+	fetched := map[int]bool{}
+	for _, record := range records {
+		if !filter.keep(record) || fetched[record.League.ID] {
+			continue
+		}
+		fetched[record.League.ID] = true
+		leagueFixtures, err := client.WithContext(ctx).GetFixturesByLeagueId(strconv.Itoa(record.League.ID))
+		if err != nil {
+			return Report{}, err
+		}
+		fixtures = append(fixtures, leagueFixtures.Response...)
+	}
+	return Evaluate(records, fixtures, filter), nil
+}
+
+func upcoming(fixture apifootball.Fixture) bool {
+	switch fixture.Fixture.Status.Short {
+	case "NS", "TBD":
+		return true
+	}
+	return false
+}
+
+func record(fixture apifootball.Fixture, source string, capturedAt time.Time, home float64, draw float64, away float64) Record {
+	return Record{
+		FixtureID:  fixture.Fixture.ID,
+		League:     League{fixture.League.ID, fixture.League.Name, fixture.League.Season},
+		Kickoff:    fixture.Fixture.Date,
+		CapturedAt: capturedAt,
+		Source:     source,
+		Home:       home,
+		Draw:       draw,
+		Away:       away,
+	}
+}
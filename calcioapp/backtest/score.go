@@ -0,0 +1,161 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+// Buckets is the number of calibration buckets, each a tenth wide.
+const Buckets = 10
+
+// epsilon bounds the log loss of a result predicted impossible.
+const epsilon = 1e-15
+
+// Bucket compares the predicted probability of outcomes with how often
+// they happened.
+type Bucket struct {
+	From      float64 `json:"from"`
+	To        float64 `json:"to"`
+	Count     int     `json:"count"`
+	Predicted float64 `json:"predicted"`
+	Observed  float64 `json:"observed"`
+}
+
+// Score is the accuracy of one source in one league and season.
+type Score struct {
+	League      League `json:"league"`
+	Source      string `json:"source"`
+	Predictions int    `json:"predictions"`
+	// Brier is the mean squared error over home, draw and away: 0 is perfect,
+	// 2/3 is what always predicting a third scores.
+	Brier float64 `json:"brier"`
+	// LogLoss is the mean negative log probability of the result: ln 3 for
+	// always predicting a third.
+	LogLoss     float64  `json:"logLoss"`
+	Calibration []Bucket `json:"calibration"`
+}
+
+type Report struct {
+	// Pending counts records of fixtures without a full time score yet.
+	Pending int     `json:"pending"`
+	Scores  []Score `json:"scores"`
+}
+
+// Filter selects the records of a report.
+type Filter struct {
+	LeagueID int
+	Season   int
+	Source   string
+}
+
+func (f Filter) keep(record Record) bool {
+	return (f.LeagueID == 0 || record.League.ID == f.LeagueID) &&
+		(f.Season == 0 || record.League.Season == f.Season) &&
+		(f.Source == "" || record.Source == f.Source)
+}
+
+// Evaluate scores the records against the full time score of the fixtures:
+// the result after 90 minutes, whatever happened in extra time.
+func Evaluate(records []Record, fixtures []apifootball.Fixture, filter Filter) Report {
+	results := map[int][3]float64{}
+	for _, fixture := range fixtures {
+		fulltime := fixture.Score.Fulltime
+		if !fulltime.Home.Valid || !fulltime.Away.Valid {
+			continue
+		}
+		switch {
+		case fulltime.Home.Int > fulltime.Away.Int:
+			results[fixture.Fixture.ID] = [3]float64{1, 0, 0}
+		case fulltime.Home.Int < fulltime.Away.Int:
+			results[fixture.Fixture.ID] = [3]float64{0, 0, 1}
+		default:
+			results[fixture.Fixture.ID] = [3]float64{0, 1, 0}
+		}
+	}
+
+	type group struct {
+		score           Score
+		count, observed [Buckets]float64
+		predicted       [Buckets]float64
+	}
+	groups := map[string]*group{}
+	var report Report
+	for _, record := range records {
+		if !filter.keep(record) {
+			continue
+		}
+		result, ok := results[record.FixtureID]
+		if !ok {
+			report.Pending++
+			continue
+		}
+		name := fmt.Sprintf("%s/%d/%d", record.Source, record.League.ID, record.League.Season)
+		g, ok := groups[name]
+		if !ok {
+			g = &group{score: Score{League: record.League, Source: record.Source}}
+			groups[name] = g
+		}
+		g.score.Predictions++
+		predicted := normalize(record)
+		for i, p := range predicted {
+			g.score.Brier += (p - result[i]) * (p - result[i])
+			if result[i] == 1 {
+				g.score.LogLoss -= math.Log(math.Max(p, epsilon))
+			}
+			b := int(p * Buckets)
+			if b == Buckets {
+				b--
+			}
+			g.count[b]++
+			g.predicted[b] += p
+			g.observed[b] += result[i]
+		}
+	}
+
+	for _, g := range groups {
+		n := float64(g.score.Predictions)
+		g.score.Brier /= n
+		g.score.LogLoss /= n
+		for b := 0; b < Buckets; b++ {
+			if g.count[b] == 0 {
+				continue
+			}
+			g.score.Calibration = append(g.score.Calibration, Bucket{
+				From:      float64(b) / Buckets,
+				To:        float64(b+1) / Buckets,
+				Count:     int(g.count[b]),
+				Predicted: g.predicted[b] / g.count[b],
+				Observed:  g.observed[b] / g.count[b],
+			})
+		}
+		report.Scores = append(report.Scores, g.score)
+	}
+	sort.Slice(report.Scores, func(i, j int) bool {
+		a, b := report.Scores[i], report.Scores[j]
+		if a.League.ID != b.League.ID {
+			return a.League.ID < b.League.ID
+		}
+		if a.League.Season != b.League.Season {
+			return a.League.Season < b.League.Season
+		}
+		return a.Source < b.Source
+	})
+	return report
+}
+
+// normalize makes the probabilities of a record sum to 1; the provider's
+// are rounded percentages.
+func normalize(record Record) [3]float64 {
+	p := [3]float64{record.Home, record.Draw, record.Away}
+	sum := p[0] + p[1] + p[2]
+	if sum <= 0 {
+		return [3]float64{1.0 / 3, 1.0 / 3, 1.0 / 3}
+	}
+	for i := range p {
+		p[i] /= sum
+	}
+	return p
+}
@@ -0,0 +1,142 @@
+// Package backtest keeps predictions captured before kickoff and scores
+// them against the final results, to tell how far they can be trusted.
+package backtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// SourceProvider is the provider's predictions endpoint.
+	SourceProvider = "provider"
+	// SourceModel is the in-house Dixon-Coles model.
+	SourceModel = "model"
+)
+
+// ErrAfterKickoff rejects a prediction captured once the fixture started.
+var ErrAfterKickoff = errors.New("backtest: prediction captured after kickoff")
+
+type League struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Season int    `json:"season"`
+}
+
+// Record is one prediction of a fixture's result.
+type Record struct {
+	FixtureID  int       `json:"fixtureId"`
+	League     League    `json:"league"`
+	Kickoff    time.Time `json:"kickoff"`
+	CapturedAt time.Time `json:"capturedAt"`
+	Source     string    `json:"source"`
+	Home       float64   `json:"home"`
+	Draw       float64   `json:"draw"`
+	Away       float64   `json:"away"`
+}
+
+// Store keeps the latest prediction captured before kickoff of each
+// fixture and source, saved to a json file.
+type Store struct {
+	mu      sync.Mutex
+	file    string
+	records map[string]Record
+}
+
+// Open reads the store saved to file. A missing file gives an empty store;
+// an empty file name gives one that cannot be saved.
+func Open(file string) (*Store, error) {
+	s := &Store{file: file, records: map[string]Record{}}
+	if file == "" {
+		return s, nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("backtest: %s: %v", file, err)
+	}
+	for _, record := range records {
+		s.records[key(record)] = record
+	}
+	return s, nil
+}
+
+func key(record Record) string {
+	return fmt.Sprintf("%d/%s", record.FixtureID, record.Source)
+}
+
+// Add keeps record unless it was captured after kickoff or an existing
+// record of the fixture was captured closer to it, and reports whether it
+// kept it.
+func (s *Store) Add(record Record) (bool, error) {
+	if !record.CapturedAt.Before(record.Kickoff) {
+		return false, ErrAfterKickoff
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if old, ok := s.records[key(record)]; ok && old.CapturedAt.After(record.CapturedAt) {
+		return false, nil
+	}
+	s.records[key(record)] = record
+	return true, nil
+}
+
+// Records lists the records from the earliest kickoff.
+func (s *Store) Records() []Record {
+	s.mu.Lock()
+	records := make([]Record, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+	s.mu.Unlock()
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].Kickoff.Equal(records[j].Kickoff) {
+			return records[i].Kickoff.Before(records[j].Kickoff)
+		}
+		if records[i].FixtureID != records[j].FixtureID {
+			return records[i].FixtureID < records[j].FixtureID
+		}
+		return records[i].Source < records[j].Source
+	})
+	return records
+}
+
+// Save writes the store to its file, replacing it atomically.
+func (s *Store) Save() error {
+	if s.file == "" {
+		return errors.New("backtest: store has no file")
+	}
+	out, err := json.MarshalIndent(s.Records(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.file), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.file), filepath.Base(s.file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(out, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.file)
+}
@@ -0,0 +1,92 @@
+// Package cache keeps values that cost many upstream requests, such as the
+// statistics of every team of a league, for a while.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nero-15/calcio-app/logging"
+	"github.com/nero-15/calcio-app/metrics"
+)
+
+// DefaultTimeout bounds a fetch, which outlives the request that started it.
+const DefaultTimeout = 5 * time.Minute
+
+type entry struct {
+	value   interface{}
+	fetched time.Time
+}
+
+// call is a fetch in flight, shared by every miss on its key.
+type call struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// Cache keeps a value per key for TTL. Concurrent misses on a key make one
+// fetch and share its result, so a burst of cold requests costs the
+// upstream requests of one. Errors are returned but not kept.
+type Cache struct {
+	TTL     time.Duration
+	Timeout time.Duration
+
+	// name labels the cache in the hit and miss metrics.
+	name     string
+	mu       sync.Mutex
+	entries  map[string]entry
+	inFlight map[string]*call
+}
+
+func New(name string, ttl time.Duration) *Cache {
+	return &Cache{TTL: ttl, Timeout: DefaultTimeout, name: name, entries: map[string]entry{}, inFlight: map[string]*call{}}
+}
+
+// Get returns the value of key, calling fetch when it is not cached or
+// older than the TTL and no other caller is already fetching it. The fetch
+// gets the request id of ctx but not its cancellation, since every waiter
+// shares it; a waiter gives up when its own ctx is done.
+func (c *Cache) Get(ctx context.Context, key string, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && time.Since(e.fetched) < c.TTL {
+		c.mu.Unlock()
+		metrics.CacheHit(c.name)
+		return e.value, nil
+	}
+	metrics.CacheMiss(c.name)
+	if pending, ok := c.inFlight[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-pending.done:
+			return pending.value, pending.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	pending := &call{done: make(chan struct{})}
+	c.inFlight[key] = pending
+	c.mu.Unlock()
+
+	// Release the waiters even when fetch panics.
+	fetched := false
+	defer func() {
+		if !fetched {
+			pending.err = fmt.Errorf("cache: fetch of %s %s panicked", c.name, key)
+		}
+		c.mu.Lock()
+		delete(c.inFlight, key)
+		if pending.err == nil {
+			c.entries[key] = entry{pending.value, time.Now()}
+		}
+		c.mu.Unlock()
+		close(pending.done)
+	}()
+	fetchCtx, cancel := context.WithTimeout(logging.Detach(ctx), c.Timeout)
+	defer cancel()
+	pending.value, pending.err = fetch(fetchCtx)
+	fetched = true
+	return pending.value, pending.err
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGet(t *testing.T) {
+	c := New("test", time.Hour)
+	fetches := 0
+	fetch := func(ctx context.Context) (interface{}, error) {
+		fetches++
+		return fetches, nil
+	}
+	for i := 0; i < 2; i++ {
+		if v, err := c.Get(context.Background(), "a", fetch); err != nil || v != 1 || fetches != 1 {
+			t.Errorf("get %d = %v, %v after %d fetches", i, v, err, fetches)
+		}
+	}
+	if v, _ := c.Get(context.Background(), "b", fetch); v != 2 {
+		t.Errorf("another key = %v", v)
+	}
+
+	c.TTL = 0
+	if v, _ := c.Get(context.Background(), "a", fetch); v != 3 {
+		t.Errorf("expired key = %v", v)
+	}
+}
+
+func TestGetError(t *testing.T) {
+	c := New("test", time.Hour)
+	if _, err := c.Get(context.Background(), "a", func(ctx context.Context) (interface{}, error) { return nil, errors.New("quota") }); err == nil {
+		t.Fatal("no error")
+	}
+	if v, err := c.Get(context.Background(), "a", func(ctx context.Context) (interface{}, error) { return 1, nil }); err != nil || v != 1 {
+		t.Errorf("error was cached: %v, %v", v, err)
+	}
+}
+
+func TestGetConcurrentMisses(t *testing.T) {
+	c := New("test", time.Hour)
+	var fetches int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return "league", nil
+	}
+
+	var wg sync.WaitGroup
+	values := make([]interface{}, 10)
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values[i], _ = c.Get(context.Background(), "a", fetch)
+		}(i)
+	}
+	// Let every caller miss before the fetch returns.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if fetches != 1 {
+		t.Errorf("%d fetches for concurrent misses", fetches)
+	}
+	for i, v := range values {
+		if v != "league" {
+			t.Errorf("caller %d got %v", i, v)
+		}
+	}
+}
+
+func TestGetCancelledCaller(t *testing.T) {
+	c := New("test", time.Hour)
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (interface{}, error) {
+		<-release
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return "league", nil
+	}
+
+	// The caller making the fetch goes away before it returns.
+	first, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Get(first, "a", fetch)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	waiter := make(chan interface{})
+	go func() {
+		v, _ := c.Get(context.Background(), "a", fetch)
+		waiter <- v
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	close(release)
+	if v := <-waiter; v != "league" {
+		t.Errorf("waiter got %v after the first caller went away", v)
+	}
+	<-done
+}
+
+func TestGetPanic(t *testing.T) {
+	c := New("test", time.Hour)
+	release := make(chan struct{})
+	waiter := make(chan error)
+	go func() {
+		defer func() { recover() }()
+		c.Get(context.Background(), "a", func(ctx context.Context) (interface{}, error) {
+			<-release
+			panic("decode")
+		})
+	}()
+	time.Sleep(50 * time.Millisecond)
+	go func() {
+		_, err := c.Get(context.Background(), "a", nil)
+		waiter <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	if err := <-waiter; err == nil {
+		t.Error("waiter got no error from a panicked fetch")
+	}
+	if v, err := c.Get(context.Background(), "a", func(ctx context.Context) (interface{}, error) { return 1, nil }); err != nil || v != 1 {
+		t.Errorf("after a panic = %v, %v", v, err)
+	}
+}
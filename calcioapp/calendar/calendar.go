@@ -0,0 +1,227 @@
+// Package calendar writes fixtures as iCalendar (RFC 5545) feeds, for
+// subscribing from a phone or desktop calendar.
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+// ContentType is the media type of a feed.
+const ContentType = "text/calendar; charset=utf-8"
+
+// Duration is the time blocked for a match: two halves, the break and
+// stoppage time.
+const Duration = 2 * time.Hour
+
+const stamp = "20060102T150405Z"
+
+// UID is the stable identifier of a fixture's event: calendars update the
+// event in place when the feed is refreshed.
+func UID(fixtureId int) string {
+	return fmt.Sprintf("fixture-%d@calcio-app", fixtureId)
+}
+
+// status maps a fixture status to the event's: postponed and cancelled
+// fixtures are CANCELLED, those without a confirmed kick-off TENTATIVE.
+func status(short string) string {
+	switch short {
+	case "PST", "CANC", "ABD":
+		return "CANCELLED"
+	case "TBD":
+		return "TENTATIVE"
+	}
+	return "CONFIRMED"
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writer writes content lines, folded at 75 octets without splitting a
+// UTF-8 sequence, with CRLF line breaks.
+type writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (w *writer) line(name string, value string) {
+	s := name + ":" + value
+	for len(s) > 75 {
+		cut := 75
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.write(s[:cut] + "\r\n")
+		s = " " + s[cut:]
+	}
+	w.write(s + "\r\n")
+}
+
+func (w *writer) write(s string) {
+	if w.err == nil {
+		_, w.err = w.w.WriteString(s)
+	}
+}
+
+func score(s apifootball.Score) string {
+	if !s.Home.Valid || !s.Away.Valid {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d", s.Home.Int, s.Away.Int)
+}
+
+// summary is the title of the event, with the score once there is one.
+func summary(f apifootball.Fixture) string {
+	if result := score(f.Goals); result != "" {
+		return fmt.Sprintf("%s %s %s", f.Teams.Home.Name, result, f.Teams.Away.Name)
+	}
+	return fmt.Sprintf("%s - %s", f.Teams.Home.Name, f.Teams.Away.Name)
+}
+
+func description(f apifootball.Fixture) string {
+	lines := []string{f.League.Name}
+	if f.League.Round != "" {
+		lines[0] += ", " + f.League.Round
+	}
+	lines = append(lines, f.Fixture.Status.Long)
+	if result := score(f.Goals); result != "" {
+		line := "Score: " + result
+		if halftime := score(f.Score.Halftime); halftime != "" {
+			line += " (HT " + halftime + ")"
+		}
+		if penalties := score(f.Score.Penalty); penalties != "" {
+			line += ", penalties " + penalties
+		}
+		lines = append(lines, line)
+	}
+	if f.Fixture.Referee.Valid {
+		lines = append(lines, "Referee: "+f.Fixture.Referee.String)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func location(f apifootball.Fixture) string {
+	var parts []string
+	for _, part := range []apifootball.NullString{f.Fixture.Venue.Name, f.Fixture.Venue.City} {
+		if part.Valid && part.String != "" {
+			parts = append(parts, part.String)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Revisions remembers when each event last changed, so that refreshed
+// feeds carry an increasing SEQUENCE and calendars replace an event whose
+// kick-off, score or status moved.
+type Revisions struct {
+	mu     sync.Mutex
+	events map[int]revision
+}
+
+type revision struct {
+	content  string
+	modified time.Time
+}
+
+// sequenceEpoch is the time of SEQUENCE 0. Sequences count the seconds
+// since, so an event first seen after a restart still gets a later one.
+var sequenceEpoch = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func NewRevisions() *Revisions {
+	return &Revisions{events: map[int]revision{}}
+}
+
+// modified returns when the event of a fixture was first written with
+// content. A nil Revisions takes every event as modified now.
+func (r *Revisions) modified(fixtureId int, content string, now time.Time) time.Time {
+	now = now.UTC().Truncate(time.Second)
+	if r == nil {
+		return now
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.events[fixtureId]
+	if ok && old.content == content {
+		return old.modified
+	}
+	// Two changes within a second still get distinct sequences.
+	if ok && !now.After(old.modified) {
+		now = old.modified.Add(time.Second)
+	}
+	r.events[fixtureId] = revision{content, now}
+	return now
+}
+
+// Write writes the feed of the fixtures, named name. now stamps the
+// events; revisions dates their changes.
+func Write(out io.Writer, name string, fixtures []apifootball.Fixture, now time.Time, revisions *Revisions) error {
+	sorted := make([]apifootball.Fixture, len(fixtures))
+	copy(sorted, fixtures)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Fixture.Date.Before(sorted[j].Fixture.Date) })
+
+	w := &writer{w: bufio.NewWriter(out)}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", "-//calcio-app//fixtures//EN")
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.line("X-WR-CALNAME", escape(name))
+	for _, f := range sorted {
+		start := f.Fixture.Date.UTC()
+		title, venue, details, state := summary(f), location(f), description(f), status(f.Fixture.Status.Short)
+		modified := revisions.modified(f.Fixture.ID, strings.Join([]string{start.Format(stamp), title, venue, details, state}, "\n"), now)
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", UID(f.Fixture.ID))
+		w.line("DTSTAMP", now.UTC().Format(stamp))
+		w.line("LAST-MODIFIED", modified.Format(stamp))
+		w.line("SEQUENCE", fmt.Sprint(int64(modified.Sub(sequenceEpoch)/time.Second)))
+		w.line("DTSTART", start.Format(stamp))
+		w.line("DTEND", start.Add(Duration).Format(stamp))
+		w.line("SUMMARY", escape(title))
+		if venue != "" {
+			w.line("LOCATION", escape(venue))
+		}
+		w.line("DESCRIPTION", escape(details))
+		w.line("CATEGORIES", escape(f.League.Name))
+		w.line("STATUS", state)
+		w.line("TRANSP", "TRANSPARENT")
+		w.line("END", "VEVENT")
+	}
+	w.line("END", "VCALENDAR")
+	if w.err != nil {
+		return w.err
+	}
+	return w.w.Flush()
+}
+
+// TeamName is the feed name of a team's fixtures.
+func TeamName(teamId int, fixtures []apifootball.Fixture) string {
+	for _, f := range fixtures {
+		switch teamId {
+		case f.Teams.Home.ID:
+			return f.Teams.Home.Name + " fixtures"
+		case f.Teams.Away.ID:
+			return f.Teams.Away.Name + " fixtures"
+		}
+	}
+	return "Fixtures"
+}
+
+// LeagueName is the feed name of a league's fixtures.
+func LeagueName(fixtures []apifootball.Fixture) string {
+	if len(fixtures) == 0 {
+		return "Fixtures"
+	}
+	l := fixtures[0].League
+	return fmt.Sprintf("%s %d/%02d", l.Name, l.Season, (l.Season+1)%100)
+}
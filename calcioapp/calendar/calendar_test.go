@@ -0,0 +1,162 @@
+package calendar
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+)
+
+func fixture(id int, date time.Time, status string) apifootball.Fixture {
+	var f apifootball.Fixture
+	f.Fixture.ID = id
+	f.Fixture.Date = date
+	f.Fixture.Status.Short = status
+	f.League.Name, f.League.Round = "Serie A", "Regular Season - 1"
+	f.Teams.Home.Name, f.Teams.Away.Name = "Inter", "Genoa"
+	return f
+}
+
+func TestWrite(t *testing.T) {
+	played := fixture(2, time.Date(2021, 8, 21, 18, 45, 0, 0, time.UTC), "FT")
+	played.Fixture.Status.Long = "Match Finished"
+	played.Goals.Home = apifootball.NullInt{Int: 4, Valid: true}
+	played.Goals.Away = apifootball.NullInt{Int: 0, Valid: true}
+	played.Score.Halftime = played.Goals
+	played.Fixture.Venue.Name = apifootball.NullString{String: "Stadio Giuseppe Meazza", Valid: true}
+	played.Fixture.Venue.City = apifootball.NullString{String: "Milano", Valid: true}
+	postponed := fixture(1, time.Date(2021, 8, 28, 16, 30, 0, 0, time.FixedZone("CEST", 2*3600)), "PST")
+	postponed.Teams.Away.Name = "Hellas Verona; Verona, \"Gialloblù\" — a name long enough to fold"
+
+	var b bytes.Buffer
+	now := time.Date(2022, 3, 15, 12, 0, 0, 0, time.UTC)
+	if err := Write(&b, "Inter fixtures", []apifootball.Fixture{postponed, played}, now, nil); err != nil {
+		t.Fatal(err)
+	}
+	feed := b.String()
+
+	for _, line := range strings.SplitAfter(feed, "\r\n") {
+		if len(line) > 77 {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("folding split a character: %q", line)
+		}
+	}
+	if strings.Count(feed, "\n") != strings.Count(feed, "\r\n") {
+		t.Error("bare line feed")
+	}
+
+	unfolded := strings.ReplaceAll(feed, "\r\n ", "")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:Inter fixtures\r\n",
+		// Sorted by kick-off.
+		"BEGIN:VEVENT\r\nUID:fixture-2@calcio-app\r\nDTSTAMP:20220315T120000Z\r\nLAST-MODIFIED:20220315T120000Z\r\nSEQUENCE:37886400\r\nDTSTART:20210821T184500Z\r\nDTEND:20210821T204500Z\r\nSUMMARY:Inter 4-0 Genoa\r\n",
+		"LOCATION:Stadio Giuseppe Meazza\\, Milano\r\n",
+		"DESCRIPTION:Serie A\\, Regular Season - 1\\nMatch Finished\\nScore: 4-0 (HT 4-0)\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"UID:fixture-1@calcio-app\r\nDTSTAMP:20220315T120000Z\r\nLAST-MODIFIED:20220315T120000Z\r\nSEQUENCE:37886400\r\nDTSTART:20210828T143000Z\r\n",
+		"SUMMARY:Inter - Hellas Verona\\; Verona\\, \"Gialloblù\" — a name long enough to fold\r\n",
+		"STATUS:CANCELLED\r\nTRANSP:TRANSPARENT\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("feed lacks %q:\n%s", want, feed)
+		}
+	}
+	if strings.Index(unfolded, "fixture-2") > strings.Index(unfolded, "fixture-1") {
+		t.Error("events not sorted by kick-off")
+	}
+}
+
+// revise writes a feed of f at now and returns its LAST-MODIFIED and
+// SEQUENCE.
+func revise(t *testing.T, revisions *Revisions, f apifootball.Fixture, now time.Time) (string, int) {
+	t.Helper()
+	var b bytes.Buffer
+	if err := Write(&b, "Inter fixtures", []apifootball.Fixture{f}, now, revisions); err != nil {
+		t.Fatal(err)
+	}
+	var modified string
+	var sequence int
+	for _, line := range strings.Split(b.String(), "\r\n") {
+		if strings.HasPrefix(line, "LAST-MODIFIED:") {
+			modified = strings.TrimPrefix(line, "LAST-MODIFIED:")
+		}
+		if strings.HasPrefix(line, "SEQUENCE:") {
+			sequence, _ = strconv.Atoi(strings.TrimPrefix(line, "SEQUENCE:"))
+		}
+	}
+	return modified, sequence
+}
+
+func TestRevisions(t *testing.T) {
+	revisions := NewRevisions()
+	now := time.Date(2021, 8, 20, 12, 0, 0, 0, time.UTC)
+	scheduled := fixture(1, time.Date(2021, 8, 28, 16, 30, 0, 0, time.UTC), "NS")
+	modified, sequence := revise(t, revisions, scheduled, now)
+
+	// Refreshing an unchanged fixture keeps its revision.
+	if m, s := revise(t, revisions, scheduled, now.Add(time.Hour)); m != modified || s != sequence {
+		t.Errorf("unchanged fixture revised: %s %d, was %s %d", m, s, modified, sequence)
+	}
+
+	postponed := scheduled
+	postponed.Fixture.Status.Short = "PST"
+	rescheduled := scheduled
+	rescheduled.Fixture.Date = time.Date(2021, 9, 22, 18, 45, 0, 0, time.UTC)
+	played := rescheduled
+	played.Fixture.Status.Short = "FT"
+	played.Goals.Home = apifootball.NullInt{Int: 1, Valid: true}
+	played.Goals.Away = apifootball.NullInt{Int: 0, Valid: true}
+	for i, f := range []apifootball.Fixture{postponed, rescheduled, played} {
+		at := now.Add(time.Duration(i+2) * time.Hour)
+		m, s := revise(t, revisions, f, at)
+		if m != at.Format(stamp) || s <= sequence {
+			t.Errorf("change %d: %s %d after %d", i, m, s, sequence)
+		}
+		sequence = s
+	}
+
+	// A change within the second of the last still raises the sequence.
+	corrected := played
+	corrected.Goals.Away = apifootball.NullInt{Int: 1, Valid: true}
+	if _, s := revise(t, revisions, corrected, now.Add(4*time.Hour)); s <= sequence {
+		t.Errorf("same second change: %d after %d", s, sequence)
+	}
+
+	// After a restart the sequence is still later than before.
+	if _, s := revise(t, NewRevisions(), corrected, now.Add(5*time.Hour)); s <= sequence {
+		t.Errorf("sequence after a restart: %d after %d", s, sequence)
+	}
+}
+
+func TestFeeds(t *testing.T) {
+	ts := httptest.NewServer(fake.New(fake.Options{DailyLimit: -1}))
+	defer ts.Close()
+	client := apifootball.New("test-token", ts.URL+"/")
+
+	fixtures, err := client.GetFixturesByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := LeagueName(fixtures.Response); name != "Serie A 2021/22" {
+		t.Errorf("league feed name = %q", name)
+	}
+	if name := TeamName(505, fixtures.Response); name != "Inter fixtures" {
+		t.Errorf("team feed name = %q", name)
+	}
+	var b bytes.Buffer
+	if err := Write(&b, "Serie A", fixtures.Response, time.Now(), NewRevisions()); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(b.String(), "BEGIN:VEVENT"); n != fixtures.Results {
+		t.Errorf("%d events for %d fixtures", n, fixtures.Results)
+	}
+}
@@ -0,0 +1,111 @@
+// Command backtest captures predictions before kickoff and reports how
+// they fared. Run it from the calcioapp directory so config/config.ini is
+// found:
+//
+//	go run ./cmd/backtest capture -league 135
+//	go run ./cmd/backtest backfill -league 135
+//	go run ./cmd/backtest report -league 135 -season 2021
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/backtest"
+	"github.com/nero-15/calcio-app/config"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: backtest capture|backfill|report [flags]")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	file := flags.String("store", config.Config.BacktestFile, "file of the captured predictions")
+	league := flags.Int("league", 135, "league id, 0 reports every league")
+	season := flags.Int("season", 0, "season reported, 0 for every season")
+	source := flags.String("source", "", "source reported: provider, model or empty for both")
+	now := flags.String("now", "", "capture as of this date instead of today, e.g. with the fake server")
+	window := flags.Duration("window", 7*24*time.Hour, "how far ahead fixtures are captured")
+	asJson := flags.Bool("json", false, "print the report as json")
+	flags.Parse(os.Args[2:])
+
+	store, err := backtest.Open(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	client := apifootball.New(config.Config.ApiFootballApiToken, config.Config.ApiFootballBaseUrl)
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "capture":
+		capturer := &backtest.Capturer{Client: client, Store: store, Window: *window}
+		if *now != "" {
+			date, err := time.Parse("2006-01-02", *now)
+			if err != nil {
+				log.Fatalf("invalid -now: %v", err)
+			}
+			capturer.Now = func() time.Time { return date }
+		}
+		added, err := capturer.Capture(ctx, *league)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%d predictions stored\n", added)
+	case "backfill":
+		fixtures, err := client.GetFixturesByLeagueId(strconv.Itoa(*league))
+		if err != nil {
+			log.Fatal(err)
+		}
+		added := backtest.Backfill(store, fixtures.Response)
+		if err := store.Save(); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%d model predictions stored\n", added)
+	case "report":
+		report, err := backtest.Run(ctx, client, store, backtest.Filter{LeagueID: *league, Season: *season, Source: *source})
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *asJson {
+			out, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(out))
+			return
+		}
+		printReport(report)
+	default:
+		usage()
+	}
+}
+
+func printReport(report backtest.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LEAGUE\tSEASON\tSOURCE\tPREDICTIONS\tBRIER\tLOG LOSS")
+	for _, score := range report.Scores {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%.4f\t%.4f\n", score.League.Name, score.League.Season, score.Source, score.Predictions, score.Brier, score.LogLoss)
+	}
+	w.Flush()
+	fmt.Printf("%d predictions awaiting a result\n", report.Pending)
+
+	for _, score := range report.Scores {
+		fmt.Printf("\n%s %d, %s calibration\n", score.League.Name, score.League.Season, score.Source)
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "BUCKET\tCOUNT\tPREDICTED\tOBSERVED")
+		for _, bucket := range score.Calibration {
+			fmt.Fprintf(w, "%.0f-%.0f%%\t%d\t%.3f\t%.3f\n", bucket.From*100, bucket.To*100, bucket.Count, bucket.Predicted, bucket.Observed)
+		}
+		w.Flush()
+	}
+}
@@ -0,0 +1,41 @@
+// Command fakeapifootball serves the fake API-Football season for local
+// development. Point config.ini's apiFootball baseUrl at it, e.g.
+// http://localhost:8081/.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball/fake"
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "listen address")
+	seed := flag.Int64("seed", 1, "seed of the generated season")
+	now := flag.String("now", "2022-03-15", "date splitting played and upcoming matches")
+	token := flag.String("token", "", "required x-apisports-key, empty accepts any")
+	limit := flag.Int("limit", 100, "daily request quota, negative for unlimited")
+	latency := flag.Duration("latency", 0, "latency added to every response")
+	jitter := flag.Duration("jitter", 0, "random extra latency up to this duration")
+	failureRate := flag.Float64("failure-rate", 0, "share of requests answered with a 500")
+	flag.Parse()
+
+	date, err := time.Parse("2006-01-02", *now)
+	if err != nil {
+		log.Fatalf("invalid -now: %v", err)
+	}
+	server := fake.New(fake.Options{
+		Seed:        *seed,
+		Now:         date,
+		Token:       *token,
+		DailyLimit:  *limit,
+		Latency:     *latency,
+		Jitter:      *jitter,
+		FailureRate: *failureRate,
+	})
+	log.Printf("fake API-Football listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
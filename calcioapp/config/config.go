@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"net/url"
 	"os"
 
 	ini "gopkg.in/ini.v1"
@@ -13,6 +15,11 @@ type ConfigList struct {
 	FootballDataBaseUrl  string
 	ApiFootballApiToken  string
 	ApiFootballBaseUrl   string
+	ApiFootballDecode    string
+	LogLevel             string
+	RatingsLeagues       []int
+	RatingsFile          string
+	BacktestFile         string
 }
 
 // Config is ConfigList
@@ -30,5 +37,40 @@ func init() {
 		FootballDataBaseUrl:  cfg.Section("footballData").Key("baseUrl").String(),
 		ApiFootballApiToken:  cfg.Section("apiFootball").Key("apiToken").String(),
 		ApiFootballBaseUrl:   cfg.Section("apiFootball").Key("baseUrl").String(),
+		ApiFootballDecode:    cfg.Section("apiFootball").Key("decode").MustString("lenient"),
+		LogLevel:             cfg.Section("log").Key("level").MustString("info"),
+		RatingsLeagues:       cfg.Section("ratings").Key("leagues").ValidInts(","),
+		RatingsFile:          cfg.Section("ratings").Key("file").MustString("data/ratings.json"),
+		BacktestFile:         cfg.Section("backtest").Key("file").MustString("data/predictions.json"),
 	}
+	if len(Config.RatingsLeagues) == 0 {
+		Config.RatingsLeagues = []int{135, 137}
+	}
+}
+
+// Validate reports the first missing or malformed setting.
+func (c ConfigList) Validate() error {
+	settings := []struct {
+		name  string
+		value string
+		isUrl bool
+	}{
+		{"footballData.apiToken", c.FootballDataApiToken, false},
+		{"footballData.baseUrl", c.FootballDataBaseUrl, true},
+		{"apiFootball.apiToken", c.ApiFootballApiToken, false},
+		{"apiFootball.baseUrl", c.ApiFootballBaseUrl, true},
+	}
+	for _, setting := range settings {
+		if setting.value == "" || setting.value == "your-api-token" {
+			return fmt.Errorf("config: %s is not set", setting.name)
+		}
+		if !setting.isUrl {
+			continue
+		}
+		u, err := url.Parse(setting.value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("config: %s is not a valid url", setting.name)
+		}
+	}
+	return nil
 }
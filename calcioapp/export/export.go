@@ -0,0 +1,128 @@
+// Package export flattens the nested API responses into tables of fixed
+// columns and writes them as CSV, for spreadsheets. The columns of each
+// table are listed, with their meaning, in Schemas.
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/media"
+)
+
+// ContentType is the media type of an export.
+const ContentType = "text/csv; charset=utf-8"
+
+// bom is the UTF-8 byte order mark: Excel reads a CSV file as UTF-8 only
+// when it starts with one, and mangles accented names otherwise.
+const bom = "\ufeff"
+
+type Column struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Table is a flattened response: every row has a cell per column, empty
+// when the provider sent null.
+type Table struct {
+	Name    string
+	Columns []Column
+	Rows    [][]string
+}
+
+// Options are how a route answers: as JSON, or as CSV with or without a
+// byte order mark.
+type Options struct {
+	CSV bool
+	BOM bool
+}
+
+// Negotiate reads the export options of a request from the format and bom
+// query parameters, falling back to the Accept header for the format, JSON
+// when it accepts neither. ok is false for an unknown format or an invalid
+// bom.
+func Negotiate(format string, withBOM string, accept string) (Options, bool) {
+	var options Options
+	if withBOM != "" {
+		var err error
+		if options.BOM, err = strconv.ParseBool(withBOM); err != nil {
+			return options, false
+		}
+	}
+	switch format {
+	case "csv":
+		options.CSV = true
+		return options, true
+	case "json":
+		return options, true
+	case "":
+	default:
+		return options, false
+	}
+	switch media.Negotiate(accept, "application/json", "text/csv", "application/csv") {
+	case "text/csv", "application/csv":
+		options.CSV = true
+	}
+	return options, true
+}
+
+// Filename is the name the export of a table is saved as.
+func Filename(table Table, id string) string {
+	return table.Name + "-" + id + ".csv"
+}
+
+// Write writes the table as CSV with a header row of the column names.
+func Write(w io.Writer, table Table, options Options) error {
+	if options.BOM {
+		if _, err := io.WriteString(w, bom); err != nil {
+			return err
+		}
+	}
+	out := csv.NewWriter(w)
+	header := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		header[i] = column.Name
+	}
+	out.Write(header)
+	out.WriteAll(table.Rows)
+	return out.Error()
+}
+
+func integer(n int) string {
+	return strconv.Itoa(n)
+}
+
+func nullInt(n apifootball.NullInt) string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.Itoa(n.Int)
+}
+
+func nullFloat(n apifootball.NullFloat) string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.FormatFloat(n.Float, 'f', -1, 64)
+}
+
+func pointer(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
+
+func boolean(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
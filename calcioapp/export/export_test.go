@@ -0,0 +1,163 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+	"github.com/nero-15/calcio-app/players"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+func TestNegotiate(t *testing.T) {
+	for _, c := range []struct {
+		format, bom, accept string
+		want                Options
+		ok                  bool
+	}{
+		{"", "", "", Options{}, true},
+		{"csv", "", "application/json", Options{CSV: true}, true},
+		{"json", "", "text/csv", Options{}, true},
+		{"", "", "text/csv;q=0.9, application/json", Options{}, true},
+		{"", "", "application/json;q=0.5, text/csv", Options{CSV: true}, true},
+		{"", "", "text/*, */*;q=0.1", Options{CSV: true}, true},
+		{"", "", "text/html, application/json", Options{}, true},
+		{"csv", "1", "", Options{CSV: true, BOM: true}, true},
+		{"xlsx", "", "", Options{}, false},
+		{"csv", "maybe", "", Options{}, false},
+	} {
+		got, ok := Negotiate(c.format, c.bom, c.accept)
+		if ok != c.ok || (ok && got != c.want) {
+			t.Errorf("Negotiate(%q, %q, %q) = %+v, %v", c.format, c.bom, c.accept, got, ok)
+		}
+	}
+}
+
+// read parses an export back, checking the table has the columns its
+// schema documents and every row has a cell per column.
+func read(t *testing.T, table Table, options Options) ([]string, [][]string, []byte) {
+	t.Helper()
+	if schema, ok := Schemas[table.Name]; !ok || !reflect.DeepEqual(schema, table.Columns) {
+		t.Fatalf("%s table does not have the columns of its schema", table.Name)
+	}
+	var b bytes.Buffer
+	if err := Write(&b, table, options); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(b.Bytes(), []byte(bom)))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records[0]) != len(table.Columns) {
+		t.Fatalf("header has %d columns, schema %d", len(records[0]), len(table.Columns))
+	}
+	for _, row := range records[1:] {
+		if len(row) != len(table.Columns) {
+			t.Fatalf("row has %d cells, schema %d columns: %v", len(row), len(table.Columns), row)
+		}
+	}
+	return records[0], records[1:], b.Bytes()
+}
+
+func TestTables(t *testing.T) {
+	ts := httptest.NewServer(fake.New(fake.Options{DailyLimit: -1}))
+	defer ts.Close()
+	client := apifootball.New("test-token", ts.URL+"/")
+
+	provider, err := client.GetStandingsByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, rows, _ := read(t, Standings(provider), Options{})
+	if len(rows) != 20 || header[3] != "rank" || rows[0][3] != "1" || rows[0][1] != "2021" {
+		t.Errorf("standings: %v, %d rows, first %v", header, len(rows), rows[0])
+	}
+
+	fixtures, err := client.GetFixturesByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, rows, _ = read(t, Computed(135, standings.Compute(fixtures.Response, standings.RulesFor(135))), Options{})
+	if len(rows) != 20 || rows[0][0] != "135" || rows[0][1] != "" {
+		t.Errorf("computed standings: %d rows, first %v", len(rows), rows[0])
+	}
+
+	topscorers, err := client.GetTopscorersByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := Leaders("topscorers", topscorers.Response)
+	header, rows, _ = read(t, table, Options{})
+	if header[0] != "rank" || rows[0][0] != "1" || rows[0][1] != integer(topscorers.Response[0].ID) {
+		t.Errorf("topscorers: %v, first %v", header, rows[0])
+	}
+
+	squads, err := client.GetSquadsByTeamId("505")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, rows, _ = read(t, Squads(squads), Options{})
+	if len(rows) != len(squads.Response[0].Players) || rows[0][0] != "505" {
+		t.Errorf("squads: %d rows, first %v", len(rows), rows[0])
+	}
+
+	pages, err := players.Collect(context.Background(), client, 135)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table = Profiles(players.Build(135, pages, players.DefaultMinMinutes))
+	if _, rows, _ = read(t, table, Options{}); len(rows) == 0 || Filename(table, "135") != "profiles-135.csv" {
+		t.Errorf("profiles: %d rows in %s", len(rows), Filename(table, "135"))
+	}
+}
+
+func TestPlayers(t *testing.T) {
+	var p apifootball.Players
+	json.Unmarshal([]byte(`{"response": [{"player": {"id": 7, "name": "Ž. Čolić, \"Zeko\"", "injured": false},
+		"statistics": [
+			{"team": {"id": 505, "name": "Inter"}, "league": {"id": 135, "name": "Serie A", "season": 2021},
+			 "games": {"appearences": 10, "minutes": 812, "rating": "7.15", "position": "Attacker"}, "goals": {"total": 4, "assists": null}},
+			{"team": {"id": 505, "name": "Inter"}, "league": {"id": 137, "name": "Coppa Italia", "season": 2021},
+			 "games": {"appearences": 1, "minutes": 90, "rating": null}}]}]}`), &p)
+
+	columns := map[string]int{}
+	for i, c := range PlayerColumns {
+		columns[c.Name] = i
+	}
+	_, rows, raw := read(t, Players(p.Response), Options{BOM: true})
+	if !bytes.HasPrefix(raw, []byte("\xef\xbb\xbfplayer_id,")) {
+		t.Errorf("export starts %q, want a byte order mark", raw[:12])
+	}
+	if len(rows) != 2 {
+		t.Fatalf("%d rows, want one per competition", len(rows))
+	}
+	first := rows[0]
+	if first[columns["name"]] != `Ž. Čolić, "Zeko"` || first[columns["rating"]] != "7.15" || first[columns["goals"]] != "4" || first[columns["assists"]] != "" {
+		t.Errorf("first row = %v", first)
+	}
+	if rows[1][columns["league"]] != "Coppa Italia" || rows[1][columns["rating"]] != "" {
+		t.Errorf("second row = %v", rows[1])
+	}
+	if !strings.Contains(string(raw), `"Ž. Čolić, ""Zeko"""`) {
+		t.Errorf("name not quoted:\n%s", raw)
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	for name, columns := range Schemas {
+		seen := map[string]bool{}
+		for _, c := range columns {
+			if c.Name == "" || c.Description == "" || seen[c.Name] {
+				t.Errorf("%s: column %+v is unnamed, undocumented or repeated", name, c)
+			}
+			seen[c.Name] = true
+		}
+	}
+}
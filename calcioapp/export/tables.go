@@ -0,0 +1,288 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/players"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+func record(prefix string, description string) []Column {
+	return []Column{
+		{prefix + "played", "Matches played" + description},
+		{prefix + "won", "Matches won" + description},
+		{prefix + "drawn", "Matches drawn" + description},
+		{prefix + "lost", "Matches lost" + description},
+		{prefix + "goals_for", "Goals scored" + description},
+		{prefix + "goals_against", "Goals conceded" + description},
+	}
+}
+
+func join(lists ...[]Column) []Column {
+	var columns []Column
+	for _, list := range lists {
+		columns = append(columns, list...)
+	}
+	return columns
+}
+
+// StandingsColumns are the columns of a league table, one row per team and
+// group. Tables computed from fixtures have no season, group, status nor
+// description.
+var StandingsColumns = join([]Column{
+	{"league_id", "Provider id of the league"},
+	{"season", "Year the season starts in"},
+	{"group", "Group of the team, the league name for single table leagues"},
+	{"rank", "Position in the table"},
+	{"team_id", "Provider id of the team"},
+	{"team", "Team name"},
+	{"points", "Points"},
+	{"goal_difference", "Goals scored minus goals conceded"},
+	{"form", "Results of the last matches, latest first: W, D or L"},
+}, record("", ""), record("home_", " at home"), record("away_", " away"), []Column{
+	{"status", "Movement since the last round: up, down or same"},
+	{"description", "Qualification or relegation zone of the position"},
+	{"updated", "Time of the provider's last update, RFC 3339 in UTC"},
+})
+
+// PlayerColumns are the columns of player statistics, one row per player
+// and competition. Leaders tables start with a rank column.
+var PlayerColumns = []Column{
+	{"player_id", "Provider id of the player"},
+	{"name", "Short name"},
+	{"firstname", "First name"},
+	{"lastname", "Last name"},
+	{"age", "Age in years"},
+	{"birth_date", "Date of birth, YYYY-MM-DD"},
+	{"nationality", "Nationality"},
+	{"height", "Height, e.g. 188 cm"},
+	{"weight", "Weight, e.g. 81 kg"},
+	{"injured", "Whether the player is injured: true or false"},
+	{"team_id", "Provider id of the team"},
+	{"team", "Team name"},
+	{"league_id", "Provider id of the competition"},
+	{"league", "Competition name"},
+	{"season", "Year the season starts in"},
+	{"position", "Goalkeeper, Defender, Midfielder or Attacker"},
+	{"appearances", "Matches played"},
+	{"lineups", "Matches started"},
+	{"minutes", "Minutes played"},
+	{"rating", "Average match rating"},
+	{"captain", "Whether the player captains the team: true or false"},
+	{"substitutes_in", "Matches entered from the bench"},
+	{"substitutes_out", "Matches substituted off"},
+	{"bench", "Matches on the bench"},
+	{"shots", "Shots"},
+	{"shots_on_target", "Shots on target"},
+	{"goals", "Goals scored"},
+	{"goals_conceded", "Goals conceded, for goalkeepers"},
+	{"assists", "Assists"},
+	{"saves", "Saves, for goalkeepers"},
+	{"passes", "Passes"},
+	{"key_passes", "Passes leading to a shot"},
+	{"pass_accuracy", "Pass accuracy in percent"},
+	{"tackles", "Tackles"},
+	{"blocks", "Blocked shots"},
+	{"interceptions", "Interceptions"},
+	{"duels", "Duels"},
+	{"duels_won", "Duels won"},
+	{"dribbles", "Dribbles attempted"},
+	{"dribbles_won", "Successful dribbles"},
+	{"dribbled_past", "Times dribbled past"},
+	{"fouls_drawn", "Fouls suffered"},
+	{"fouls_committed", "Fouls committed"},
+	{"yellow_cards", "Yellow cards"},
+	{"second_yellow_cards", "Second yellow cards"},
+	{"red_cards", "Straight red cards"},
+	{"penalties_won", "Penalties won"},
+	{"penalties_committed", "Penalties conceded"},
+	{"penalties_scored", "Penalties scored"},
+	{"penalties_missed", "Penalties missed"},
+	{"penalties_saved", "Penalties saved, for goalkeepers"},
+}
+
+var rank = Column{"rank", "Position in the leaders list"}
+
+// SquadColumns are the columns of a squad, one row per player.
+var SquadColumns = []Column{
+	{"team_id", "Provider id of the team"},
+	{"team", "Team name"},
+	{"player_id", "Provider id of the player"},
+	{"name", "Player name"},
+	{"age", "Age in years"},
+	{"number", "Shirt number"},
+	{"position", "Goalkeeper, Defender, Midfielder or Attacker"},
+}
+
+// ProfileColumns are the columns of the league player profiles, one row per
+// player; a value and a percentile column follow for each metric.
+var ProfileColumns = []Column{
+	{"player_id", "Provider id of the player"},
+	{"name", "Player name"},
+	{"age", "Age in years"},
+	{"nationality", "Nationality"},
+	{"team_ids", "Provider ids of the teams played for in the league, separated by |"},
+	{"teams", "Names of the teams played for in the league, separated by |"},
+	{"position", "Goalkeeper, Defender, Midfielder or Attacker"},
+	{"minutes", "Minutes played"},
+	{"appearances", "Matches played"},
+	{"qualified", "Whether the player played enough minutes to be ranked: true or false"},
+}
+
+// metricColumns are the value and percentile columns of each metric.
+func metricColumns() []Column {
+	var columns []Column
+	for _, m := range players.Metrics() {
+		unit := ", 0 to 100"
+		if m.Per90 {
+			unit = " per 90 minutes"
+		}
+		columns = append(columns,
+			Column{m.Key, m.Label + unit},
+			Column{m.Key + "_percentile", "Percentile of " + m.Label + " among qualified players of the position"})
+	}
+	return columns
+}
+
+// LeaderLists are the lists flattened by Leaders.
+var LeaderLists = []string{"topscorers", "topassists", "topyellowcards", "topredcards"}
+
+// Schemas are the columns of each table, by table name.
+var Schemas = schemas()
+
+func schemas() map[string][]Column {
+	s := map[string][]Column{
+		"standings": StandingsColumns,
+		"players":   PlayerColumns,
+		"squads":    SquadColumns,
+		"profiles":  append(append([]Column{}, ProfileColumns...), metricColumns()...),
+	}
+	leaders := append([]Column{rank}, PlayerColumns...)
+	for _, name := range LeaderLists {
+		s[name] = leaders
+	}
+	return s
+}
+
+func recordCells(played, win, draw, lose, goalsFor, goalsAgainst int) []string {
+	return []string{integer(played), integer(win), integer(draw), integer(lose), integer(goalsFor), integer(goalsAgainst)}
+}
+
+func providerRecord(r apifootball.StandingsRecord) []string {
+	return recordCells(r.Played, r.Win, r.Draw, r.Lose, r.Goals.For, r.Goals.Against)
+}
+
+func computedRecord(r standings.Record) []string {
+	return recordCells(r.Played, r.Win, r.Draw, r.Lose, r.Goals.For, r.Goals.Against)
+}
+
+// Standings flattens the provider's standings.
+func Standings(s apifootball.Standings) Table {
+	table := Table{Name: "standings", Columns: Schemas["standings"], Rows: [][]string{}}
+	for _, response := range s.Response {
+		l := response.League
+		for _, group := range l.Standings {
+			for _, r := range group {
+				row := []string{integer(l.ID), integer(l.Season), r.Group, integer(r.Rank), integer(r.Team.ID), r.Team.Name, integer(r.Points), integer(r.Goalsdiff), r.Form}
+				row = append(row, providerRecord(r.All)...)
+				row = append(row, providerRecord(r.Home)...)
+				row = append(row, providerRecord(r.Away)...)
+				row = append(row, r.Status, r.Description, timestamp(r.Update))
+				table.Rows = append(table.Rows, row)
+			}
+		}
+	}
+	return table
+}
+
+// Computed flattens a table computed from the fixtures of a league.
+func Computed(leagueId int, t standings.Table) Table {
+	table := Table{Name: "standings", Columns: Schemas["standings"], Rows: [][]string{}}
+	for _, r := range t.Rows {
+		row := []string{integer(leagueId), "", "", integer(r.Rank), integer(r.Team.ID), r.Team.Name, integer(r.Points), integer(r.GoalsDiff), r.Form}
+		row = append(row, computedRecord(r.All)...)
+		row = append(row, computedRecord(r.Home)...)
+		row = append(row, computedRecord(r.Away)...)
+		row = append(row, "", "", "")
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+// PlayerRow is an entry of the players and top players responses.
+type PlayerRow = struct {
+	apifootball.Player `json:"player"`
+	Statistics         []apifootball.Statistic `json:"statistics"`
+}
+
+func statisticCells(p apifootball.Player, s apifootball.Statistic) []string {
+	return []string{
+		integer(p.ID), p.Name, p.Firstname, p.Lastname, integer(p.Age), p.Birth.Date, p.Nationality, p.Height, p.Weight, boolean(p.Injured),
+		integer(s.Team.ID), s.Team.Name, integer(s.League.ID), s.League.Name, integer(s.League.Season),
+		s.Games.Position, nullInt(s.Games.Appearences), nullInt(s.Games.Lineups), nullInt(s.Games.Minutes), nullFloat(s.Games.Rating), boolean(s.Games.Captain),
+		integer(s.Substitutes.In), integer(s.Substitutes.Out), integer(s.Substitutes.Bench),
+		nullInt(s.Shots.Total), nullInt(s.Shots.On),
+		nullInt(s.Goals.Total), nullInt(s.Goals.Conceded), nullInt(s.Goals.Assists), nullInt(s.Goals.Saves),
+		nullInt(s.Passes.Total), nullInt(s.Passes.Key), nullInt(s.Passes.Accuracy),
+		nullInt(s.Tackles.Total), nullInt(s.Tackles.Blocks), nullInt(s.Tackles.Interceptions),
+		nullInt(s.Duels.Total), nullInt(s.Duels.Won),
+		nullInt(s.Dribbles.Attempts), nullInt(s.Dribbles.Success), nullInt(s.Dribbles.Past),
+		nullInt(s.Fouls.Drawn), nullInt(s.Fouls.Committed),
+		integer(s.Cards.Yellow), integer(s.Cards.Yellowred), integer(s.Cards.Red),
+		nullInt(s.Penalty.Won), nullInt(s.Penalty.Commited), nullInt(s.Penalty.Scored), nullInt(s.Penalty.Missed), nullInt(s.Penalty.Saved),
+	}
+}
+
+// Players flattens player statistics, a row per player and competition.
+func Players(rows []PlayerRow) Table {
+	table := Table{Name: "players", Columns: Schemas["players"], Rows: [][]string{}}
+	for _, r := range rows {
+		for _, s := range r.Statistics {
+			table.Rows = append(table.Rows, statisticCells(r.Player, s))
+		}
+	}
+	return table
+}
+
+// Leaders flattens a top scorers, assists or cards list, ranked in the
+// provider's order. name is one of LeaderLists.
+func Leaders(name string, rows []PlayerRow) Table {
+	table := Table{Name: name, Columns: Schemas[name], Rows: [][]string{}}
+	for i, r := range rows {
+		for _, s := range r.Statistics {
+			table.Rows = append(table.Rows, append([]string{integer(i + 1)}, statisticCells(r.Player, s)...))
+		}
+	}
+	return table
+}
+
+// Squads flattens the squads of a team.
+func Squads(s apifootball.Squads) Table {
+	table := Table{Name: "squads", Columns: Schemas["squads"], Rows: [][]string{}}
+	for _, response := range s.Response {
+		for _, p := range response.Players {
+			table.Rows = append(table.Rows, []string{integer(response.Team.ID), response.Team.Name, integer(p.ID), p.Name, integer(p.Age), integer(p.Number), p.Position})
+		}
+	}
+	return table
+}
+
+// Profiles flattens the player profiles of a league.
+func Profiles(league players.League) Table {
+	table := Table{Name: "profiles", Columns: Schemas["profiles"], Rows: [][]string{}}
+	for _, p := range league.Players {
+		var ids, names []string
+		for _, team := range p.Teams {
+			ids = append(ids, integer(team.ID))
+			names = append(names, team.Name)
+		}
+		row := []string{integer(p.Player.ID), p.Player.Name, integer(p.Player.Age), p.Player.Nationality,
+			strings.Join(ids, "|"), strings.Join(names, "|"), p.Position, integer(p.Minutes), integer(p.Appearances), boolean(p.Qualified)}
+		for i := range p.Values {
+			row = append(row, pointer(p.Values[i]), pointer(p.Percentiles[i]))
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
@@ -1,33 +1,97 @@
 package footballData
 
 import (
+	"context"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path"
+	"time"
+
+	"github.com/nero-15/calcio-app/logging"
+	"github.com/nero-15/calcio-app/metrics"
+	"github.com/sirupsen/logrus"
 )
 
 type APIClient struct {
 	token      string
 	baseUrl    string
 	httpClient *http.Client
+	ctx        context.Context
 }
 
 func New(token string, baseUrl string) *APIClient {
-	apiClient := &APIClient{token, baseUrl, &http.Client{}}
+	return NewWithHTTPClient(token, baseUrl, &http.Client{Timeout: 10 * time.Second})
+}
+
+// NewWithHTTPClient lets callers swap the transport, e.g. to replay recorded responses in tests.
+func NewWithHTTPClient(token string, baseUrl string, httpClient *http.Client) *APIClient {
+	apiClient := &APIClient{token, baseUrl, httpClient, context.Background()}
 	return apiClient
 }
 
+// WithContext returns a copy of the client whose requests use ctx, so they
+// are cancelled with the incoming request and logged with its request id.
+func (api *APIClient) WithContext(ctx context.Context) *APIClient {
+	apiClient := *api
+	apiClient.ctx = ctx
+	return &apiClient
+}
+
 func (api *APIClient) DoRequest(urlPath string, teamId string) (body []byte, err error) {
-	url, _ := url.Parse(api.baseUrl)
+	url, err := url.Parse(api.baseUrl)
+	if err != nil {
+		return nil, err
+	}
 	url.Path = path.Join(url.Path, urlPath, teamId)
 
-	req, _ := http.NewRequest("GET", url.String(), nil)
+	req, err := http.NewRequestWithContext(api.ctx, "GET", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Add("X-Auth-Token", api.token)
-	client := new(http.Client)
-	resp, _ := client.Do(req)
+	start := time.Now()
+	resp, err := api.httpClient.Do(req)
+	if err != nil {
+		logRequest(api.ctx, urlPath, url.RawQuery, 0, time.Since(start), err)
+		return nil, err
+	}
 	defer resp.Body.Close()
+	logRequest(api.ctx, urlPath, url.RawQuery, resp.StatusCode, time.Since(start), nil)
 
-	byteArray, _ := ioutil.ReadAll(resp.Body)
+	byteArray, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return byteArray, fmt.Errorf("footballData: %s returned %s", urlPath, resp.Status)
+	}
 	return byteArray, nil
 }
+
+// logRequest logs and records metrics for one request to the provider.
+func logRequest(ctx context.Context, urlPath string, query string, status int, duration time.Duration, err error) {
+	metrics.ObserveUpstream("footballData", urlPath, status, duration)
+	entry := logging.FromContext(ctx).WithFields(logrus.Fields{
+		"provider":    "footballData",
+		"endpoint":    urlPath,
+		"query":       query,
+		"status":      status,
+		"duration_ms": float64(duration.Microseconds()) / 1000,
+	})
+	switch {
+	case err != nil:
+		entry.WithError(err).Error("upstream request")
+	case status != http.StatusOK:
+		entry.Warn("upstream request")
+	default:
+		entry.Info("upstream request")
+	}
+}
+
+// Ping requests a single competition, which is the cheapest call on the free tier.
+func (api *APIClient) Ping() error {
+	_, err := api.DoRequest("competitions", "SA")
+	return err
+}
@@ -0,0 +1,68 @@
+package footballData
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/nero-15/calcio-app/recorder"
+)
+
+// go test ./footballData -record re-records testdata/recordings from the
+// live API using FOOTBALLDATA_TOKEN.
+var record = flag.Bool("record", false, "record responses from the live football-data.org")
+
+const recordings = "testdata/recordings"
+
+func newTestClient(t *testing.T) *APIClient {
+	t.Helper()
+	mode := recorder.Replay
+	token := "test-token"
+	if *record {
+		mode = recorder.Record
+		token = os.Getenv("FOOTBALLDATA_TOKEN")
+		if token == "" {
+			t.Fatal("-record needs FOOTBALLDATA_TOKEN")
+		}
+	}
+	return NewWithHTTPClient(token, "https://api.football-data.org/v2/", recorder.New(recordings, mode).Client())
+}
+
+func TestPing(t *testing.T) {
+	if err := newTestClient(t).Ping(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDoRequest(t *testing.T) {
+	api := newTestClient(t)
+	resp, err := api.DoRequest("teams", "108")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var team struct {
+		ID                 int    `json:"id"`
+		ShortName          string `json:"shortName"`
+		ActiveCompetitions []struct {
+			Code string `json:"code"`
+		} `json:"activeCompetitions"`
+		Squad []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+			Role string `json:"role"`
+		} `json:"squad"`
+	}
+	if err := json.Unmarshal(resp, &team); err != nil {
+		t.Fatal(err)
+	}
+	if team.ID != 108 || team.ShortName != "Inter" || len(team.ActiveCompetitions) == 0 || len(team.Squad) == 0 || team.Squad[0].Name == "" {
+		t.Errorf("team not decoded: %+v", team)
+	}
+
+	// An unknown team is an error, with the provider's message as the body.
+	resp, err = api.DoRequest("teams", "0")
+	if err == nil || !json.Valid(resp) {
+		t.Errorf("unknown team: %v, %s", err, resp)
+	}
+}
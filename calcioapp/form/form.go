@@ -0,0 +1,269 @@
+// Package form turns a team's finished fixtures into form and streaks: the
+// numbers behind strings such as "WWDLW".
+package form
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+// DefaultWindows are the sizes of the rolling form windows.
+var DefaultWindows = []int{5, 10}
+
+type Team struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+type League struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Match is a finished fixture from the team's point of view.
+type Match struct {
+	FixtureID    int       `json:"fixtureId"`
+	Date         time.Time `json:"date"`
+	League       League    `json:"league"`
+	Opponent     Team      `json:"opponent"`
+	Home         bool      `json:"home"`
+	GoalsFor     int       `json:"goalsFor"`
+	GoalsAgainst int       `json:"goalsAgainst"`
+	Result       string    `json:"result"`
+	Points       int       `json:"points"`
+}
+
+// Window sums the last Size matches, or all of them when fewer were played.
+type Window struct {
+	Size          int     `json:"size"`
+	Played        int     `json:"played"`
+	Win           int     `json:"win"`
+	Draw          int     `json:"draw"`
+	Lose          int     `json:"lose"`
+	GoalsFor      int     `json:"goalsFor"`
+	GoalsAgainst  int     `json:"goalsAgainst"`
+	CleanSheets   int     `json:"cleanSheets"`
+	Points        int     `json:"points"`
+	PointsPerGame float64 `json:"pointsPerGame"`
+	// Form is the results, most recent first like the provider's.
+	Form string `json:"form"`
+}
+
+// Split is a window over every match and over home and away matches.
+type Split struct {
+	All  Window `json:"all"`
+	Home Window `json:"home"`
+	Away Window `json:"away"`
+}
+
+const (
+	StreakWins        = "wins"
+	StreakUnbeaten    = "unbeaten"
+	StreakWinless     = "winless"
+	StreakLosses      = "losses"
+	StreakCleanSheets = "cleanSheets"
+	StreakScoring     = "scoring"
+)
+
+var streakKinds = []struct {
+	name  string
+	holds func(m Match) bool
+}{
+	{StreakWins, func(m Match) bool { return m.Result == "W" }},
+	{StreakUnbeaten, func(m Match) bool { return m.Result != "L" }},
+	{StreakWinless, func(m Match) bool { return m.Result != "W" }},
+	{StreakLosses, func(m Match) bool { return m.Result == "L" }},
+	{StreakCleanSheets, func(m Match) bool { return m.GoalsAgainst == 0 }},
+	{StreakScoring, func(m Match) bool { return m.GoalsFor > 0 }},
+}
+
+// Run is a run of consecutive matches. From and To are zero for an empty run.
+type Run struct {
+	Length int       `json:"length"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
+
+type Streak struct {
+	Kind    string `json:"kind"`
+	Current Run    `json:"current"`
+	Longest Run    `json:"longest"`
+}
+
+// TrendPoint is the points per game over the window ending with a match.
+type TrendPoint struct {
+	FixtureID     int       `json:"fixtureId"`
+	Date          time.Time `json:"date"`
+	Points        int       `json:"points"`
+	PointsPerGame float64   `json:"pointsPerGame"`
+}
+
+type Report struct {
+	Team    Team     `json:"team"`
+	Played  int      `json:"played"`
+	Season  Split    `json:"season"`
+	Windows []Split  `json:"windows"`
+	Streaks []Streak `json:"streaks"`
+	// Trend is the rolling points per game over the first window, per match.
+	Trend   []TrendPoint `json:"trend"`
+	Matches []Match      `json:"matches"`
+}
+
+// Matches lists the team's finished fixtures, oldest first.
+func Matches(teamId int, fixtures []apifootball.Fixture) []Match {
+	var matches []Match
+	for _, fixture := range fixtures {
+		if !standings.Finished(fixture) {
+			continue
+		}
+		home, away := fixture.Teams.Home, fixture.Teams.Away
+		m := Match{FixtureID: fixture.Fixture.ID, Date: fixture.Fixture.Date, League: League{fixture.League.ID, fixture.League.Name}}
+		switch teamId {
+		case home.ID:
+			m.Home, m.Opponent = true, Team{away.ID, away.Name, away.Logo}
+			m.GoalsFor, m.GoalsAgainst = fixture.Goals.Home.Int, fixture.Goals.Away.Int
+		case away.ID:
+			m.Opponent = Team{home.ID, home.Name, home.Logo}
+			m.GoalsFor, m.GoalsAgainst = fixture.Goals.Away.Int, fixture.Goals.Home.Int
+		default:
+			continue
+		}
+		switch {
+		case m.GoalsFor > m.GoalsAgainst:
+			m.Result, m.Points = "W", 3
+		case m.GoalsFor < m.GoalsAgainst:
+			m.Result = "L"
+		default:
+			m.Result, m.Points = "D", 1
+		}
+		matches = append(matches, m)
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Date.Before(matches[j].Date) })
+	return matches
+}
+
+// InLeague keeps the fixtures of a competition; leagueId 0 keeps them all.
+func InLeague(fixtures []apifootball.Fixture, leagueId int) []apifootball.Fixture {
+	if leagueId == 0 {
+		return fixtures
+	}
+	var kept []apifootball.Fixture
+	for _, fixture := range fixtures {
+		if fixture.League.ID == leagueId {
+			kept = append(kept, fixture)
+		}
+	}
+	return kept
+}
+
+// Analyse reports the form of the team of teamId in the fixtures. Windows
+// default to DefaultWindows.
+func Analyse(teamId int, fixtures []apifootball.Fixture, windows []int) Report {
+	if len(windows) == 0 {
+		windows = DefaultWindows
+	}
+	report := Report{Team: Team{ID: teamId}, Matches: Matches(teamId, fixtures)}
+	for _, fixture := range fixtures {
+		switch teamId {
+		case fixture.Teams.Home.ID:
+			report.Team = Team{teamId, fixture.Teams.Home.Name, fixture.Teams.Home.Logo}
+		case fixture.Teams.Away.ID:
+			report.Team = Team{teamId, fixture.Teams.Away.Name, fixture.Teams.Away.Logo}
+		}
+	}
+	matches := report.Matches
+	report.Played = len(matches)
+	report.Season = split(matches, len(matches))
+	for _, size := range windows {
+		report.Windows = append(report.Windows, split(matches, size))
+	}
+	for _, kind := range streakKinds {
+		report.Streaks = append(report.Streaks, streak(matches, kind.name, kind.holds))
+	}
+	report.Trend = trend(matches, windows[0])
+	return report
+}
+
+func split(matches []Match, size int) Split {
+	var home, away []Match
+	for _, m := range matches {
+		if m.Home {
+			home = append(home, m)
+		} else {
+			away = append(away, m)
+		}
+	}
+	return Split{window(matches, size), window(home, size), window(away, size)}
+}
+
+// window sums the last size matches.
+func window(matches []Match, size int) Window {
+	w := Window{Size: size}
+	form := make([]byte, 0, size)
+	for i := len(matches) - 1; i >= 0 && w.Played < size; i-- {
+		m := matches[i]
+		w.Played++
+		w.GoalsFor += m.GoalsFor
+		w.GoalsAgainst += m.GoalsAgainst
+		w.Points += m.Points
+		if m.GoalsAgainst == 0 {
+			w.CleanSheets++
+		}
+		switch m.Result {
+		case "W":
+			w.Win++
+		case "D":
+			w.Draw++
+		default:
+			w.Lose++
+		}
+		form = append(form, m.Result[0])
+	}
+	if w.Played > 0 {
+		w.PointsPerGame = float64(w.Points) / float64(w.Played)
+	}
+	w.Form = string(form)
+	return w
+}
+
+func streak(matches []Match, kind string, holds func(m Match) bool) Streak {
+	s := Streak{Kind: kind}
+	var run Run
+	for _, m := range matches {
+		if !holds(m) {
+			run = Run{}
+			continue
+		}
+		if run.Length == 0 {
+			run.From = m.Date
+		}
+		run.Length++
+		run.To = m.Date
+		if run.Length > s.Longest.Length {
+			s.Longest = run
+		}
+	}
+	s.Current = run
+	return s
+}
+
+func trend(matches []Match, size int) []TrendPoint {
+	points := make([]TrendPoint, 0, len(matches))
+	sum := 0
+	for i, m := range matches {
+		sum += m.Points
+		if i >= size {
+			sum -= matches[i-size].Points
+		}
+		n := i + 1
+		if n > size {
+			n = size
+		}
+		points = append(points, TrendPoint{m.FixtureID, m.Date, m.Points, float64(sum) / float64(n)})
+	}
+	return points
+}
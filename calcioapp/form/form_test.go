@@ -0,0 +1,111 @@
+package form
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+	"github.com/nero-15/calcio-app/internal/fixturetest"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+func fixture(id int, day int, home int, away int, homeGoals int, awayGoals int) apifootball.Fixture {
+	return fixturetest.Finished(id, fixturetest.Date(2021, time.September, day), home, away, homeGoals, awayGoals)
+}
+
+func TestAnalyse(t *testing.T) {
+	upcoming := fixture(8, 30, 1, 2, 0, 0)
+	upcoming.Fixture.Status.Short = "NS"
+	cup := fixture(7, 26, 9, 1, 0, 0)
+	cup.League.ID = 137
+	fixtures := []apifootball.Fixture{
+		fixture(3, 10, 1, 4, 0, 0), // D, clean sheet
+		fixture(1, 1, 1, 2, 2, 0),  // W, clean sheet
+		fixture(2, 5, 3, 1, 1, 1),  // D away
+		fixture(4, 15, 5, 1, 2, 1), // L away
+		fixture(5, 20, 1, 6, 3, 1), // W
+		fixture(6, 24, 7, 1, 0, 2), // W away, clean sheet
+		cup,                        // D away, clean sheet
+		upcoming,
+		fixture(9, 3, 2, 3, 1, 0), // not team 1
+	}
+	report := Analyse(1, fixtures, []int{3, 10})
+	if report.Played != 7 {
+		t.Fatalf("played %d, want 7", report.Played)
+	}
+	if season := report.Season.All; season.Form != "DWWLDDW" || season.Points != 12 || season.CleanSheets != 4 {
+		t.Errorf("season = %+v", season)
+	}
+	last3 := report.Windows[0]
+	if last3.All.Form != "DWW" || last3.All.PointsPerGame != 7.0/3 {
+		t.Errorf("last 3 = %+v", last3.All)
+	}
+	if last3.Home.Form != "WDW" || last3.Away.Form != "DWL" {
+		t.Errorf("last 3 home %q, away %q", last3.Home.Form, last3.Away.Form)
+	}
+	if last10 := report.Windows[1].All; last10.Played != 7 || last10.Size != 10 {
+		t.Errorf("last 10 = %+v", last10)
+	}
+
+	streaks := map[string]Streak{}
+	for _, s := range report.Streaks {
+		streaks[s.Kind] = s
+	}
+	for kind, want := range map[string][2]int{
+		StreakWins:        {0, 2},
+		StreakUnbeaten:    {3, 3},
+		StreakWinless:     {1, 3},
+		StreakCleanSheets: {2, 2},
+		StreakScoring:     {0, 3},
+	} {
+		s := streaks[kind]
+		if s.Current.Length != want[0] || s.Longest.Length != want[1] {
+			t.Errorf("%s streak = %d current, %d longest, want %v", kind, s.Current.Length, s.Longest.Length, want)
+		}
+	}
+	if unbeaten := streaks[StreakUnbeaten].Current; unbeaten.From.Day() != 20 || unbeaten.To.Day() != 26 {
+		t.Errorf("unbeaten run = %+v", unbeaten)
+	}
+
+	var ppg []float64
+	for _, point := range report.Trend {
+		ppg = append(ppg, point.PointsPerGame)
+	}
+	if want := []float64{3, 2, 5.0 / 3, 2.0 / 3, 4.0 / 3, 2, 7.0 / 3}; !reflect.DeepEqual(ppg, want) {
+		t.Errorf("trend = %v, want %v", ppg, want)
+	}
+
+	if league := Analyse(1, InLeague(fixtures, 135), nil); league.Played != 6 || len(league.Windows) != len(DefaultWindows) {
+		t.Errorf("league report played %d with %d windows", league.Played, len(league.Windows))
+	}
+}
+
+func TestAnalyseMatchesStandings(t *testing.T) {
+	server := httptest.NewServer(fake.New(fake.Options{DailyLimit: -1}))
+	defer server.Close()
+	client := apifootball.New("test-token", server.URL+"/")
+	fixtures, err := client.GetFixturesByTeamId("505")
+	if err != nil {
+		t.Fatal(err)
+	}
+	league, err := client.GetFixturesByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	row, _ := standings.Compute(league.Response, standings.SerieA).Row(505)
+
+	report := Analyse(505, InLeague(fixtures.Response, 135), []int{5})
+	season := report.Season
+	if season.All.Points != row.Points || season.All.GoalsFor != row.All.Goals.For || season.Home.Win != row.Home.Win || season.Away.Lose != row.Away.Lose {
+		t.Errorf("season %+v does not match standings %+v", season, row)
+	}
+	if report.Windows[0].All.Form != row.Form {
+		t.Errorf("form %q, standings %q", report.Windows[0].All.Form, row.Form)
+	}
+	if all := Analyse(505, fixtures.Response, nil); all.Played <= report.Played {
+		t.Errorf("cup matches not counted: %d and %d", all.Played, report.Played)
+	}
+}
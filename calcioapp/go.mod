@@ -3,7 +3,8 @@ module github.com/nero-15/calcio-app
 go 1.14
 
 require (
-	github.com/labstack/echo v3.3.10+incompatible
 	github.com/labstack/echo/v4 v4.6.1
+	github.com/prometheus/client_golang v1.11.1
+	github.com/sirupsen/logrus v1.8.1
 	gopkg.in/ini.v1 v1.66.2
 )
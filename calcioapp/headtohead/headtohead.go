@@ -0,0 +1,233 @@
+// Package headtohead summarises the meetings of two teams across every
+// competition and season.
+package headtohead
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+// DefaultRecent is the number of recent meetings of a summary.
+const DefaultRecent = 5
+
+type Team struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+type Competition struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Logo   string `json:"logo"`
+	Season int    `json:"season"`
+	Round  string `json:"round"`
+}
+
+// Meeting is one finished match of the two teams. Goals include extra
+// time; a tie decided on penalties is a draw, with the shoot-out in
+// Penalties.
+type Meeting struct {
+	FixtureID   int         `json:"fixtureId"`
+	Date        time.Time   `json:"date"`
+	Competition Competition `json:"competition"`
+	Venue       string      `json:"venue"`
+	Home        Team        `json:"home"`
+	Away        Team        `json:"away"`
+	HomeGoals   int         `json:"homeGoals"`
+	AwayGoals   int         `json:"awayGoals"`
+	Penalties   *string     `json:"penalties"`
+	// Winner is the id of the winning team, 0 for a draw.
+	Winner int `json:"winner"`
+}
+
+// Record is a team's results against the other, from its side.
+type Record struct {
+	Played       int `json:"played"`
+	Wins         int `json:"wins"`
+	Draws        int `json:"draws"`
+	Losses       int `json:"losses"`
+	GoalsFor     int `json:"goalsFor"`
+	GoalsAgainst int `json:"goalsAgainst"`
+}
+
+func (r *Record) add(scored int, conceded int) {
+	r.Played++
+	r.GoalsFor += scored
+	r.GoalsAgainst += conceded
+	switch {
+	case scored > conceded:
+		r.Wins++
+	case scored < conceded:
+		r.Losses++
+	default:
+		r.Draws++
+	}
+}
+
+type CompetitionRecord struct {
+	Competition Competition `json:"competition"`
+	Record      Record      `json:"record"`
+}
+
+// Scoreline is a result from the side of the summary's team, e.g. "2-1"
+// for a win by a goal, and how often it happened.
+type Scoreline struct {
+	Score string `json:"score"`
+	Count int    `json:"count"`
+}
+
+// Summary is the meetings of Team and Opponent, every record from Team's
+// side.
+type Summary struct {
+	Team     Team   `json:"team"`
+	Opponent Team   `json:"opponent"`
+	Overall  Record `json:"overall"`
+	// Home is Team's record when it played at home, Away when Opponent did.
+	Home         Record              `json:"home"`
+	Away         Record              `json:"away"`
+	Competitions []CompetitionRecord `json:"competitions"`
+	// BiggestWins are each side's widest winning margin, the most goals
+	// scored breaking ties; null for a side that never won.
+	BiggestWins struct {
+		Team     *Meeting `json:"team"`
+		Opponent *Meeting `json:"opponent"`
+	} `json:"biggestWins"`
+	Scorelines []Scoreline `json:"scorelines"`
+	// Recent are the latest meetings, newest first.
+	Recent []Meeting `json:"recent"`
+	// Next is the next scheduled meeting, if any.
+	Next *Meeting `json:"next"`
+}
+
+func meetingOf(f apifootball.Fixture) Meeting {
+	m := Meeting{
+		FixtureID:   f.Fixture.ID,
+		Date:        f.Fixture.Date,
+		Competition: Competition{f.League.ID, f.League.Name, f.League.Logo, f.League.Season, f.League.Round},
+		Venue:       f.Fixture.Venue.Name.String,
+		Home:        Team{f.Teams.Home.ID, f.Teams.Home.Name, f.Teams.Home.Logo},
+		Away:        Team{f.Teams.Away.ID, f.Teams.Away.Name, f.Teams.Away.Logo},
+		HomeGoals:   f.Goals.Home.Int,
+		AwayGoals:   f.Goals.Away.Int,
+	}
+	if f.Score.Penalty.Home.Valid && f.Score.Penalty.Away.Valid {
+		penalties := fmt.Sprintf("%d-%d", f.Score.Penalty.Home.Int, f.Score.Penalty.Away.Int)
+		m.Penalties = &penalties
+	}
+	switch {
+	case m.HomeGoals > m.AwayGoals:
+		m.Winner = m.Home.ID
+	case m.AwayGoals > m.HomeGoals:
+		m.Winner = m.Away.ID
+	}
+	return m
+}
+
+// margin is the goal difference of a meeting for team.
+func (m Meeting) margin(team int) (int, int) {
+	if m.Home.ID == team {
+		return m.HomeGoals - m.AwayGoals, m.HomeGoals
+	}
+	return m.AwayGoals - m.HomeGoals, m.AwayGoals
+}
+
+// Summarise builds the summary of the meetings of teamId and opponentId
+// among fixtures, listing the recent latest ones.
+func Summarise(teamId int, opponentId int, fixtures []apifootball.Fixture, recent int) (Summary, bool) {
+	summary := Summary{Competitions: []CompetitionRecord{}, Scorelines: []Scoreline{}, Recent: []Meeting{}}
+	var meetings []Meeting
+	known := false
+	for _, f := range fixtures {
+		home, away := f.Teams.Home, f.Teams.Away
+		if !(home.ID == teamId && away.ID == opponentId || home.ID == opponentId && away.ID == teamId) {
+			continue
+		}
+		known = true
+		m := meetingOf(f)
+		if m.Home.ID == teamId {
+			summary.Team, summary.Opponent = m.Home, m.Away
+		} else {
+			summary.Team, summary.Opponent = m.Away, m.Home
+		}
+		if !standings.Finished(f) {
+			if f.Fixture.Status.Short == "NS" && (summary.Next == nil || m.Date.Before(summary.Next.Date)) {
+				next := m
+				summary.Next = &next
+			}
+			continue
+		}
+		meetings = append(meetings, m)
+	}
+	if !known {
+		return Summary{}, false
+	}
+
+	sort.SliceStable(meetings, func(i, j int) bool { return meetings[i].Date.After(meetings[j].Date) })
+	competitions := map[string]int{}
+	scorelines := map[string]int{}
+	for i := range meetings {
+		m := &meetings[i]
+		margin, scored := m.margin(teamId)
+		conceded := scored - margin
+		summary.Overall.add(scored, conceded)
+		if m.Home.ID == teamId {
+			summary.Home.add(scored, conceded)
+		} else {
+			summary.Away.add(scored, conceded)
+		}
+
+		key := fmt.Sprintf("%d/%d", m.Competition.ID, m.Competition.Season)
+		index, ok := competitions[key]
+		if !ok {
+			index = len(summary.Competitions)
+			competitions[key] = index
+			competition := m.Competition
+			competition.Round = ""
+			summary.Competitions = append(summary.Competitions, CompetitionRecord{Competition: competition})
+		}
+		summary.Competitions[index].Record.add(scored, conceded)
+
+		score := fmt.Sprintf("%d-%d", scored, conceded)
+		if _, ok := scorelines[score]; !ok {
+			summary.Scorelines = append(summary.Scorelines, Scoreline{Score: score})
+		}
+		scorelines[score]++
+
+		summary.BiggestWins.Team = biggest(summary.BiggestWins.Team, m, teamId)
+		summary.BiggestWins.Opponent = biggest(summary.BiggestWins.Opponent, m, opponentId)
+	}
+	for i := range summary.Scorelines {
+		summary.Scorelines[i].Count = scorelines[summary.Scorelines[i].Score]
+	}
+	// Meetings are newest first, so ties go to the most recent scoreline.
+	sort.SliceStable(summary.Scorelines, func(i, j int) bool {
+		return summary.Scorelines[i].Count > summary.Scorelines[j].Count
+	})
+	if recent > len(meetings) {
+		recent = len(meetings)
+	}
+	summary.Recent = append(summary.Recent, meetings[:recent]...)
+	return summary, true
+}
+
+// biggest keeps the widest win of team between best and m. Meetings come
+// newest first, so the most recent of equal wins is kept.
+func biggest(best *Meeting, m *Meeting, team int) *Meeting {
+	if m.Winner != team {
+		return best
+	}
+	if best == nil {
+		return m
+	}
+	margin, scored := m.margin(team)
+	bestMargin, bestScored := best.margin(team)
+	if margin > bestMargin || margin == bestMargin && scored > bestScored {
+		return m
+	}
+	return best
+}
@@ -0,0 +1,91 @@
+package headtohead
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+	"github.com/nero-15/calcio-app/internal/fixturetest"
+)
+
+func fixture(id int, year int, league int, home int, away int, homeGoals int, awayGoals int) apifootball.Fixture {
+	f := fixturetest.Finished(id, fixturetest.Date(year, time.October, 1), home, away, homeGoals, awayGoals)
+	f.League.ID = league
+	return f
+}
+
+func TestSummarise(t *testing.T) {
+	shootout := fixture(5, 2020, 137, 2, 1, 1, 1)
+	shootout.Fixture.Date = time.Date(2021, 3, 1, 18, 0, 0, 0, time.UTC)
+	shootout.Fixture.Status.Short = "PEN"
+	shootout.Score.Penalty.Home = apifootball.NullInt{Int: 4, Valid: true}
+	shootout.Score.Penalty.Away = apifootball.NullInt{Int: 3, Valid: true}
+	upcoming := fixture(7, 2022, 135, 2, 1, 0, 0)
+	upcoming.Fixture.Status.Short = "NS"
+	fixtures := []apifootball.Fixture{
+		fixture(1, 2017, 135, 1, 2, 3, 0),
+		fixture(2, 2018, 135, 2, 1, 2, 1),
+		fixture(3, 2019, 135, 1, 2, 2, 1),
+		fixture(4, 2020, 135, 2, 1, 1, 2),
+		shootout,
+		fixture(6, 2021, 135, 1, 2, 2, 1),
+		upcoming,
+		fixture(8, 2021, 135, 1, 3, 5, 0),
+	}
+
+	summary, ok := Summarise(1, 2, fixtures, 3)
+	if !ok {
+		t.Fatal("no summary")
+	}
+	if o := summary.Overall; o.Played != 6 || o.Wins != 4 || o.Draws != 1 || o.Losses != 1 || o.GoalsFor != 11 || o.GoalsAgainst != 6 {
+		t.Errorf("overall = %+v", o)
+	}
+	if h, a := summary.Home, summary.Away; h.Played != 3 || h.Wins != 3 || a.Played != 3 || a.Losses != 1 {
+		t.Errorf("home %+v, away %+v", h, a)
+	}
+	if len(summary.Competitions) != 6 {
+		t.Errorf("%d competition seasons, want 6", len(summary.Competitions))
+	}
+	if w := summary.BiggestWins.Team; w == nil || w.FixtureID != 1 {
+		t.Errorf("biggest win = %+v", w)
+	}
+	if w := summary.BiggestWins.Opponent; w == nil || w.FixtureID != 2 {
+		t.Errorf("opponent biggest win = %+v", w)
+	}
+	if s := summary.Scorelines[0]; s.Score != "2-1" || s.Count != 3 {
+		t.Errorf("most common scoreline = %+v", s)
+	}
+	if len(summary.Recent) != 3 || summary.Recent[0].FixtureID != 6 || summary.Recent[1].FixtureID != 5 {
+		t.Errorf("recent = %+v", summary.Recent)
+	}
+	if p := summary.Recent[1]; p.Winner != 0 || p.Penalties == nil || *p.Penalties != "4-3" {
+		t.Errorf("shoot-out = %+v", p)
+	}
+	if summary.Next == nil || summary.Next.FixtureID != 7 {
+		t.Errorf("next = %+v", summary.Next)
+	}
+
+	if _, ok := Summarise(1, 9, fixtures, 3); ok {
+		t.Error("summary of teams that never met")
+	}
+}
+
+func TestProvider(t *testing.T) {
+	ts := httptest.NewServer(fake.New(fake.Options{DailyLimit: -1}))
+	defer ts.Close()
+	client := apifootball.New("test-token", ts.URL+"/")
+
+	fixtures, err := client.GetHeadtoheadByTeamIds("505", "489")
+	if err != nil || fixtures.Results == 0 {
+		t.Fatalf("%d meetings (%v)", fixtures.Results, err)
+	}
+	summary, ok := Summarise(505, 489, fixtures.Response, DefaultRecent)
+	if !ok || summary.Team.ID != 505 || summary.Opponent.ID != 489 {
+		t.Fatalf("summary = %+v", summary)
+	}
+	if o := summary.Overall; o.Wins+o.Draws+o.Losses != o.Played || summary.Home.Played+summary.Away.Played != o.Played {
+		t.Errorf("overall = %+v", o)
+	}
+}
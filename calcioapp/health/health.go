@@ -0,0 +1,155 @@
+package health
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+const (
+	StatusOk   = "ok"
+	StatusFail = "fail"
+)
+
+type Check struct {
+	Name   string      `json:"name"`
+	Status string      `json:"status"`
+	Error  string      `json:"error,omitempty"`
+	Detail interface{} `json:"detail,omitempty"`
+}
+
+type Report struct {
+	Status    string    `json:"status"`
+	CheckedAt time.Time `json:"checkedAt"`
+	Checks    []Check   `json:"checks"`
+}
+
+func (r Report) Ok() bool {
+	return r.Status == StatusOk
+}
+
+type ApiFootball interface {
+	GetStatus() (apifootball.Status, error)
+}
+
+type FootballData interface {
+	Ping() error
+}
+
+// Checker runs the readiness checks. Results are kept for ttl so that
+// frequent probes do not hit the providers on every call.
+type Checker struct {
+	config       func() error
+	apiFootball  ApiFootball
+	footballData FootballData
+	ttl          time.Duration
+
+	mu   sync.Mutex
+	last Report
+}
+
+func NewChecker(config func() error, apiFootball ApiFootball, footballData FootballData, ttl time.Duration) *Checker {
+	return &Checker{config: config, apiFootball: apiFootball, footballData: footballData, ttl: ttl}
+}
+
+func (ch *Checker) Ready() Report {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	if !ch.last.CheckedAt.IsZero() && time.Since(ch.last.CheckedAt) < ch.ttl {
+		return ch.last
+	}
+
+	checks := make([]Check, 3)
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		checks[0] = ch.checkConfig()
+	}()
+	go func() {
+		defer wg.Done()
+		checks[1] = ch.checkApiFootball()
+	}()
+	go func() {
+		defer wg.Done()
+		checks[2] = ch.checkFootballData()
+	}()
+	wg.Wait()
+
+	report := Report{Status: StatusOk, CheckedAt: time.Now(), Checks: checks}
+	for _, check := range checks {
+		if check.Status != StatusOk {
+			report.Status = StatusFail
+		}
+	}
+	ch.last = report
+	return report
+}
+
+func (ch *Checker) checkConfig() Check {
+	check := Check{Name: "config", Status: StatusOk}
+	if err := ch.config(); err != nil {
+		check.Status = StatusFail
+		check.Error = err.Error()
+	}
+	return check
+}
+
+type apiFootballDetail struct {
+	Plan      string    `json:"plan"`
+	Active    bool      `json:"active"`
+	End       time.Time `json:"end"`
+	Current   int       `json:"current"`
+	LimitDay  int       `json:"limitDay"`
+	Remaining int       `json:"remaining"`
+}
+
+func (ch *Checker) checkApiFootball() Check {
+	check := Check{Name: "apiFootball", Status: StatusOk}
+	status, err := ch.apiFootball.GetStatus()
+	if messages := status.ErrorMessages(); err == nil && len(messages) > 0 {
+		err = fmt.Errorf("apifootball: %v", messages)
+	}
+	if err != nil {
+		check.Status = StatusFail
+		check.Error = err.Error()
+		return check
+	}
+
+	subscription := status.Response.Subscription
+	requests := status.Response.Requests
+	detail := apiFootballDetail{
+		Plan:      subscription.Plan,
+		Active:    subscription.Active,
+		End:       subscription.End,
+		Current:   requests.Current,
+		LimitDay:  requests.LimitDay,
+		Remaining: requests.LimitDay - requests.Current,
+	}
+	check.Detail = detail
+
+	switch {
+	case !detail.Active:
+		check.Error = "subscription is not active"
+	case !detail.End.IsZero() && detail.End.Before(time.Now()):
+		check.Error = "subscription ended at " + detail.End.Format(time.RFC3339)
+	case detail.Remaining <= 0:
+		check.Error = "daily request quota is exhausted"
+	}
+	if check.Error != "" {
+		check.Status = StatusFail
+	}
+	return check
+}
+
+func (ch *Checker) checkFootballData() Check {
+	check := Check{Name: "footballData", Status: StatusOk}
+	if err := ch.footballData.Ping(); err != nil {
+		check.Status = StatusFail
+		check.Error = err.Error()
+	}
+	return check
+}
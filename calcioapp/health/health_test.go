@@ -0,0 +1,114 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+type stubApiFootball struct {
+	status apifootball.Status
+	err    error
+	calls  int
+}
+
+func (s *stubApiFootball) GetStatus() (apifootball.Status, error) {
+	s.calls++
+	return s.status, s.err
+}
+
+type stubFootballData struct {
+	err   error
+	calls int
+}
+
+func (s *stubFootballData) Ping() error {
+	s.calls++
+	return s.err
+}
+
+func status(active bool, end time.Time, current int, limitDay int) apifootball.Status {
+	var s apifootball.Status
+	s.Response.Subscription.Plan = "Free"
+	s.Response.Subscription.Active = active
+	s.Response.Subscription.End = end
+	s.Response.Requests.Current = current
+	s.Response.Requests.LimitDay = limitDay
+	return s
+}
+
+func configOk() error { return nil }
+
+func TestReady(t *testing.T) {
+	future := time.Now().Add(30 * 24 * time.Hour)
+	for _, c := range []struct {
+		name         string
+		config       func() error
+		apiFootball  *stubApiFootball
+		footballData *stubFootballData
+		// failed is the check expected to fail, "" when all pass.
+		failed string
+	}{
+		{"ok", configOk, &stubApiFootball{status: status(true, future, 10, 100)}, &stubFootballData{}, ""},
+		{"invalid config", func() error { return errors.New("missing token") }, &stubApiFootball{status: status(true, future, 10, 100)}, &stubFootballData{}, "config"},
+		{"apiFootball down", configOk, &stubApiFootball{err: errors.New("timeout")}, &stubFootballData{}, "apiFootball"},
+		{"apiFootball errors", configOk, &stubApiFootball{status: func() apifootball.Status {
+			s := status(true, future, 10, 100)
+			s.Errors = json.RawMessage(`{"token": "invalid"}`)
+			return s
+		}()}, &stubFootballData{}, "apiFootball"},
+		{"subscription inactive", configOk, &stubApiFootball{status: status(false, future, 10, 100)}, &stubFootballData{}, "apiFootball"},
+		{"subscription ended", configOk, &stubApiFootball{status: status(true, time.Now().Add(-time.Hour), 10, 100)}, &stubFootballData{}, "apiFootball"},
+		{"quota exhausted", configOk, &stubApiFootball{status: status(true, future, 100, 100)}, &stubFootballData{}, "apiFootball"},
+		{"footballData down", configOk, &stubApiFootball{status: status(true, future, 10, 100)}, &stubFootballData{err: errors.New("503")}, "footballData"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			report := NewChecker(c.config, c.apiFootball, c.footballData, time.Minute).Ready()
+			if report.Ok() != (c.failed == "") {
+				t.Errorf("status = %s, checks %+v", report.Status, report.Checks)
+			}
+			if len(report.Checks) != 3 {
+				t.Fatalf("checks = %+v", report.Checks)
+			}
+			for _, check := range report.Checks {
+				failed := check.Name == c.failed
+				if (check.Status == StatusFail) != failed || (check.Error != "") != failed {
+					t.Errorf("check %+v", check)
+				}
+			}
+		})
+	}
+}
+
+func TestReadyDetail(t *testing.T) {
+	api := &stubApiFootball{status: status(true, time.Now().Add(time.Hour), 30, 100)}
+	report := NewChecker(configOk, api, &stubFootballData{}, time.Minute).Ready()
+	detail, ok := report.Checks[1].Detail.(apiFootballDetail)
+	if !ok || detail.Remaining != 70 || detail.Plan != "Free" {
+		t.Errorf("detail = %+v", report.Checks[1].Detail)
+	}
+}
+
+func TestReadyCached(t *testing.T) {
+	api := &stubApiFootball{status: status(true, time.Now().Add(time.Hour), 10, 100)}
+	data := &stubFootballData{}
+	checker := NewChecker(configOk, api, data, time.Minute)
+
+	first := checker.Ready()
+	// A failure within the TTL is not seen: the last report is served.
+	data.err = errors.New("503")
+	second := checker.Ready()
+	if !second.Ok() || !second.CheckedAt.Equal(first.CheckedAt) || api.calls != 1 || data.calls != 1 {
+		t.Errorf("cached report %+v after %d and %d calls", second, api.calls, data.calls)
+	}
+
+	// Past the TTL the providers are checked again.
+	checker.ttl = 0
+	third := checker.Ready()
+	if third.Ok() || api.calls != 2 || data.calls != 2 {
+		t.Errorf("refreshed report %+v after %d and %d calls", third, api.calls, data.calls)
+	}
+}
@@ -0,0 +1,32 @@
+// Package fixturetest builds the provider fixtures of the tests.
+package fixturetest
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+// SerieA is the league of the fixtures.
+const SerieA = 135
+
+// Date is a kickoff at 18:00 UTC.
+func Date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 18, 0, 0, 0, time.UTC)
+}
+
+// Finished is a fixture of the season starting in the year of date,
+// finished with the given score. Teams are named Team <id>.
+func Finished(id int, date time.Time, home int, away int, homeGoals int, awayGoals int) apifootball.Fixture {
+	var f apifootball.Fixture
+	f.Fixture.ID = id
+	f.Fixture.Date = date
+	f.Fixture.Status.Short, f.Fixture.Status.Long = "FT", "Match Finished"
+	f.League.ID, f.League.Name, f.League.Season = SerieA, "Serie A", date.Year()
+	f.Teams.Home.ID, f.Teams.Home.Name = home, "Team "+strconv.Itoa(home)
+	f.Teams.Away.ID, f.Teams.Away.Name = away, "Team "+strconv.Itoa(away)
+	f.Goals.Home = apifootball.NullInt{Int: homeGoals, Valid: true}
+	f.Goals.Away = apifootball.NullInt{Int: awayGoals, Valid: true}
+	return f
+}
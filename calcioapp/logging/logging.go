@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"context"
+	"os"
+	"time"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey struct{}
+
+// Logger writes one JSON object per line to stdout.
+var Logger = &logrus.Logger{
+	Out:       os.Stdout,
+	Formatter: &logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano},
+	Hooks:     make(logrus.LevelHooks),
+	Level:     logrus.InfoLevel,
+}
+
+// SetLevel sets the minimum level by name (debug, info, warn, error).
+// Unknown names leave the level unchanged.
+func SetLevel(name string) error {
+	level, err := logrus.ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	Logger.SetLevel(level)
+	return nil
+}
+
+func WithRequestID(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, contextKey{}, requestId)
+}
+
+func RequestID(ctx context.Context) string {
+	requestId, _ := ctx.Value(contextKey{}).(string)
+	return requestId
+}
+
+// Detach returns a context carrying the request id of ctx but not its
+// deadline or cancellation, for work that outlives the request.
+func Detach(ctx context.Context) context.Context {
+	return WithRequestID(context.Background(), RequestID(ctx))
+}
+
+// FromContext returns a log entry carrying the request id of ctx, if any.
+func FromContext(ctx context.Context) *logrus.Entry {
+	entry := logrus.NewEntry(Logger)
+	if requestId := RequestID(ctx); requestId != "" {
+		entry = entry.WithField("request_id", requestId)
+	}
+	return entry
+}
+
+// Middleware stores the request id set by middleware.RequestID in the
+// request context and writes one access log line per request.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+			requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+			if requestId == "" {
+				requestId = req.Header.Get(echo.HeaderXRequestID)
+			}
+			ctx := WithRequestID(req.Context(), requestId)
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			res := c.Response()
+			entry := FromContext(ctx).WithFields(logrus.Fields{
+				"remote_ip":  c.RealIP(),
+				"host":       req.Host,
+				"method":     req.Method,
+				"uri":        req.RequestURI,
+				"route":      c.Path(),
+				"status":     res.Status,
+				"bytes_out":  res.Size,
+				"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			})
+			if err != nil {
+				entry = entry.WithError(err)
+			}
+			switch {
+			case res.Status >= 500:
+				entry.Error("request")
+			case res.Status >= 400:
+				entry.Warn("request")
+			default:
+				entry.Info("request")
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package logging_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/footballData"
+	"github.com/nero-15/calcio-app/logging"
+)
+
+func TestMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	out := logging.Logger.Out
+	logging.Logger.Out = &logs
+	defer func() { logging.Logger.Out = out }()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+	apiFootball := apifootball.New("test-token", upstream.URL+"/")
+	footballDataClient := footballData.New("test-token", upstream.URL+"/")
+
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(logging.Middleware())
+	e.GET("/api/status", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if _, err := apiFootball.WithContext(ctx).GetStatus(); err != nil {
+			t.Fatal(err)
+		}
+		if err := footballDataClient.WithContext(ctx).Ping(); err != nil {
+			t.Fatal(err)
+		}
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "quota exhausted")
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/status", nil)
+	req.Header.Set(echo.HeaderXRequestID, "req-1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d", rec.Code)
+	}
+
+	upstreams := map[string]bool{}
+	var access map[string]interface{}
+	scanner := bufio.NewScanner(&logs)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("%v: %s", err, scanner.Text())
+		}
+		if entry["request_id"] != "req-1" {
+			t.Errorf("entry without the request id: %v", entry)
+		}
+		switch entry["msg"] {
+		case "upstream request":
+			upstreams[entry["provider"].(string)] = true
+		case "request":
+			access = entry
+		}
+	}
+	if !upstreams["apifootball"] || !upstreams["footballData"] {
+		t.Errorf("upstream entries of %v", upstreams)
+	}
+	if access == nil || access["status"] != float64(http.StatusServiceUnavailable) || access["level"] != "error" || access["route"] != "/api/status" {
+		t.Errorf("access entry = %v", access)
+	}
+}
@@ -1,22 +1,44 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
-	"html/template"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	echo "github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
 	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/atom"
+	"github.com/nero-15/calcio-app/backtest"
+	"github.com/nero-15/calcio-app/calendar"
 	"github.com/nero-15/calcio-app/config"
+	"github.com/nero-15/calcio-app/export"
 	"github.com/nero-15/calcio-app/footballData"
+	"github.com/nero-15/calcio-app/form"
+	"github.com/nero-15/calcio-app/headtohead"
+	"github.com/nero-15/calcio-app/health"
+	"github.com/nero-15/calcio-app/logging"
+	"github.com/nero-15/calcio-app/metrics"
+	"github.com/nero-15/calcio-app/outcome"
+	"github.com/nero-15/calcio-app/pitch"
+	"github.com/nero-15/calcio-app/players"
+	"github.com/nero-15/calcio-app/ratings"
+	"github.com/nero-15/calcio-app/report"
+	"github.com/nero-15/calcio-app/simulation"
+	"github.com/nero-15/calcio-app/standings"
+	"github.com/nero-15/calcio-app/tactics"
+	"github.com/nero-15/calcio-app/timing"
+	"github.com/nero-15/calcio-app/views"
 )
 
 // TemplateRenderer is a custom html/template renderer for Echo framework
 type TemplateRenderer struct {
-	templates *template.Template
+	templates *views.Set
 }
 
 // Render renders a template document
@@ -27,37 +49,177 @@ func (t *TemplateRenderer) Render(w io.Writer, name string, data interface{}, c
 		viewContext["reverse"] = c.Echo().Reverse
 	}
 
-	return t.templates.ExecuteTemplate(w, name, data)
+	return t.templates.Execute(w, name, data)
 }
 
 func main() {
 	e := echo.New()
 
 	renderer := &TemplateRenderer{
-		templates: template.Must(template.New("").Delims("[[", "]]").ParseGlob("views/*.html")), // vue.jsとdelimsがかぶるので変更
+		templates: views.Must(views.Load("views")), // vue.jsとdelimsがかぶるので[[ ]]
 	}
 	e.Renderer = renderer
 
-	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
-		Format: `"time":"${time_rfc3339}","remote_ip":"${remote_ip}","host":"${host}",` +
-			`"method":"${method}","uri":"${uri}","status":${status},"error":"${error}"` + "\n",
-	}))
+	if err := logging.SetLevel(config.Config.LogLevel); err != nil {
+		logging.Logger.WithError(err).Warn("invalid log level, using info")
+	}
+	e.Use(middleware.RequestID())
+	e.Use(logging.Middleware())
+	e.Use(metrics.Middleware())
 	e.Use(middleware.Recover())
 
-	apifootball := apifootball.New(config.Config.ApiFootballApiToken, config.Config.ApiFootballBaseUrl)
+	decodeMode, err := apifootball.ParseDecodeMode(config.Config.ApiFootballDecode)
+	if err != nil {
+		logging.Logger.WithError(err).Warn("invalid apiFootball decode mode, using lenient")
+	}
+	apifootball := apifootball.New(config.Config.ApiFootballApiToken, config.Config.ApiFootballBaseUrl).WithDecodeMode(decodeMode)
+	checker := health.NewChecker(
+		config.Config.Validate,
+		apifootball,
+		footballData.New(config.Config.FootballDataApiToken, config.Config.FootballDataBaseUrl),
+		30*time.Second,
+	)
+	snapshots := standings.NewSnapshotCache()
+	timings := timing.NewCache(time.Hour)
+	formations := tactics.NewCache(6 * time.Hour)
+	profiles := players.NewCache(6 * time.Hour)
+	// negotiateExport reads whether a route with a CSV export answers in CSV,
+	// from ?format=csv or the Accept header, and ?bom=1 for Excel.
+	negotiateExport := func(c echo.Context) (export.Options, error) {
+		options, ok := export.Negotiate(c.QueryParam("format"), c.QueryParam("bom"), c.Request().Header.Get(echo.HeaderAccept))
+		if !ok {
+			return options, echo.NewHTTPError(http.StatusBadRequest, "invalid format")
+		}
+		return options, nil
+	}
+	writeExport := func(c echo.Context, table export.Table, id string, options export.Options) error {
+		c.Response().Header().Set(echo.HeaderContentType, export.ContentType)
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+export.Filename(table, id)+`"`)
+		c.Response().WriteHeader(http.StatusOK)
+		return export.Write(c.Response(), table, options)
+	}
+	elo, err := ratings.Load(config.Config.RatingsFile, ratings.DefaultParams)
+	if err != nil {
+		logging.Logger.WithError(err).Warn("saved ratings not loaded, rating from scratch")
+		elo = ratings.New(ratings.DefaultParams)
+	}
+	calendarRevisions := calendar.NewRevisions()
+	ratingsUpdater := &ratings.Updater{
+		Engine:   elo,
+		Leagues:  config.Config.RatingsLeagues,
+		File:     config.Config.RatingsFile,
+		Interval: 10 * time.Minute,
+		Fetch:    ratings.FixturesFrom(apifootball),
+	}
 
 	e.GET("/", func(c echo.Context) error {
 		return c.Render(http.StatusOK, "index.html", map[string]interface{}{})
 	})
 
+	e.GET("/leagues/:leagueId", func(c echo.Context) error {
+		if _, err := strconv.Atoi(c.Param("leagueId")); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid leagueId")
+		}
+		standings, err := apifootball.WithContext(c.Request().Context()).GetStandingsByLeagueId(c.Param("leagueId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		page, ok := views.LeagueOf(standings)
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		return c.Render(http.StatusOK, "league.html", page)
+	})
+
+	e.GET("/leagues/:leagueId/fixtures", func(c echo.Context) error {
+		if _, err := strconv.Atoi(c.Param("leagueId")); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid leagueId")
+		}
+		fixtures, err := apifootball.WithContext(c.Request().Context()).GetFixturesByLeagueId(c.Param("leagueId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		page, ok := views.FixturesOf(fixtures.Response)
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		return c.Render(http.StatusOK, "fixtures.html", page)
+	})
+
+	e.GET("/fixtures/:fixtureId", func(c echo.Context) error {
+		fixtureId, err := strconv.Atoi(c.Param("fixtureId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid fixtureId")
+		}
+		parts, err := report.Fetch(c.Request().Context(), apifootball, fixtureId)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		return c.Render(http.StatusOK, "match.html", report.Build(parts))
+	})
+
+	e.GET("/teams/:teamId", func(c echo.Context) error {
+		teamId, err := strconv.Atoi(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid teamId")
+		}
+		client := apifootball.WithContext(c.Request().Context())
+		fixtures, err := client.GetFixturesByTeamId(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		// Without a squad the page still has the fixtures.
+		squads, _ := client.GetSquadsByTeamId(c.Param("teamId"))
+		page, ok := views.TeamOf(teamId, fixtures.Response, squads)
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		return c.Render(http.StatusOK, "team.html", page)
+	})
+
+	e.GET("/players/:playerId", func(c echo.Context) error {
+		if _, err := strconv.Atoi(c.Param("playerId")); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid playerId")
+		}
+		player, err := apifootball.WithContext(c.Request().Context()).GetPlayersByPlayerId(c.Param("playerId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		season, ok := players.SeasonOf(player)
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		return c.Render(http.StatusOK, "player.html", season)
+	})
+
+	e.GET("/healthz", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"status": health.StatusOk})
+	})
+
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+
+	e.GET("/readyz", func(c echo.Context) error {
+		report := checker.Ready()
+		if !report.Ok() {
+			return c.JSON(http.StatusServiceUnavailable, report)
+		}
+		return c.JSON(http.StatusOK, report)
+	})
+
 	e.GET("api/footballData/teams/:teamId", func(c echo.Context) error {
-		footballData := footballData.New(config.Config.FootballDataApiToken, config.Config.FootballDataBaseUrl)
+		footballData := footballData.New(config.Config.FootballDataApiToken, config.Config.FootballDataBaseUrl).WithContext(c.Request().Context())
 		resp, _ := footballData.DoRequest("teams", c.Param("teamId")) //inter = 108
 		return c.String(http.StatusOK, string(resp))
 	})
 
+	e.GET("/api/export/schema", func(c echo.Context) error {
+		// The columns of the CSV exports, by table.
+		schemaByteArray, _ := json.Marshal(export.Schemas)
+		return c.String(http.StatusOK, string(schemaByteArray))
+	})
+
 	e.GET("/api/apiFootball/status", func(c echo.Context) error {
-		status, err := apifootball.GetStatus()
+		status, err := apifootball.WithContext(c.Request().Context()).GetStatus()
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -69,7 +231,7 @@ func main() {
 	})
 
 	e.GET("/api/apiFootball/leagues", func(c echo.Context) error {
-		leagues, err := apifootball.GetLeagues()
+		leagues, err := apifootball.WithContext(c.Request().Context()).GetLeagues()
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -81,7 +243,7 @@ func main() {
 	})
 
 	e.GET("/api/apiFootball/league/:leagueId", func(c echo.Context) error {
-		league, err := apifootball.GetLeagueByLeagueId(c.Param("leagueId"))
+		league, err := apifootball.WithContext(c.Request().Context()).GetLeagueByLeagueId(c.Param("leagueId"))
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -94,72 +256,440 @@ func main() {
 
 	e.GET("/api/apiFootball/league/:leagueId/standings", func(c echo.Context) error {
 		leagueId := c.Param("leagueId")
-		standings, err := apifootball.GetStandingsByLeagueId(leagueId)
+		options, err := negotiateExport(c)
+		if err != nil {
+			return err
+		}
+		standings, err := apifootball.WithContext(c.Request().Context()).GetStandingsByLeagueId(leagueId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
 		if standings.Results == 0 {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
+		if options.CSV {
+			return writeExport(c, export.Standings(standings), leagueId, options)
+		}
 		standingsByteArray, _ := json.Marshal(standings)
 		return c.String(http.StatusOK, string(standingsByteArray))
 	})
 
+	e.GET("/api/leagues/:leagueId/standings", func(c echo.Context) error {
+		leagueId, err := strconv.Atoi(c.Param("leagueId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid leagueId")
+		}
+		rules := standings.RulesFor(leagueId)
+		if name := c.QueryParam("rules"); name != "" {
+			var ok bool
+			if rules, ok = standings.ParseRules(name); !ok {
+				return echo.NewHTTPError(http.StatusBadRequest, "unknown rules")
+			}
+		}
+		var filters []standings.Filter
+		if round := c.QueryParam("round"); round != "" {
+			n, err := strconv.Atoi(round)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid round")
+			}
+			filters = append(filters, standings.UpToRound(n))
+		}
+		if date := c.QueryParam("date"); date != "" {
+			asOf, err := time.Parse("2006-01-02", date)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid date")
+			}
+			filters = append(filters, standings.Until(asOf))
+		}
+		options, err := negotiateExport(c)
+		if err != nil {
+			return err
+		}
+
+		fixtures, err := apifootball.WithContext(c.Request().Context()).GetFixturesByLeagueId(c.Param("leagueId"))
+		if err != nil || fixtures.Results == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		table := standings.Compute(fixtures.Response, rules, filters...)
+		if options.CSV {
+			return writeExport(c, export.Computed(leagueId, table), c.Param("leagueId"), options)
+		}
+		tableByteArray, _ := json.Marshal(table)
+		return c.String(http.StatusOK, string(tableByteArray))
+	})
+
+	e.GET("/api/leagues/:leagueId/standings/history", func(c echo.Context) error {
+		leagueId, err := strconv.Atoi(c.Param("leagueId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid leagueId")
+		}
+		fixtures, err := apifootball.WithContext(c.Request().Context()).GetFixturesByLeagueId(c.Param("leagueId"))
+		if err != nil || fixtures.Results == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		history := snapshots.History(leagueId, fixtures.Response, standings.RulesFor(leagueId))
+		historyByteArray, _ := json.Marshal(history)
+		return c.String(http.StatusOK, string(historyByteArray))
+	})
+
+	e.GET("/api/leagues/:leagueId/simulation", func(c echo.Context) error {
+		opts := simulation.Options{Simulations: 10000}
+		if n := c.QueryParam("simulations"); n != "" {
+			simulations, err := strconv.Atoi(n)
+			if err != nil || simulations < 1 || simulations > 100000 {
+				return echo.NewHTTPError(http.StatusBadRequest, "simulations must be between 1 and 100000")
+			}
+			opts.Simulations = simulations
+		}
+		if seed := c.QueryParam("seed"); seed != "" {
+			n, err := strconv.ParseInt(seed, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid seed")
+			}
+			opts.Seed = n
+		}
+
+		ctx := c.Request().Context()
+		leagueStandings, err := apifootball.WithContext(ctx).GetStandingsByLeagueId(c.Param("leagueId"))
+		if err != nil || leagueStandings.Results == 0 || len(leagueStandings.Response[0].League.Standings) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		fixtures, err := apifootball.WithContext(ctx).GetFixturesByLeagueId(c.Param("leagueId"))
+		if err != nil || fixtures.Results == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		rows := leagueStandings.Response[0].League.Standings[0]
+		result, err := simulation.Simulate(ctx, rows, fixtures.Response, simulation.ModelFromStandings(rows), opts)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "simulation cancelled")
+		}
+		resultByteArray, _ := json.Marshal(result)
+		return c.String(http.StatusOK, string(resultByteArray))
+	})
+
+	e.GET("/api/ratings", func(c echo.Context) error {
+		leagueId := 0
+		if league := c.QueryParam("league"); league != "" {
+			var err error
+			if leagueId, err = strconv.Atoi(league); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid league")
+			}
+		}
+		ratingsUpdater.Update(c.Request().Context())
+		list := elo.Ratings(leagueId)
+		if len(list) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		ratingsByteArray, _ := json.Marshal(list)
+		return c.String(http.StatusOK, string(ratingsByteArray))
+	})
+
+	e.GET("/api/team/:teamId/rating/history", func(c echo.Context) error {
+		teamId, err := strconv.Atoi(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid teamId")
+		}
+		ratingsUpdater.Update(c.Request().Context())
+		history, ok := elo.History(teamId)
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		historyByteArray, _ := json.Marshal(history)
+		return c.String(http.StatusOK, string(historyByteArray))
+	})
+
+	e.GET("/api/team/:teamId/form", func(c echo.Context) error {
+		teamId, err := strconv.Atoi(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid teamId")
+		}
+		var windows []int
+		if query := c.QueryParam("windows"); query != "" {
+			for _, size := range strings.Split(query, ",") {
+				n, err := strconv.Atoi(strings.TrimSpace(size))
+				if err != nil || n < 1 || n > 100 {
+					return echo.NewHTTPError(http.StatusBadRequest, "windows must be sizes between 1 and 100")
+				}
+				windows = append(windows, n)
+			}
+		}
+		leagueId := 0
+		if league := c.QueryParam("league"); league != "" {
+			if leagueId, err = strconv.Atoi(league); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid league")
+			}
+		}
+		fixtures, err := apifootball.WithContext(c.Request().Context()).GetFixturesByTeamId(c.Param("teamId"))
+		if err != nil || fixtures.Results == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		report := form.Analyse(teamId, form.InLeague(fixtures.Response, leagueId), windows)
+		reportByteArray, _ := json.Marshal(report)
+		return c.String(http.StatusOK, string(reportByteArray))
+	})
+
+	e.GET("/api/team/:teamId/tactics", func(c echo.Context) error {
+		teamId, err := strconv.Atoi(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid teamId")
+		}
+		leagueId := 0
+		if league := c.QueryParam("league"); league != "" {
+			if leagueId, err = strconv.Atoi(league); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid league")
+			}
+		}
+		report, err := formations.Report(c.Request().Context(), apifootball, teamId, leagueId)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		reportByteArray, _ := json.Marshal(report)
+		return c.String(http.StatusOK, string(reportByteArray))
+	})
+
+	e.GET("/api/team/:teamId/fixtures.ics", func(c echo.Context) error {
+		teamId, err := strconv.Atoi(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid teamId")
+		}
+		fixtures, err := apifootball.WithContext(c.Request().Context()).GetFixturesByTeamId(c.Param("teamId"))
+		if err != nil || fixtures.Results == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		c.Response().Header().Set(echo.HeaderContentType, calendar.ContentType)
+		c.Response().WriteHeader(http.StatusOK)
+		return calendar.Write(c.Response(), calendar.TeamName(teamId, fixtures.Response), fixtures.Response, time.Now(), calendarRevisions)
+	})
+
+	e.GET("/api/team/:teamId/results.atom", func(c echo.Context) error {
+		teamId, err := strconv.Atoi(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid teamId")
+		}
+		fixtures, err := apifootball.WithContext(c.Request().Context()).GetFixturesByTeamId(c.Param("teamId"))
+		if err != nil || fixtures.Results == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		base := c.Scheme() + "://" + c.Request().Host
+		c.Response().Header().Set(echo.HeaderContentType, atom.ContentType)
+		c.Response().WriteHeader(http.StatusOK)
+		return atom.Write(c.Response(), atom.Results(teamId, fixtures.Response, base), base+c.Request().RequestURI, time.Now())
+	})
+
+	e.GET("/api/team/:teamId/transfers.atom", func(c echo.Context) error {
+		teamId, err := strconv.Atoi(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid teamId")
+		}
+		transfers, err := apifootball.WithContext(c.Request().Context()).GetTransfersByTeamId(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		base := c.Scheme() + "://" + c.Request().Host
+		c.Response().Header().Set(echo.HeaderContentType, atom.ContentType)
+		c.Response().WriteHeader(http.StatusOK)
+		return atom.Write(c.Response(), atom.Transfers(teamId, transfers, base), base+c.Request().RequestURI, time.Now())
+	})
+
+	e.GET("/api/team/:teamId/injuries.atom", func(c echo.Context) error {
+		teamId, err := strconv.Atoi(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid teamId")
+		}
+		client := apifootball.WithContext(c.Request().Context())
+		// The fixtures tell which absences are consecutive.
+		fixtures, err := client.GetFixturesByTeamId(c.Param("teamId"))
+		if err != nil || fixtures.Results == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		injuries, err := client.GetInjuriesByTeamId(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		base := c.Scheme() + "://" + c.Request().Host
+		c.Response().Header().Set(echo.HeaderContentType, atom.ContentType)
+		c.Response().WriteHeader(http.StatusOK)
+		return atom.Write(c.Response(), atom.Injuries(teamId, injuries, fixtures.Response, base), base+c.Request().RequestURI, time.Now())
+	})
+
+	e.GET("/api/league/:leagueId/fixtures.ics", func(c echo.Context) error {
+		if _, err := strconv.Atoi(c.Param("leagueId")); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid leagueId")
+		}
+		fixtures, err := apifootball.WithContext(c.Request().Context()).GetFixturesByLeagueId(c.Param("leagueId"))
+		if err != nil || fixtures.Results == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		c.Response().Header().Set(echo.HeaderContentType, calendar.ContentType)
+		c.Response().WriteHeader(http.StatusOK)
+		return calendar.Write(c.Response(), calendar.LeagueName(fixtures.Response), fixtures.Response, time.Now(), calendarRevisions)
+	})
+
+	e.GET("/api/leagues/:leagueId/timing", func(c echo.Context) error {
+		leagueId, err := strconv.Atoi(c.Param("leagueId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid leagueId")
+		}
+		league, err := timings.League(c.Request().Context(), apifootball, leagueId)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		leagueByteArray, _ := json.Marshal(league)
+		return c.String(http.StatusOK, string(leagueByteArray))
+	})
+
+	e.GET("/api/leagues/:leagueId/team/:teamId/timing", func(c echo.Context) error {
+		leagueId, err := strconv.Atoi(c.Param("leagueId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid leagueId")
+		}
+		teamId, err := strconv.Atoi(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid teamId")
+		}
+		league, err := timings.League(c.Request().Context(), apifootball, leagueId)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		comparison, ok := league.Compare(teamId)
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		comparisonByteArray, _ := json.Marshal(comparison)
+		return c.String(http.StatusOK, string(comparisonByteArray))
+	})
+
+	e.GET("/api/leagues/:leagueId/players", func(c echo.Context) error {
+		leagueId, err := strconv.Atoi(c.Param("leagueId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid leagueId")
+		}
+		options, err := negotiateExport(c)
+		if err != nil {
+			return err
+		}
+		league, err := profiles.League(c.Request().Context(), apifootball, leagueId)
+		if err != nil || len(league.Players) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		if options.CSV {
+			return writeExport(c, export.Profiles(league.Position(c.QueryParam("position"))), c.Param("leagueId"), options)
+		}
+		leagueByteArray, _ := json.Marshal(league.Position(c.QueryParam("position")))
+		return c.String(http.StatusOK, string(leagueByteArray))
+	})
+
+	e.GET("/api/players/compare", func(c echo.Context) error {
+		var ids []int
+		for _, id := range strings.Split(c.QueryParam("ids"), ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(id))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid ids")
+			}
+			ids = append(ids, n)
+		}
+		leagueId := 135
+		if league := c.QueryParam("league"); league != "" {
+			var err error
+			if leagueId, err = strconv.Atoi(league); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid league")
+			}
+		}
+		league, err := profiles.League(c.Request().Context(), apifootball, leagueId)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		comparison := league.Compare(ids)
+		if len(comparison.Players) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		comparisonByteArray, _ := json.Marshal(comparison)
+		return c.String(http.StatusOK, string(comparisonByteArray))
+	})
+
 	e.GET("/api/apiFootball/league/:leagueId/topscorers", func(c echo.Context) error {
 		leagueId := c.Param("leagueId")
-		topscorers, err := apifootball.GetTopscorersByLeagueId(leagueId)
+		options, err := negotiateExport(c)
+		if err != nil {
+			return err
+		}
+		topscorers, err := apifootball.WithContext(c.Request().Context()).GetTopscorersByLeagueId(leagueId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
 		if topscorers.Results == 0 {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
+		if options.CSV {
+			return writeExport(c, export.Leaders("topscorers", topscorers.Response), leagueId, options)
+		}
 		topscorersByteArray, _ := json.Marshal(topscorers)
 		return c.String(http.StatusOK, string(topscorersByteArray))
 	})
 
 	e.GET("/api/apiFootball/league/:leagueId/topassists", func(c echo.Context) error {
 		leagueId := c.Param("leagueId")
-		topassists, err := apifootball.GetTopassistsByLeagueId(leagueId)
+		options, err := negotiateExport(c)
+		if err != nil {
+			return err
+		}
+		topassists, err := apifootball.WithContext(c.Request().Context()).GetTopassistsByLeagueId(leagueId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
 		if topassists.Results == 0 {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
+		if options.CSV {
+			return writeExport(c, export.Leaders("topassists", topassists.Response), leagueId, options)
+		}
 		topassistsByteArray, _ := json.Marshal(topassists)
 		return c.String(http.StatusOK, string(topassistsByteArray))
 	})
 
 	e.GET("/api/apiFootball/league/:leagueId/topyellowcards", func(c echo.Context) error {
 		leagueId := c.Param("leagueId")
-		topyellowcards, err := apifootball.GetTopyellowcardsByLeagueId(leagueId)
+		options, err := negotiateExport(c)
+		if err != nil {
+			return err
+		}
+		topyellowcards, err := apifootball.WithContext(c.Request().Context()).GetTopyellowcardsByLeagueId(leagueId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
 		if topyellowcards.Results == 0 {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
+		if options.CSV {
+			return writeExport(c, export.Leaders("topyellowcards", topyellowcards.Response), leagueId, options)
+		}
 		topyellowcardsByteArray, _ := json.Marshal(topyellowcards)
 		return c.String(http.StatusOK, string(topyellowcardsByteArray))
 	})
 
 	e.GET("/api/apiFootball/league/:leagueId/topredcards", func(c echo.Context) error {
 		leagueId := c.Param("leagueId")
-		topredcards, err := apifootball.GetTopredcardsByLeagueId(leagueId)
+		options, err := negotiateExport(c)
+		if err != nil {
+			return err
+		}
+		topredcards, err := apifootball.WithContext(c.Request().Context()).GetTopredcardsByLeagueId(leagueId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
 		if topredcards.Results == 0 {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
+		if options.CSV {
+			return writeExport(c, export.Leaders("topredcards", topredcards.Response), leagueId, options)
+		}
 		topyellowcardsByteArray, _ := json.Marshal(topredcards)
 		return c.String(http.StatusOK, string(topyellowcardsByteArray))
 	})
 
 	e.GET("/api/apiFootball/league/:leagueId/teams", func(c echo.Context) error {
 		leagueId := c.Param("leagueId") //SerieA: 135, SerieB: 136
-		teams, err := apifootball.GetTeamsByLeagueId(leagueId)
+		teams, err := apifootball.WithContext(c.Request().Context()).GetTeamsByLeagueId(leagueId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -174,7 +704,7 @@ func main() {
 		leagueId := c.Param("leagueId") //SerieA: 135, SerieB: 136
 		teamId := c.Param("teamId")
 
-		teams, err := apifootball.GetTeamsByLeagueIdAndTeamId(leagueId, teamId)
+		teams, err := apifootball.WithContext(c.Request().Context()).GetTeamsByLeagueIdAndTeamId(leagueId, teamId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -188,7 +718,7 @@ func main() {
 	e.GET("/api/apiFootball/league/:leagueId/team/:teamId/statistics", func(c echo.Context) error {
 		leagueId := c.Param("leagueId")
 		teamId := c.Param("teamId") //inter: 505
-		statistics, err := apifootball.GetStatisticsByLeagueIdAndTeamId(leagueId, teamId)
+		statistics, err := apifootball.WithContext(c.Request().Context()).GetStatisticsByLeagueIdAndTeamId(leagueId, teamId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -202,13 +732,20 @@ func main() {
 	e.GET("/api/apiFootball/league/:leagueId/team/:teamId/players", func(c echo.Context) error {
 		leagueId := c.Param("leagueId")
 		teamId := c.Param("teamId")
-		players, err := apifootball.GetPlayersByLeagueIdAndTeamId(leagueId, teamId)
+		options, err := negotiateExport(c)
+		if err != nil {
+			return err
+		}
+		players, err := apifootball.WithContext(c.Request().Context()).GetPlayersByLeagueIdAndTeamId(leagueId, teamId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
 		if players.Results == 0 {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
+		if options.CSV {
+			return writeExport(c, export.Players(players.Response), leagueId+"-"+teamId, options)
+		}
 		playersByteArray, _ := json.Marshal(players)
 		return c.String(http.StatusOK, string(playersByteArray))
 	})
@@ -216,7 +753,7 @@ func main() {
 	e.GET("/api/apiFootball/league/:leagueId/team/:teamId/fixtures", func(c echo.Context) error {
 		leagueId := c.Param("leagueId")
 		teamId := c.Param("teamId")
-		fixtures, err := apifootball.GetFixturesByLeagueIdAndTeamId(leagueId, teamId)
+		fixtures, err := apifootball.WithContext(c.Request().Context()).GetFixturesByLeagueIdAndTeamId(leagueId, teamId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -229,7 +766,7 @@ func main() {
 
 	e.GET("/api/apiFootball/league/:leagueId/team/:teamId/fixture/:fixtureId", func(c echo.Context) error {
 		fixtureId := c.Param("fixtureId")
-		fixtures, err := apifootball.GetFixtureByFixtureId(fixtureId)
+		fixtures, err := apifootball.WithContext(c.Request().Context()).GetFixtureByFixtureId(fixtureId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -245,7 +782,7 @@ func main() {
 		teamId := c.Param("teamId")
 		fixtureId := c.Param("fixtureId")
 
-		injuries, err := apifootball.GetInjuriesByLeagueIdAndTeamIdAndFixtureId(leagueId, teamId, fixtureId)
+		injuries, err := apifootball.WithContext(c.Request().Context()).GetInjuriesByLeagueIdAndTeamIdAndFixtureId(leagueId, teamId, fixtureId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -260,7 +797,7 @@ func main() {
 		teamId := c.Param("teamId")
 		fixtureId := c.Param("fixtureId")
 
-		fixturesStatistics, err := apifootball.GetStatisticsByTeamIdAndFixtureId(teamId, fixtureId)
+		fixturesStatistics, err := apifootball.WithContext(c.Request().Context()).GetStatisticsByTeamIdAndFixtureId(teamId, fixtureId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -275,7 +812,7 @@ func main() {
 		teamId := c.Param("teamId")
 		fixtureId := c.Param("fixtureId") //731698
 
-		events, err := apifootball.GetEventsByTeamIdAndFixtureId(teamId, fixtureId)
+		events, err := apifootball.WithContext(c.Request().Context()).GetEventsByTeamIdAndFixtureId(teamId, fixtureId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -290,7 +827,7 @@ func main() {
 		teamId := c.Param("teamId")
 		fixtureId := c.Param("fixtureId") //731698
 
-		lineups, err := apifootball.GetLineupsByTeamIdAndFixtureId(teamId, fixtureId)
+		lineups, err := apifootball.WithContext(c.Request().Context()).GetLineupsByTeamIdAndFixtureId(teamId, fixtureId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -305,7 +842,7 @@ func main() {
 		teamId := c.Param("teamId")
 		fixtureId := c.Param("fixtureId") //731698
 
-		fixturesPlayers, err := apifootball.GetPlayersByTeamIdAndFixtureId(teamId, fixtureId)
+		fixturesPlayers, err := apifootball.WithContext(c.Request().Context()).GetPlayersByTeamIdAndFixtureId(teamId, fixtureId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -318,7 +855,7 @@ func main() {
 
 	e.GET("/api/apiFootball/team/:teamId/coachs", func(c echo.Context) error {
 		teamId := c.Param("teamId")
-		coachs, err := apifootball.GetCoachsByTeamId(teamId)
+		coachs, err := apifootball.WithContext(c.Request().Context()).GetCoachsByTeamId(teamId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -331,13 +868,20 @@ func main() {
 
 	e.GET("/api/apiFootball/team/:teamId/squads", func(c echo.Context) error {
 		teamId := c.Param("teamId")
-		squads, err := apifootball.GetSquadsByTeamId(teamId)
+		options, err := negotiateExport(c)
+		if err != nil {
+			return err
+		}
+		squads, err := apifootball.WithContext(c.Request().Context()).GetSquadsByTeamId(teamId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
 		if squads.Results == 0 {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
+		if options.CSV {
+			return writeExport(c, export.Squads(squads), teamId, options)
+		}
 		squadsByteArray, _ := json.Marshal(squads)
 		return c.String(http.StatusOK, string(squadsByteArray))
 	})
@@ -345,12 +889,39 @@ func main() {
 	e.GET("/api/apiFootball/league/:leagueId/fixtures/headtohead/:h2h", func(c echo.Context) error {
 		leagueId := c.Param("leagueId")
 		h2hId := c.Param("h2h")
-		resp, _ := apifootball.GetHeadtoheadByLeagueIdAndH2hId(leagueId, h2hId)
+		resp, _ := apifootball.WithContext(c.Request().Context()).GetHeadtoheadByLeagueIdAndH2hId(leagueId, h2hId)
 		return c.String(http.StatusOK, string(resp))
 	})
 
+	e.GET("/api/headtohead/:teamId/:opponentId", func(c echo.Context) error {
+		teamId, err := strconv.Atoi(c.Param("teamId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid teamId")
+		}
+		opponentId, err := strconv.Atoi(c.Param("opponentId"))
+		if err != nil || opponentId == teamId {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid opponentId")
+		}
+		recent := headtohead.DefaultRecent
+		if query := c.QueryParam("recent"); query != "" {
+			if recent, err = strconv.Atoi(query); err != nil || recent < 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid recent")
+			}
+		}
+		fixtures, err := apifootball.WithContext(c.Request().Context()).GetHeadtoheadByTeamIds(c.Param("teamId"), c.Param("opponentId"))
+		if err != nil || fixtures.Results == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		summary, ok := headtohead.Summarise(teamId, opponentId, fixtures.Response, recent)
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		summaryByteArray, _ := json.Marshal(summary)
+		return c.String(http.StatusOK, string(summaryByteArray))
+	})
+
 	e.GET("/api/apiFootball/venues", func(c echo.Context) error {
-		venues, err := apifootball.GetVenues()
+		venues, err := apifootball.WithContext(c.Request().Context()).GetVenues()
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -363,7 +934,7 @@ func main() {
 
 	e.GET("/api/apiFootball/venue/:venueId", func(c echo.Context) error {
 		venueId := c.Param("venueId") //Stadio Giuseppe Meazza: 907
-		venues, err := apifootball.GetVenueByVenueId(venueId)
+		venues, err := apifootball.WithContext(c.Request().Context()).GetVenueByVenueId(venueId)
 
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
@@ -377,7 +948,7 @@ func main() {
 
 	e.GET("/api/apiFootball/predictions/:fixtureId", func(c echo.Context) error {
 		fixtureId := c.Param("fixtureId")
-		predictions, err := apifootball.GetPredictionsByFixtureId(fixtureId)
+		predictions, err := apifootball.WithContext(c.Request().Context()).GetPredictionsByFixtureId(fixtureId)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
@@ -388,23 +959,152 @@ func main() {
 		return c.String(http.StatusOK, string(predictionsByteArray))
 	})
 
+	e.GET("/api/fixture/:fixtureId/prediction", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		fixtures, err := apifootball.WithContext(ctx).GetFixtureByFixtureId(c.Param("fixtureId"))
+		if err != nil || fixtures.Results == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		fixture := fixtures.Response[0]
+		leagueFixtures, err := apifootball.WithContext(ctx).GetFixturesByLeagueId(strconv.Itoa(fixture.League.ID))
+		if err != nil || leagueFixtures.Results == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		// The provider's prediction is optional: without it the model is shown alone.
+		predictions, _ := apifootball.WithContext(ctx).GetPredictionsByFixtureId(c.Param("fixtureId"))
+		model := outcome.Fit(leagueFixtures.Response, fixture.Fixture.Date, outcome.Options{})
+		comparison := outcome.Compare(fixture, model, predictions)
+		comparisonByteArray, _ := json.Marshal(comparison)
+		return c.String(http.StatusOK, string(comparisonByteArray))
+	})
+
+	e.GET("/api/fixture/:fixtureId/report", func(c echo.Context) error {
+		fixtureId, err := strconv.Atoi(c.Param("fixtureId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid fixtureId")
+		}
+		format, ok := report.Negotiate(c.QueryParam("format"), c.Request().Header.Get(echo.HeaderAccept))
+		if !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid format")
+		}
+		parts, err := report.Fetch(c.Request().Context(), apifootball, fixtureId)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		matchReport := report.Build(parts)
+		// Rendered before the status is written, so that a template error
+		// is a 500 rather than a truncated page.
+		var body bytes.Buffer
+		switch format {
+		case "markdown":
+			err = report.Markdown(&body, matchReport)
+		case "html":
+			err = report.HTML(&body, matchReport)
+		default:
+			err = json.NewEncoder(&body).Encode(matchReport)
+		}
+		if err != nil {
+			return err
+		}
+		return c.Blob(http.StatusOK, report.Formats[format], body.Bytes())
+	})
+
+	e.GET("/api/fixture/:fixtureId/lineups.svg", func(c echo.Context) error {
+		if _, err := strconv.Atoi(c.Param("fixtureId")); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid fixtureId")
+		}
+		home, away, err := pitch.Fetch(c.Request().Context(), apifootball, c.Param("fixtureId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		c.Response().Header().Set(echo.HeaderContentType, "image/svg+xml")
+		c.Response().WriteHeader(http.StatusOK)
+		return pitch.SVG(c.Response(), home, away)
+	})
+
+	e.GET("/api/leagues/:leagueId/model", func(c echo.Context) error {
+		asOf := time.Now()
+		if date := c.QueryParam("date"); date != "" {
+			var err error
+			if asOf, err = time.Parse("2006-01-02", date); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid date")
+			}
+		}
+		fixtures, err := apifootball.WithContext(c.Request().Context()).GetFixturesByLeagueId(c.Param("leagueId"))
+		if err != nil || fixtures.Results == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		model := outcome.Fit(fixtures.Response, asOf, outcome.Options{})
+		modelByteArray, _ := json.Marshal(model)
+		return c.String(http.StatusOK, string(modelByteArray))
+	})
+
+	e.GET("/api/backtest", func(c echo.Context) error {
+		var filter backtest.Filter
+		for param, value := range map[string]*int{"league": &filter.LeagueID, "season": &filter.Season} {
+			if query := c.QueryParam(param); query != "" {
+				n, err := strconv.Atoi(query)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest, "invalid "+param)
+				}
+				*value = n
+			}
+		}
+		filter.Source = c.QueryParam("source")
+		// cmd/backtest captures the predictions; read what it saved so far.
+		predictionStore, err := backtest.Open(config.Config.BacktestFile)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "captured predictions not loaded")
+		}
+		report, err := backtest.Run(c.Request().Context(), apifootball, predictionStore, filter)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		}
+		reportByteArray, _ := json.Marshal(report)
+		return c.String(http.StatusOK, string(reportByteArray))
+	})
+
 	e.GET("/api/apiFootball/player/:playerId", func(c echo.Context) error {
 		playerId := c.Param("playerId") // M. Škriniar: 198
-		players, err := apifootball.GetPlayersByPlayerId(playerId)
+		options, err := negotiateExport(c)
+		if err != nil {
+			return err
+		}
+		player, err := apifootball.WithContext(c.Request().Context()).GetPlayersByPlayerId(playerId)
 
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
-		if players.Results == 0 {
+		if player.Results == 0 {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
 		}
-		playersByteArray, _ := json.Marshal(players)
-		return c.String(http.StatusOK, string(playersByteArray))
+		switch c.QueryParam("view") {
+		case "":
+			if options.CSV {
+				return writeExport(c, export.Players(player.Response), playerId, options)
+			}
+		case "season":
+			// Per competition and combined over all of them; there is no
+			// CSV of it.
+			if options.CSV {
+				return echo.NewHTTPError(http.StatusBadRequest, "no csv of the season view")
+			}
+			season, ok := players.SeasonOf(player)
+			if !ok {
+				return echo.NewHTTPError(http.StatusNotFound, "not found")
+			}
+			seasonByteArray, _ := json.Marshal(season)
+			return c.String(http.StatusOK, string(seasonByteArray))
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid view")
+		}
+		playerByteArray, _ := json.Marshal(player)
+		return c.String(http.StatusOK, string(playerByteArray))
 	})
 
 	e.GET("/api/apiFootball/player/:playerId/transfers", func(c echo.Context) error {
 		playerId := c.Param("playerId")
-		transfers, err := apifootball.GetTransfersByPlayerId(playerId)
+		transfers, err := apifootball.WithContext(c.Request().Context()).GetTransfersByPlayerId(playerId)
 
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
@@ -418,7 +1118,7 @@ func main() {
 
 	e.GET("/api/apiFootball/player/:playerId/trophies", func(c echo.Context) error {
 		playerId := c.Param("playerId")
-		trophies, err := apifootball.GetTrophiesByPlayerId(playerId)
+		trophies, err := apifootball.WithContext(c.Request().Context()).GetTrophiesByPlayerId(playerId)
 
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "not found")
@@ -433,9 +1133,9 @@ func main() {
 	e.GET("/api/apiFootball/player/:playerId/sidelined", func(c echo.Context) error {
 		playerId := c.Param("playerId")
 
-		resp, _ := apifootball.GetSidelinedByPlayerId(playerId)
+		resp, _ := apifootball.WithContext(c.Request().Context()).GetSidelinedByPlayerId(playerId)
 		return c.String(http.StatusOK, string(resp))
 	})
 
-	e.Logger.Fatal(e.Start(":8080"))
+	logging.Logger.Fatal(e.Start(":8080"))
 }
@@ -0,0 +1,89 @@
+// Package media picks the representation of a response from the Accept
+// header of the request.
+package media
+
+import (
+	"strconv"
+	"strings"
+)
+
+// accepted is a media range of an Accept header, such as text/* or
+// application/json;q=0.9.
+type accepted struct {
+	mediaType string
+	subtype   string
+	q         float64
+}
+
+func parse(accept string) []accepted {
+	var ranges []accepted
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+		slash := strings.Index(mediaRange, "/")
+		if slash < 0 {
+			continue
+		}
+		r := accepted{mediaRange[:slash], mediaRange[slash+1:], 1}
+		for _, param := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) != 2 || strings.ToLower(kv[0]) != "q" {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+			if err != nil || q < 0 || q > 1 {
+				q = 0
+			}
+			r.q = q
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// specificity is how closely a range matches a media type: 3 for the type
+// itself, 2 for type/*, 1 for */* and 0 for none.
+func (r accepted) specificity(mediaType string, subtype string) int {
+	switch {
+	case r.mediaType == mediaType && r.subtype == subtype:
+		return 3
+	case r.mediaType == mediaType && r.subtype == "*":
+		return 2
+	case r.mediaType == "*" && r.subtype == "*":
+		return 1
+	}
+	return 0
+}
+
+// Negotiate returns the offer, a media type such as text/csv, the Accept
+// header prefers. Each offer takes the quality of its most specific range,
+// so text/csv;q=0 refuses CSV even with */*. Ties go to the range listed
+// first, then to the first offer. It returns the first offer when accept is
+// empty and "" when no offer is acceptable.
+func Negotiate(accept string, offers ...string) string {
+	if strings.TrimSpace(accept) == "" {
+		if len(offers) == 0 {
+			return ""
+		}
+		return offers[0]
+	}
+	ranges := parse(accept)
+	best, bestQ, bestIndex := "", 0.0, len(ranges)
+	for _, offer := range offers {
+		slash := strings.Index(offer, "/")
+		if slash < 0 {
+			continue
+		}
+		mediaType, subtype := strings.ToLower(offer[:slash]), strings.ToLower(offer[slash+1:])
+		q, index, specificity := 0.0, len(ranges), 0
+		for i, r := range ranges {
+			if s := r.specificity(mediaType, subtype); s > specificity {
+				q, index, specificity = r.q, i, s
+			}
+		}
+		if q > bestQ || (q == bestQ && q > 0 && index < bestIndex) {
+			best, bestQ, bestIndex = offer, q, index
+		}
+	}
+	return best
+}
@@ -0,0 +1,27 @@
+package media
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json", "text/csv", "text/html"}
+	for accept, want := range map[string]string{
+		"":                                 "application/json",
+		"text/csv":                         "text/csv",
+		"TEXT/CSV; charset=utf-8":          "text/csv",
+		"text/html, text/csv":              "text/html",
+		"text/csv;q=0.9, application/json": "application/json",
+		"application/json;q=0.5, text/*":   "text/csv",
+		"*/*":                              "application/json",
+		"*/*;q=0.8, text/html":             "text/html",
+		"*/*, application/json;q=0":        "text/csv",
+		"text/*;q=0.5, text/csv;q=0":       "text/html",
+		"image/png":                        "",
+		"application/json;q=0, image/png":  "",
+		"text/csv;q=abc, application/json": "application/json",
+		"text/html,application/xhtml+xml;q=0.9,*/*;q=0.8": "text/html",
+	} {
+		if got := Negotiate(accept, offers...); got != want {
+			t.Errorf("Negotiate(%q) = %q, want %q", accept, got, want)
+		}
+	}
+}
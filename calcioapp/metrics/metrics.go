@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "calcio"
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by route.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	upstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_requests_total",
+		Help:      "Requests sent to the football data providers, by endpoint and status code.",
+	}, []string{"provider", "endpoint", "status"})
+
+	upstreamErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_errors_total",
+		Help:      "Provider requests that failed or did not return 200.",
+	}, []string{"provider", "endpoint"})
+
+	upstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Provider request latency, by endpoint.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"provider", "endpoint"})
+
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Cache lookups, by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	schemaDrift = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "schema_drift_total",
+		Help:      "Provider payloads that differ from the decoded structs, by endpoint and kind (unknown_field or type_mismatch).",
+	}, []string{"provider", "endpoint", "kind"})
+
+	apiFootballRequestsUsed = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "apifootball_requests_used",
+		Help:      "API-Football requests used today.",
+	})
+
+	apiFootballRequestsLimit = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "apifootball_requests_limit_day",
+		Help:      "API-Football daily request limit of the subscription.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		httpRequests,
+		httpDuration,
+		upstreamRequests,
+		upstreamErrors,
+		upstreamDuration,
+		cacheRequests,
+		schemaDrift,
+		apiFootballRequestsUsed,
+		apiFootballRequestsLimit,
+	)
+}
+
+// Handler serves the registered metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Middleware records request count and latency labelled with the route
+// pattern (e.g. /api/apiFootball/league/:leagueId) rather than the raw uri.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			route := c.Path()
+			if route == "" || err == echo.ErrNotFound || err == echo.ErrMethodNotAllowed {
+				route = "unmatched"
+			}
+			status := c.Response().Status
+			if err != nil {
+				status = http.StatusInternalServerError
+				if he, ok := err.(*echo.HTTPError); ok {
+					status = he.Code
+				}
+			}
+			method := c.Request().Method
+			httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+			httpDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+			return err
+		}
+	}
+}
+
+// ObserveUpstream records one provider request. status is the http status
+// code, or 0 when no response was received.
+func ObserveUpstream(provider string, endpoint string, status int, duration time.Duration) {
+	label := "error"
+	if status != 0 {
+		label = strconv.Itoa(status)
+	}
+	upstreamRequests.WithLabelValues(provider, endpoint, label).Inc()
+	upstreamDuration.WithLabelValues(provider, endpoint).Observe(duration.Seconds())
+	if status != http.StatusOK {
+		upstreamErrors.WithLabelValues(provider, endpoint).Inc()
+	}
+}
+
+func CacheHit(cache string) {
+	cacheRequests.WithLabelValues(cache, "hit").Inc()
+}
+
+func CacheMiss(cache string) {
+	cacheRequests.WithLabelValues(cache, "miss").Inc()
+}
+
+// SchemaDrift counts one difference between a provider payload and the
+// struct it was decoded into.
+func SchemaDrift(provider string, endpoint string, kind string) {
+	schemaDrift.WithLabelValues(provider, endpoint, kind).Inc()
+}
+
+// SetApiFootballQuota publishes how much of the daily API-Football quota is used.
+func SetApiFootballQuota(used int, limitDay int) {
+	apiFootballRequestsUsed.Set(float64(used))
+	apiFootballRequestsLimit.Set(float64(limitDay))
+}
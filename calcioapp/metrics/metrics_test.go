@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// delta runs f and returns how much it added to a counter.
+func delta(c prometheus.Collector, f func()) float64 {
+	before := testutil.ToFloat64(c)
+	f()
+	return testutil.ToFloat64(c) - before
+}
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/api/team/:teamId", func(c echo.Context) error {
+		return c.String(http.StatusOK, "{}")
+	})
+	e.GET("/api/team/:teamId/fixtures", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "quota exhausted")
+	})
+	e.GET("/api/team/:teamId/squad", func(c echo.Context) error {
+		return errors.New("decode")
+	})
+
+	for _, c := range []struct {
+		method, target, route, status string
+	}{
+		{http.MethodGet, "/api/team/505", "/api/team/:teamId", "200"},
+		{http.MethodGet, "/api/team/505/fixtures", "/api/team/:teamId/fixtures", "503"},
+		{http.MethodGet, "/api/team/505/squad", "/api/team/:teamId/squad", "500"},
+		{http.MethodGet, "/api/nowhere", "unmatched", "404"},
+		{http.MethodPost, "/api/team/505", "unmatched", "405"},
+	} {
+		requests := httpRequests.WithLabelValues(c.method, c.route, c.status)
+		added := delta(requests, func() {
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(c.method, c.target, nil))
+		})
+		if added != 1 {
+			t.Errorf("%s %s: %v requests counted as %s %s", c.method, c.target, added, c.route, c.status)
+		}
+	}
+	if n := testutil.CollectAndCount(httpDuration); n == 0 {
+		t.Error("no request latency observed")
+	}
+}
+
+func TestObserveUpstream(t *testing.T) {
+	for _, c := range []struct {
+		status int
+		label  string
+		errors float64
+	}{
+		{http.StatusOK, "200", 0},
+		{http.StatusTooManyRequests, "429", 1},
+		{0, "error", 1},
+	} {
+		requests := upstreamRequests.WithLabelValues("test", "fixtures", c.label)
+		failures := upstreamErrors.WithLabelValues("test", "fixtures")
+		var added float64
+		failed := delta(failures, func() {
+			added = delta(requests, func() { ObserveUpstream("test", "fixtures", c.status, 120*time.Millisecond) })
+		})
+		if added != 1 || failed != c.errors {
+			t.Errorf("status %d: %v requests as %s, %v errors", c.status, added, c.label, failed)
+		}
+	}
+}
+
+func TestSetApiFootballQuota(t *testing.T) {
+	SetApiFootballQuota(30, 100)
+	if used, limit := testutil.ToFloat64(apiFootballRequestsUsed), testutil.ToFloat64(apiFootballRequestsLimit); used != 30 || limit != 100 {
+		t.Errorf("quota %v of %v", used, limit)
+	}
+}
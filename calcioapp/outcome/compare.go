@@ -0,0 +1,146 @@
+package outcome
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+type Team struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+type FixtureSummary struct {
+	ID     int                `json:"id"`
+	Date   time.Time          `json:"date"`
+	Status string             `json:"status"`
+	Home   Team               `json:"home"`
+	Away   Team               `json:"away"`
+	Goals  apifootball.Score  `json:"goals"`
+	League apifootball.League `json:"league"`
+}
+
+// ProviderPrediction is the part of the provider's prediction that can be
+// compared with the model.
+type ProviderPrediction struct {
+	Outcome   Probabilities `json:"outcome"`
+	Winner    *Team         `json:"winner"`
+	WinOrDraw bool          `json:"winOrDraw"`
+	UnderOver string        `json:"underOver"`
+	Advice    string        `json:"advice"`
+}
+
+// Agreement is how the two predictions relate. Advice and UnderOver are the
+// model's probabilities that the provider's picks come true.
+type Agreement struct {
+	ModelFavourite    string   `json:"modelFavourite"`
+	ProviderFavourite string   `json:"providerFavourite"`
+	SameFavourite     bool     `json:"sameFavourite"`
+	Advice            *float64 `json:"advice"`
+	UnderOver         *float64 `json:"underOver"`
+}
+
+type Comparison struct {
+	Fixture  FixtureSummary      `json:"fixture"`
+	Model    Prediction          `json:"model"`
+	Provider *ProviderPrediction `json:"provider"`
+	// Difference is the model's outcome minus the provider's.
+	Difference *Probabilities `json:"difference"`
+	Agreement  *Agreement     `json:"agreement"`
+}
+
+// Compare predicts fixture with model and sets the prediction beside the
+// provider's. Provider fields are null when predictions has no response.
+func Compare(fixture apifootball.Fixture, model Model, predictions apifootball.Predictions) Comparison {
+	home := Team{fixture.Teams.Home.ID, fixture.Teams.Home.Name, fixture.Teams.Home.Logo}
+	away := Team{fixture.Teams.Away.ID, fixture.Teams.Away.Name, fixture.Teams.Away.Logo}
+	comparison := Comparison{
+		Fixture: FixtureSummary{
+			ID:     fixture.Fixture.ID,
+			Date:   fixture.Fixture.Date,
+			Status: fixture.Fixture.Status.Short,
+			Home:   home,
+			Away:   away,
+			Goals:  fixture.Goals,
+			League: fixture.League,
+		},
+		Model: model.Predict(home.ID, away.ID),
+	}
+	if len(predictions.Response) == 0 {
+		return comparison
+	}
+
+	theirs := predictions.Response[0].Predictions
+	provider := &ProviderPrediction{
+		Outcome:   Probabilities{theirs.Percent.Home.Ratio(), theirs.Percent.Draw.Ratio(), theirs.Percent.Away.Ratio()},
+		WinOrDraw: theirs.WinOrDraw,
+		UnderOver: theirs.UnderOver.String,
+		Advice:    theirs.Advice,
+	}
+	if theirs.Winner.ID.Valid {
+		provider.Winner = &Team{ID: theirs.Winner.ID.Int, Name: theirs.Winner.Name.String}
+		switch provider.Winner.ID {
+		case home.ID:
+			provider.Winner.Logo = home.Logo
+		case away.ID:
+			provider.Winner.Logo = away.Logo
+		}
+	}
+	comparison.Provider = provider
+
+	ours := comparison.Model.Outcome
+	comparison.Difference = &Probabilities{ours.Home - provider.Outcome.Home, ours.Draw - provider.Outcome.Draw, ours.Away - provider.Outcome.Away}
+
+	agreement := &Agreement{ModelFavourite: favourite(ours), ProviderFavourite: favourite(provider.Outcome)}
+	agreement.SameFavourite = agreement.ModelFavourite == agreement.ProviderFavourite
+	if provider.Winner != nil {
+		var p float64
+		switch provider.Winner.ID {
+		case home.ID:
+			p = ours.Home
+		case away.ID:
+			p = ours.Away
+		}
+		if provider.WinOrDraw {
+			p += ours.Draw
+		}
+		agreement.Advice = &p
+	}
+	if line, over, ok := parseUnderOver(provider.UnderOver); ok {
+		p := comparison.Model.Over(line)
+		if !over {
+			p = 1 - p
+		}
+		agreement.UnderOver = &p
+	}
+	comparison.Agreement = agreement
+	return comparison
+}
+
+func favourite(p Probabilities) string {
+	switch {
+	case p.Home >= p.Draw && p.Home >= p.Away:
+		return "home"
+	case p.Away >= p.Draw:
+		return "away"
+	}
+	return "draw"
+}
+
+// parseUnderOver reads the provider's pick such as "+2.5" (over 2.5 goals)
+// or "-3.5" (under 3.5 goals).
+func parseUnderOver(pick string) (line float64, over bool, ok bool) {
+	pick = strings.TrimSpace(pick)
+	if len(pick) < 2 || (pick[0] != '+' && pick[0] != '-') {
+		return 0, false, false
+	}
+	line, err := strconv.ParseFloat(pick[1:], 64)
+	if err != nil {
+		return 0, false, false
+	}
+	return line, pick[0] == '+', true
+}
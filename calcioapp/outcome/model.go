@@ -0,0 +1,209 @@
+// Package outcome is an in-house match model: a Poisson model of goals with
+// the Dixon-Coles correction for low scores, fitted from past fixtures and
+// compared with the provider's predictions.
+package outcome
+
+import (
+	"math"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+type Options struct {
+	// HalfLife is the age at which a fixture counts half. Defaults to 180 days.
+	HalfLife time.Duration
+	// Prior is the weight of an average match added to every team, keeping
+	// teams with few fixtures close to the average. Defaults to 1.
+	Prior float64
+	// MaxGoals bounds the score matrix. Defaults to 10.
+	MaxGoals int
+}
+
+func (o Options) withDefaults() Options {
+	if o.HalfLife <= 0 {
+		o.HalfLife = 180 * 24 * time.Hour
+	}
+	if o.Prior <= 0 {
+		o.Prior = 1
+	}
+	if o.MaxGoals <= 0 {
+		o.MaxGoals = 10
+	}
+	return o
+}
+
+// Strength is a team's attack and defence, 1 being the average. A higher
+// Defence concedes more.
+type Strength struct {
+	Attack  float64 `json:"attack"`
+	Defence float64 `json:"defence"`
+}
+
+// Model gives the expected goals of a fixture as
+// HomeGoals * attack(home) * defence(away) for the home team and
+// AwayGoals * attack(away) * defence(home) for the away team.
+type Model struct {
+	AsOf     time.Time `json:"asOf"`
+	Fixtures int       `json:"fixtures"`
+	// HomeGoals and AwayGoals are the goals of average teams.
+	HomeGoals float64 `json:"homeGoals"`
+	AwayGoals float64 `json:"awayGoals"`
+	// Rho is the Dixon-Coles dependence of low scores; negative values make
+	// 0-0 and 1-1 more likely than independent Poisson goals.
+	Rho   float64          `json:"rho"`
+	Teams map[int]Strength `json:"teams"`
+
+	maxGoals int
+}
+
+type observation struct {
+	home, away           int
+	homeGoals, awayGoals int
+	weight               float64
+}
+
+const iterations = 200
+
+// Fit fits a model to the fixtures finished before asOf, weighting each by
+// its age. Leaving later fixtures out lets the model be evaluated on them.
+func Fit(fixtures []apifootball.Fixture, asOf time.Time, opts Options) Model {
+	opts = opts.withDefaults()
+	model := Model{AsOf: asOf, HomeGoals: 1, AwayGoals: 1, Teams: map[int]Strength{}, maxGoals: opts.MaxGoals}
+
+	var obs []observation
+	var sumWeight, sumGoals float64
+	for _, fixture := range fixtures {
+		if !standings.Finished(fixture) || !fixture.Fixture.Date.Before(asOf) {
+			continue
+		}
+		age := asOf.Sub(fixture.Fixture.Date)
+		o := observation{
+			home: fixture.Teams.Home.ID, away: fixture.Teams.Away.ID,
+			homeGoals: fixture.Goals.Home.Int, awayGoals: fixture.Goals.Away.Int,
+			weight: math.Pow(0.5, float64(age)/float64(opts.HalfLife)),
+		}
+		obs = append(obs, o)
+		sumWeight += o.weight
+		sumGoals += o.weight * float64(o.homeGoals+o.awayGoals)
+		model.Teams[o.home] = Strength{1, 1}
+		model.Teams[o.away] = Strength{1, 1}
+	}
+	model.Fixtures = len(obs)
+	// Without goals, say after a goalless first matchday, there is nothing
+	// to rate the teams by and every attack would be 0: the prior stands.
+	if len(obs) == 0 || sumGoals == 0 {
+		return model
+	}
+	// The prior is a match against an average team, at home and away, with
+	// the average number of goals scored and conceded.
+	average := sumGoals / sumWeight / 2
+
+	for i := 0; i < iterations; i++ {
+		// Attack and defence are kept at mean 1, so an average team scores
+		// HomeGoals at home and AwayGoals away.
+		venue := (model.HomeGoals + model.AwayGoals) / 2
+
+		scored, conceded := map[int]float64{}, map[int]float64{}
+		attackExposure, defenceExposure := map[int]float64{}, map[int]float64{}
+		for id := range model.Teams {
+			scored[id], conceded[id] = opts.Prior*average, opts.Prior*average
+			attackExposure[id] = opts.Prior * venue
+			defenceExposure[id] = opts.Prior * venue
+		}
+		for _, o := range obs {
+			home, away := model.Teams[o.home], model.Teams[o.away]
+			scored[o.home] += o.weight * float64(o.homeGoals)
+			scored[o.away] += o.weight * float64(o.awayGoals)
+			conceded[o.home] += o.weight * float64(o.awayGoals)
+			conceded[o.away] += o.weight * float64(o.homeGoals)
+			attackExposure[o.home] += o.weight * model.HomeGoals * away.Defence
+			attackExposure[o.away] += o.weight * model.AwayGoals * home.Defence
+			defenceExposure[o.home] += o.weight * model.AwayGoals * away.Attack
+			defenceExposure[o.away] += o.weight * model.HomeGoals * home.Attack
+		}
+
+		next := map[int]Strength{}
+		var sumAttack, sumDefence float64
+		for id := range model.Teams {
+			s := Strength{scored[id] / attackExposure[id], conceded[id] / defenceExposure[id]}
+			next[id] = s
+			sumAttack += s.Attack
+			sumDefence += s.Defence
+		}
+		meanAttack, meanDefence := sumAttack/float64(len(next)), sumDefence/float64(len(next))
+		for id, s := range next {
+			next[id] = Strength{s.Attack / meanAttack, s.Defence / meanDefence}
+		}
+		model.Teams = next
+
+		// The level of scoring goes to the base rates, fitted to the
+		// rescaled strengths.
+		var homeGoals, homeExposure, awayGoals, awayExposure float64
+		for _, o := range obs {
+			home, away := model.Teams[o.home], model.Teams[o.away]
+			homeGoals += o.weight * float64(o.homeGoals)
+			homeExposure += o.weight * home.Attack * away.Defence
+			awayGoals += o.weight * float64(o.awayGoals)
+			awayExposure += o.weight * away.Attack * home.Defence
+		}
+		model.HomeGoals = homeGoals / homeExposure
+		model.AwayGoals = awayGoals / awayExposure
+	}
+
+	model.Rho = fitRho(model, obs)
+	return model
+}
+
+// Expected returns the expected goals of each side. Teams without fixtures
+// are average.
+func (m Model) Expected(homeId int, awayId int) (float64, float64) {
+	home, ok := m.Teams[homeId]
+	if !ok {
+		home = Strength{1, 1}
+	}
+	away, ok := m.Teams[awayId]
+	if !ok {
+		away = Strength{1, 1}
+	}
+	return m.HomeGoals * home.Attack * away.Defence, m.AwayGoals * away.Attack * home.Defence
+}
+
+// tau is the Dixon-Coles correction of the probability of a score.
+func tau(home int, away int, homeMean float64, awayMean float64, rho float64) float64 {
+	switch {
+	case home == 0 && away == 0:
+		return 1 - homeMean*awayMean*rho
+	case home == 0 && away == 1:
+		return 1 + homeMean*rho
+	case home == 1 && away == 0:
+		return 1 + awayMean*rho
+	case home == 1 && away == 1:
+		return 1 - rho
+	}
+	return 1
+}
+
+// fitRho maximises the likelihood of the observed low scores over rho,
+// holding the fitted means.
+func fitRho(m Model, obs []observation) float64 {
+	best, bestLikelihood := 0.0, math.Inf(-1)
+	for rho := -0.25; rho <= 0.25+1e-9; rho += 0.005 {
+		likelihood := 0.0
+		valid := true
+		for _, o := range obs {
+			homeMean, awayMean := m.Expected(o.home, o.away)
+			t := tau(o.homeGoals, o.awayGoals, homeMean, awayMean, rho)
+			if t <= 0 {
+				valid = false
+				break
+			}
+			likelihood += o.weight * math.Log(t)
+		}
+		if valid && likelihood > bestLikelihood {
+			best, bestLikelihood = rho, likelihood
+		}
+	}
+	return math.Round(best*1000) / 1000
+}
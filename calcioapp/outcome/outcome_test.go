@@ -0,0 +1,166 @@
+package outcome
+
+import (
+	"encoding/json"
+	"math"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+)
+
+func near(a float64, b float64, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestPredict(t *testing.T) {
+	model := Model{HomeGoals: 1.5, AwayGoals: 1.1, Teams: map[int]Strength{1: {1.3, 0.8}, 2: {0.9, 1.1}}}
+	independent := model.Predict(1, 2)
+	homeMean, awayMean := 1.5*1.3*1.1, 1.1*0.9*0.8
+	if !near(independent.ExpectedGoals.Home, homeMean, 1e-12) || !near(independent.ExpectedGoals.Away, awayMean, 1e-12) {
+		t.Errorf("expected goals = %+v", independent.ExpectedGoals)
+	}
+	outcome := independent.Outcome
+	if !near(outcome.Home+outcome.Draw+outcome.Away, 1, 1e-9) {
+		t.Errorf("outcome sums to %f", outcome.Home+outcome.Draw+outcome.Away)
+	}
+	if want := pmf(homeMean, 2) * pmf(awayMean, 1); !near(independent.Score(2, 1), want, 1e-4) {
+		t.Errorf("P(2-1) = %f, want %f", independent.Score(2, 1), want)
+	}
+	for _, ou := range independent.OverUnder {
+		if !near(ou.Over+ou.Under, 1, 1e-9) || !near(ou.Over, independent.Over(ou.Line), 1e-12) {
+			t.Errorf("over/under %+v", ou)
+		}
+	}
+	if len(independent.Scores) != topScores || independent.Scores[0].Probability < independent.Scores[1].Probability {
+		t.Errorf("scores = %+v", independent.Scores)
+	}
+
+	model.Rho = -0.1
+	dependent := model.Predict(1, 2)
+	if dependent.Score(0, 0) <= independent.Score(0, 0) || dependent.Score(1, 1) <= independent.Score(1, 1) {
+		t.Error("a negative rho did not make 0-0 and 1-1 more likely")
+	}
+	if dependent.Score(1, 0) >= independent.Score(1, 0) {
+		t.Error("a negative rho did not make 1-0 less likely")
+	}
+}
+
+func TestPredictSmallMatrix(t *testing.T) {
+	for maxGoals, scores := range map[int]int{1: 4, 2: 9, 3: topScores} {
+		model := Model{HomeGoals: 1.5, AwayGoals: 1.1, maxGoals: maxGoals}
+		p := model.Predict(1, 2)
+		if len(p.Scores) != scores {
+			t.Errorf("MaxGoals %d: %d scores, want %d", maxGoals, len(p.Scores), scores)
+		}
+		for _, s := range p.Scores {
+			if s.Home > maxGoals || s.Away > maxGoals || s.Probability == 0 {
+				t.Errorf("MaxGoals %d: score %+v", maxGoals, s)
+			}
+		}
+	}
+}
+
+func TestFitWithoutGoals(t *testing.T) {
+	var goalless apifootball.Fixture
+	goalless.Fixture.Date = time.Date(2021, 8, 21, 18, 45, 0, 0, time.UTC)
+	goalless.Fixture.Status.Short = "FT"
+	goalless.Teams.Home.ID, goalless.Teams.Away.ID = 1, 2
+	goalless.Goals.Home = apifootball.NullInt{Int: 0, Valid: true}
+	goalless.Goals.Away = apifootball.NullInt{Int: 0, Valid: true}
+
+	model := Fit([]apifootball.Fixture{goalless}, goalless.Fixture.Date.AddDate(0, 0, 7), Options{})
+	if model.Fixtures != 1 || model.Teams[1] != (Strength{1, 1}) || model.Teams[2] != (Strength{1, 1}) {
+		t.Errorf("model = %+v", model)
+	}
+	p := model.Predict(1, 2)
+	if _, err := json.Marshal(p); err != nil || math.IsNaN(p.Outcome.Home) {
+		t.Errorf("prediction %+v: %v", p.Outcome, err)
+	}
+}
+
+func TestParseUnderOver(t *testing.T) {
+	for pick, want := range map[string]struct {
+		line     float64
+		over, ok bool
+	}{
+		"+2.5": {2.5, true, true},
+		"-3.5": {3.5, false, true},
+		"2.5":  {0, false, false},
+		"":     {0, false, false},
+	} {
+		line, over, ok := parseUnderOver(pick)
+		if line != want.line || over != want.over || ok != want.ok {
+			t.Errorf("parseUnderOver(%q) = %v, %v, %v", pick, line, over, ok)
+		}
+	}
+}
+
+func TestFitAndCompare(t *testing.T) {
+	server := httptest.NewServer(fake.New(fake.Options{DailyLimit: -1}))
+	defer server.Close()
+	client := apifootball.New("test-token", server.URL+"/")
+	fixtures, err := client.GetFixturesByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := client.GetStandingsByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	asOf := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+	model := Fit(fixtures.Response, asOf, Options{})
+	if model.Fixtures == 0 || len(model.Teams) != 20 {
+		t.Fatalf("fitted %d fixtures and %d teams", model.Fixtures, len(model.Teams))
+	}
+	if model.HomeGoals <= model.AwayGoals {
+		t.Errorf("home %f not above away %f", model.HomeGoals, model.AwayGoals)
+	}
+	var attack, defence float64
+	for _, s := range model.Teams {
+		attack += s.Attack
+		defence += s.Defence
+	}
+	if !near(attack, 20, 1e-6) || !near(defence, 20, 1e-6) {
+		t.Errorf("strengths average %f and %f, want 1", attack/20, defence/20)
+	}
+	rows := table.Response[0].League.Standings[0]
+	best, worst := model.Teams[rows[0].Team.ID], model.Teams[rows[len(rows)-1].Team.ID]
+	if best.Attack/best.Defence <= worst.Attack/worst.Defence {
+		t.Errorf("leader %+v not stronger than last %+v", best, worst)
+	}
+
+	early := Fit(fixtures.Response, time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC), Options{})
+	if early.Fixtures >= model.Fixtures {
+		t.Errorf("fixtures after asOf were used: %d and %d", early.Fixtures, model.Fixtures)
+	}
+
+	var next apifootball.Fixture
+	for _, fixture := range fixtures.Response {
+		if fixture.Fixture.Date.After(asOf) {
+			next = fixture
+			break
+		}
+	}
+	predictions, err := client.GetPredictionsByFixtureId(strconv.Itoa(next.Fixture.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	comparison := Compare(next, Fit(fixtures.Response, next.Fixture.Date, Options{}), predictions)
+	if comparison.Provider == nil || comparison.Agreement == nil || comparison.Agreement.Advice == nil || comparison.Agreement.UnderOver == nil {
+		t.Fatalf("comparison = %+v", comparison)
+	}
+	diff := comparison.Difference
+	if !near(diff.Home+diff.Draw+diff.Away, 0, 0.02) {
+		t.Errorf("differences %+v do not cancel out", diff)
+	}
+
+	alone := Compare(next, model, apifootball.Predictions{})
+	if alone.Provider != nil || alone.Agreement != nil {
+		t.Errorf("comparison without provider = %+v", alone)
+	}
+}
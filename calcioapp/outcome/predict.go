@@ -0,0 +1,137 @@
+package outcome
+
+import (
+	"math"
+	"sort"
+)
+
+type Probabilities struct {
+	Home float64 `json:"home"`
+	Draw float64 `json:"draw"`
+	Away float64 `json:"away"`
+}
+
+type ScoreProbability struct {
+	Home        int     `json:"home"`
+	Away        int     `json:"away"`
+	Probability float64 `json:"probability"`
+}
+
+type OverUnder struct {
+	Line  float64 `json:"line"`
+	Over  float64 `json:"over"`
+	Under float64 `json:"under"`
+}
+
+type Prediction struct {
+	ExpectedGoals struct {
+		Home float64 `json:"home"`
+		Away float64 `json:"away"`
+	} `json:"expectedGoals"`
+	Outcome   Probabilities `json:"outcome"`
+	BothScore float64       `json:"bothScore"`
+	// Scores are the most likely exact scores, most likely first.
+	Scores    []ScoreProbability `json:"scores"`
+	OverUnder []OverUnder        `json:"overUnder"`
+
+	matrix [][]float64
+}
+
+// Lines are the goal lines of Prediction.OverUnder.
+var Lines = []float64{0.5, 1.5, 2.5, 3.5, 4.5}
+
+const topScores = 10
+
+// Predict gives the probabilities of a fixture between two teams.
+func (m Model) Predict(homeId int, awayId int) Prediction {
+	maxGoals := m.maxGoals
+	if maxGoals <= 0 {
+		maxGoals = 10
+	}
+	homeMean, awayMean := m.Expected(homeId, awayId)
+
+	var p Prediction
+	p.ExpectedGoals.Home, p.ExpectedGoals.Away = homeMean, awayMean
+	p.matrix = make([][]float64, maxGoals+1)
+	var total float64
+	for i := range p.matrix {
+		p.matrix[i] = make([]float64, maxGoals+1)
+		for j := range p.matrix[i] {
+			p.matrix[i][j] = pmf(homeMean, i) * pmf(awayMean, j) * tau(i, j, homeMean, awayMean, m.Rho)
+			total += p.matrix[i][j]
+		}
+	}
+	// Scores beyond maxGoals are spread over the matrix.
+	for i := range p.matrix {
+		for j := range p.matrix[i] {
+			p.matrix[i][j] /= total
+		}
+	}
+
+	p.OverUnder = make([]OverUnder, len(Lines))
+	for i, line := range Lines {
+		p.OverUnder[i].Line = line
+	}
+	for i, row := range p.matrix {
+		for j, prob := range row {
+			switch {
+			case i > j:
+				p.Outcome.Home += prob
+			case i < j:
+				p.Outcome.Away += prob
+			default:
+				p.Outcome.Draw += prob
+			}
+			if i > 0 && j > 0 {
+				p.BothScore += prob
+			}
+			for k := range p.OverUnder {
+				if float64(i+j) > p.OverUnder[k].Line {
+					p.OverUnder[k].Over += prob
+				} else {
+					p.OverUnder[k].Under += prob
+				}
+			}
+			p.Scores = append(p.Scores, ScoreProbability{i, j, prob})
+		}
+	}
+	sort.SliceStable(p.Scores, func(i, j int) bool {
+		return p.Scores[i].Probability > p.Scores[j].Probability
+	})
+	if len(p.Scores) > topScores {
+		p.Scores = p.Scores[:topScores]
+	}
+	return p
+}
+
+// Score returns the probability of an exact score.
+func (p Prediction) Score(home int, away int) float64 {
+	if home < 0 || away < 0 || home >= len(p.matrix) || away >= len(p.matrix) {
+		return 0
+	}
+	return p.matrix[home][away]
+}
+
+// Over returns the probability of more than line goals.
+func (p Prediction) Over(line float64) float64 {
+	var over float64
+	for i, row := range p.matrix {
+		for j, prob := range row {
+			if float64(i+j) > line {
+				over += prob
+			}
+		}
+	}
+	return over
+}
+
+func pmf(mean float64, k int) float64 {
+	if mean <= 0 {
+		if k == 0 {
+			return 1
+		}
+		return 0
+	}
+	lgamma, _ := math.Lgamma(float64(k + 1))
+	return math.Exp(float64(k)*math.Log(mean) - mean - lgamma)
+}
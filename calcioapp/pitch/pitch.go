@@ -0,0 +1,251 @@
+// Package pitch draws the starting elevens of a fixture on a pitch, as SVG.
+package pitch
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+// The pitch is drawn at 10 units a metre, with a band above it for the
+// team names.
+const (
+	length = 1050.0
+	width  = 680.0
+	margin = 30.0
+	band   = 60.0
+	radius = 17.0
+)
+
+// Player is a starter with his pitch position: row 1 is the goalkeeper and
+// rows go up the pitch; columns run across it.
+type Player struct {
+	Name   string
+	Number int
+	Row    int
+	Column int
+	Keeper bool
+}
+
+type Kit struct {
+	Primary string
+	Number  string
+	Border  string
+}
+
+// Team is one side of the diagram.
+type Team struct {
+	Name       string
+	Formation  string
+	Outfield   Kit
+	Goalkeeper Kit
+	Players    []Player
+}
+
+// TeamOf reads a team from a fixtures/lineups response for one team; it is
+// false when the provider has not published the lineup yet.
+func TeamOf(lineups apifootball.Lineups) (Team, bool) {
+	if len(lineups.Response) == 0 || len(lineups.Response[0].Startxi) == 0 {
+		return Team{}, false
+	}
+	l := lineups.Response[0]
+	colors := l.Team.Colors
+	team := Team{
+		Name:       l.Team.Name,
+		Formation:  l.Formation,
+		Outfield:   Kit{colors.Player.Primary, colors.Player.Number, colors.Player.Border},
+		Goalkeeper: Kit{colors.Goalkeeper.Primary, colors.Goalkeeper.Number, colors.Goalkeeper.Border},
+	}
+	placed := true
+	for _, s := range l.Startxi {
+		row, column, ok := grid(s.Player.Grid)
+		placed = placed && ok
+		team.Players = append(team.Players, Player{Name: s.Player.Name, Number: s.Player.Number, Row: row, Column: column, Keeper: s.Player.Pos == "G"})
+	}
+	if !placed {
+		team.place()
+	}
+	return team, true
+}
+
+// grid parses a "row:column" grid position.
+func grid(g string) (int, int, bool) {
+	parts := strings.Split(g, ":")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	row, err1 := strconv.Atoi(parts[0])
+	column, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || row < 1 || column < 1 {
+		return 0, 0, false
+	}
+	return row, column, true
+}
+
+// place positions the players from the formation when the provider sends
+// no grid: the goalkeeper first, then the lines from defence to attack.
+func (t *Team) place() {
+	rows := []int{1}
+	for _, n := range strings.Split(t.Formation, "-") {
+		if count, err := strconv.Atoi(n); err == nil && count > 0 {
+			rows = append(rows, count)
+		}
+	}
+	i := 0
+	for row, n := range rows {
+		for column := 1; column <= n && i < len(t.Players); column++ {
+			t.Players[i].Row, t.Players[i].Column = row+1, column
+			i++
+		}
+	}
+	// Players the formation does not account for make up a last line.
+	for column := 1; i < len(t.Players); column++ {
+		t.Players[i].Row, t.Players[i].Column = len(rows)+1, column
+		i++
+	}
+}
+
+var hex = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
+
+// color is a provider colour as CSS, or fallback when it is not a hex
+// colour.
+func color(c string, fallback string) string {
+	if hex.MatchString(c) {
+		return "#" + strings.ToLower(c)
+	}
+	return fallback
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// SVG draws home attacking left to right and away right to left.
+func SVG(w io.Writer, home Team, away Team) error {
+	var b strings.Builder
+	total := length + 2*margin
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %g %g" width="%g" height="%g" font-family="sans-serif">`+"\n",
+		total, width+2*margin+band, total, width+2*margin+band)
+	fmt.Fprintf(&b, `<rect width="%g" height="%g" fill="#2e7d32"/>`+"\n", total, width+2*margin+band)
+	fmt.Fprintf(&b, `<text x="%g" y="40" font-size="26" fill="#ffffff">%s</text>`+"\n", margin, escape(title(home)))
+	fmt.Fprintf(&b, `<text x="%g" y="40" font-size="26" fill="#ffffff" text-anchor="end">%s</text>`+"\n", margin+length, escape(title(away)))
+	markings(&b)
+	side(&b, home, false)
+	side(&b, away, true)
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func title(t Team) string {
+	if t.Formation == "" {
+		return t.Name
+	}
+	return t.Name + " " + t.Formation
+}
+
+// markings draws the lines of a 105 by 68 metre pitch.
+func markings(b *strings.Builder) {
+	x, y := margin, margin+band
+	fmt.Fprintf(b, `<g fill="none" stroke="#ffffff" stroke-width="3">`+"\n")
+	fmt.Fprintf(b, `<rect x="%g" y="%g" width="%g" height="%g"/>`+"\n", x, y, length, width)
+	fmt.Fprintf(b, `<line x1="%g" y1="%g" x2="%g" y2="%g"/>`+"\n", x+length/2, y, x+length/2, y+width)
+	fmt.Fprintf(b, `<circle cx="%g" cy="%g" r="91.5"/>`+"\n", x+length/2, y+width/2)
+	for _, left := range []bool{true, false} {
+		box := func(depth float64, breadth float64) {
+			bx := x
+			if !left {
+				bx = x + length - depth
+			}
+			fmt.Fprintf(b, `<rect x="%g" y="%g" width="%g" height="%g"/>`+"\n", bx, y+(width-breadth)/2, depth, breadth)
+		}
+		box(165, 403)
+		box(55, 183)
+	}
+	b.WriteString("</g>\n")
+}
+
+// side draws the players of a team in its half.
+func side(b *strings.Builder, t Team, mirrored bool) {
+	rows, columns := 0, map[int]int{}
+	for _, p := range t.Players {
+		if p.Row > rows {
+			rows = p.Row
+		}
+		if p.Column > columns[p.Row] {
+			columns[p.Row] = p.Column
+		}
+	}
+	for _, p := range t.Players {
+		// Lines are spread over the half, the goalkeeper on their line.
+		depth := 40.0
+		if rows > 1 {
+			depth += (float64(p.Row-1) / float64(rows-1)) * (length/2 - 100)
+		}
+		across := (float64(p.Column) - 0.5) / float64(columns[p.Row]) * width
+		x, y := margin+depth, margin+band+width-across
+		if mirrored {
+			x, y = margin+length-depth, margin+band+across
+		}
+		// Rounded so the output does not depend on float noise.
+		x, y = math.Round(x*10)/10, math.Round(y*10)/10
+		kit := t.Outfield
+		if p.Keeper {
+			kit = t.Goalkeeper
+		}
+		fmt.Fprintf(b, `<g transform="translate(%g,%g)">`+"\n", x, y)
+		fmt.Fprintf(b, `<circle r="%g" fill="%s" stroke="%s" stroke-width="3"/>`+"\n", radius, color(kit.Primary, "#ffffff"), color(kit.Border, "#000000"))
+		fmt.Fprintf(b, `<text y="6" font-size="16" font-weight="bold" fill="%s" text-anchor="middle">%d</text>`+"\n", color(kit.Number, "#000000"), p.Number)
+		fmt.Fprintf(b, `<text y="%g" font-size="14" fill="#ffffff" text-anchor="middle">%s</text>`+"\n", radius+18, escape(p.Name))
+		b.WriteString("</g>\n")
+	}
+}
+
+// ErrNoLineups is returned when a lineup is not published yet.
+var ErrNoLineups = errors.New("pitch: lineups not available")
+
+// Fetch reads the lineups of both teams of a fixture, concurrently.
+func Fetch(ctx context.Context, client *apifootball.APIClient, fixtureId string) (Team, Team, error) {
+	client = client.WithContext(ctx)
+	fixtures, err := client.GetFixtureByFixtureId(fixtureId)
+	if err != nil {
+		return Team{}, Team{}, err
+	}
+	if fixtures.Results == 0 {
+		return Team{}, Team{}, ErrNoLineups
+	}
+	f := fixtures.Response[0]
+	var lineups [2]apifootball.Lineups
+	var errs [2]error
+	var wg sync.WaitGroup
+	for i, team := range []int{f.Teams.Home.ID, f.Teams.Away.ID} {
+		wg.Add(1)
+		go func(i int, team int) {
+			defer wg.Done()
+			lineups[i], errs[i] = client.GetLineupsByTeamIdAndFixtureId(strconv.Itoa(team), fixtureId)
+		}(i, team)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return Team{}, Team{}, err
+		}
+	}
+	home, ok := TeamOf(lineups[0])
+	away, ok2 := TeamOf(lineups[1])
+	if !ok || !ok2 {
+		return Team{}, Team{}, ErrNoLineups
+	}
+	return home, away, nil
+}
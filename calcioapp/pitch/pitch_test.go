@@ -0,0 +1,136 @@
+package pitch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+)
+
+// go test ./pitch -update rewrites the golden files from the current output.
+var update = flag.Bool("update", false, "rewrite the golden SVG files")
+
+func lineups(t *testing.T, payload string) Team {
+	var l apifootball.Lineups
+	if err := json.Unmarshal([]byte(payload), &l); err != nil {
+		t.Fatal(err)
+	}
+	team, ok := TeamOf(l)
+	if !ok {
+		t.Fatal("no lineup")
+	}
+	return team
+}
+
+const inter = `{"response": [{"team": {"id": 505, "name": "Inter",
+	"colors": {"player": {"primary": "0a2f6b", "number": "FFFFFF", "border": "0a2f6b"}, "goalkeeper": {"primary": "F0E000", "number": "000000", "border": "F0E000"}}},
+	"formation": "3-5-2", "startXI": [
+	{"player": {"id": 1, "name": "S. Handanovič", "number": 1, "pos": "G", "grid": "1:1"}},
+	{"player": {"id": 2, "name": "A. Bastoni", "number": 95, "pos": "D", "grid": "2:3"}},
+	{"player": {"id": 3, "name": "S. de Vrij", "number": 6, "pos": "D", "grid": "2:2"}},
+	{"player": {"id": 4, "name": "M. Škriniar", "number": 37, "pos": "D", "grid": "2:1"}},
+	{"player": {"id": 5, "name": "I. Perišić", "number": 14, "pos": "M", "grid": "3:5"}},
+	{"player": {"id": 6, "name": "H. Çalhanoğlu", "number": 20, "pos": "M", "grid": "3:4"}},
+	{"player": {"id": 7, "name": "M. Brozović", "number": 77, "pos": "M", "grid": "3:3"}},
+	{"player": {"id": 8, "name": "N. Barella", "number": 23, "pos": "M", "grid": "3:2"}},
+	{"player": {"id": 9, "name": "D. Dumfries", "number": 2, "pos": "M", "grid": "3:1"}},
+	{"player": {"id": 10, "name": "E. Džeko", "number": 9, "pos": "F", "grid": "4:2"}},
+	{"player": {"id": 11, "name": "Lautaro Martínez", "number": 10, "pos": "F", "grid": "4:1"}}]}]}`
+
+// Without grids nor valid colours, with names to escape.
+const visitors = `{"response": [{"team": {"id": 2, "name": "Away & Co",
+	"colors": {"player": {"primary": "red", "number": "", "border": null}, "goalkeeper": {}}},
+	"formation": "4-4-2", "startXI": [
+	{"player": {"name": "K <1>", "number": 1, "pos": "G", "grid": null}},
+	{"player": {"name": "D'2", "number": 2, "pos": "D"}},
+	{"player": {"name": "D3", "number": 3, "pos": "D"}},
+	{"player": {"name": "D4", "number": 4, "pos": "D"}},
+	{"player": {"name": "D5", "number": 5, "pos": "D"}},
+	{"player": {"name": "M6", "number": 6, "pos": "M"}},
+	{"player": {"name": "M7", "number": 7, "pos": "M"}},
+	{"player": {"name": "M8", "number": 8, "pos": "M"}},
+	{"player": {"name": "M9", "number": 9, "pos": "M"}},
+	{"player": {"name": "F10", "number": 10, "pos": "F"}},
+	{"player": {"name": "F11", "number": 11, "pos": "F"}}]}]}`
+
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	file := filepath.Join("testdata", name+".svg")
+	if *update {
+		if err := ioutil.WriteFile(file, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file; run go test ./pitch -update after checking the output:\n%s", file, got)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	home, away := lineups(t, inter), lineups(t, visitors)
+	if p := away.Players[10]; p.Row != 4 || p.Column != 2 {
+		t.Errorf("placed last forward at %d:%d, want 4:2", p.Row, p.Column)
+	}
+
+	for _, c := range []struct {
+		name       string
+		home, away Team
+	}{
+		{"grid", home, home},
+		{"formation", home, away},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := SVG(&b, c.home, c.away); err != nil {
+				t.Fatal(err)
+			}
+			golden(t, c.name, b.Bytes())
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	ts := httptest.NewServer(fake.New(fake.Options{DailyLimit: -1}))
+	defer ts.Close()
+	client := apifootball.New("test-token", ts.URL+"/")
+
+	fixtures, err := client.GetFixturesByTeamId("505")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var played, upcoming string
+	for _, f := range fixtures.Response {
+		switch f.Fixture.Status.Short {
+		case "FT":
+			played = strconv.Itoa(f.Fixture.ID)
+		case "NS":
+			upcoming = strconv.Itoa(f.Fixture.ID)
+		}
+	}
+
+	home, away, err := Fetch(context.Background(), client, played)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	SVG(&b, home, away)
+	if n := strings.Count(b.String(), "<circle r="); n != 22 {
+		t.Errorf("%d players drawn", n)
+	}
+	if _, _, err := Fetch(context.Background(), client, upcoming); err != ErrNoLineups {
+		t.Errorf("lineups of an upcoming fixture: %v", err)
+	}
+}
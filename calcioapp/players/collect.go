@@ -0,0 +1,55 @@
+package players
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/cache"
+)
+
+// Collect fetches every page of a league's players.
+func Collect(ctx context.Context, client *apifootball.APIClient, leagueId int) ([]apifootball.Players, error) {
+	client = client.WithContext(ctx)
+	league := strconv.Itoa(leagueId)
+	var pages []apifootball.Players
+	for page := 1; ; page++ {
+		players, err := client.GetPlayersByLeagueId(league, strconv.Itoa(page))
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, players)
+		if players.Paging.Current >= players.Paging.Total {
+			return pages, nil
+		}
+	}
+}
+
+// Cache keeps the profiles of each league for a while: a league costs one
+// request per 20 players.
+type Cache struct {
+	MinMinutes int
+
+	leagues *cache.Cache
+}
+
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{MinMinutes: DefaultMinMinutes, leagues: cache.New("players", ttl)}
+}
+
+// League returns the profiles of a league, collecting them when they are
+// not cached or older than the TTL.
+func (c *Cache) League(ctx context.Context, client *apifootball.APIClient, leagueId int) (League, error) {
+	league, err := c.leagues.Get(ctx, strconv.Itoa(leagueId), func(ctx context.Context) (interface{}, error) {
+		pages, err := Collect(ctx, client, leagueId)
+		if err != nil {
+			return nil, err
+		}
+		return Build(leagueId, pages, c.MinMinutes), nil
+	})
+	if err != nil {
+		return League{}, err
+	}
+	return league.(League), nil
+}
@@ -0,0 +1,70 @@
+// Package players compares players of a league on per 90 minute metrics
+// and ranks them in percentiles against players of the same position.
+package players
+
+// Metric is one axis of a comparison.
+type Metric struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	// Per90 metrics are counts scaled to 90 minutes; the others are rates
+	// between 0 and 100.
+	Per90 bool `json:"per90"`
+	// LowerIsBetter inverts the percentile, e.g. for fouls committed.
+	LowerIsBetter bool `json:"lowerIsBetter"`
+}
+
+type metric struct {
+	Metric
+	// value returns the count or the rate, and false when it is unknown.
+	value func(t Totals) (float64, bool)
+}
+
+func count(n func(t Totals) int) func(t Totals) (float64, bool) {
+	return func(t Totals) (float64, bool) { return float64(n(t)), true }
+}
+
+func rate(part func(t Totals) int, whole func(t Totals) int) func(t Totals) (float64, bool) {
+	return func(t Totals) (float64, bool) {
+		if whole(t) == 0 {
+			return 0, false
+		}
+		return 100 * float64(part(t)) / float64(whole(t)), true
+	}
+}
+
+var axes = []metric{
+	{Metric{"goals", "Goals", true, false}, count(func(t Totals) int { return t.Goals })},
+	{Metric{"assists", "Assists", true, false}, count(func(t Totals) int { return t.Assists })},
+	{Metric{"shots", "Shots", true, false}, count(func(t Totals) int { return t.Shots })},
+	{Metric{"shotsOnTarget", "Shots on target", true, false}, count(func(t Totals) int { return t.ShotsOn })},
+	{Metric{"keyPasses", "Key passes", true, false}, count(func(t Totals) int { return t.KeyPasses })},
+	{Metric{"passes", "Passes", true, false}, count(func(t Totals) int { return t.Passes })},
+	{Metric{"passAccuracy", "Pass accuracy %", false, false}, func(t Totals) (float64, bool) {
+		if t.PassAccuracy == nil {
+			return 0, false
+		}
+		return *t.PassAccuracy, true
+	}},
+	{Metric{"tackles", "Tackles", true, false}, count(func(t Totals) int { return t.Tackles })},
+	{Metric{"interceptions", "Interceptions", true, false}, count(func(t Totals) int { return t.Interceptions })},
+	{Metric{"blocks", "Blocks", true, false}, count(func(t Totals) int { return t.Blocks })},
+	{Metric{"duelsWon", "Duels won", true, false}, count(func(t Totals) int { return t.DuelsWon })},
+	{Metric{"duelSuccess", "Duels won %", false, false}, rate(func(t Totals) int { return t.DuelsWon }, func(t Totals) int { return t.Duels })},
+	{Metric{"dribbles", "Successful dribbles", true, false}, count(func(t Totals) int { return t.DribblesWon })},
+	{Metric{"dribbleSuccess", "Dribble success %", false, false}, rate(func(t Totals) int { return t.DribblesWon }, func(t Totals) int { return t.Dribbles })},
+	{Metric{"foulsDrawn", "Fouls drawn", true, false}, count(func(t Totals) int { return t.FoulsDrawn })},
+	{Metric{"foulsCommitted", "Fouls committed", true, true}, count(func(t Totals) int { return t.FoulsCommitted })},
+	{Metric{"cards", "Cards", true, true}, count(func(t Totals) int { return t.Yellow + t.YellowRed + t.Red })},
+	{Metric{"saves", "Saves", true, false}, count(func(t Totals) int { return t.Saves })},
+	{Metric{"conceded", "Goals conceded", true, true}, count(func(t Totals) int { return t.Conceded })},
+}
+
+// Metrics lists the metrics in the order of Profile.Values and of the axes
+// of a comparison.
+func Metrics() []Metric {
+	list := make([]Metric, len(axes))
+	for i, m := range axes {
+		list[i] = m.Metric
+	}
+	return list
+}
@@ -0,0 +1,207 @@
+package players
+
+import (
+	"math"
+	"sort"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+// DefaultMinMinutes is the playing time, five full matches, from which a
+// player is ranked among their peers.
+const DefaultMinMinutes = 450
+
+type Player struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Age         int    `json:"age"`
+	Nationality string `json:"nationality"`
+	Photo       string `json:"photo"`
+}
+
+type Team struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+// Profile is a player's season in a league. Values and Percentiles follow
+// the order of Metrics; they are null where the provider sent nothing to
+// compute them from.
+type Profile struct {
+	Player      Player `json:"player"`
+	Teams       []Team `json:"teams"`
+	Position    string `json:"position"`
+	Minutes     int    `json:"minutes"`
+	Appearances int    `json:"appearances"`
+	// Qualified players played MinMinutes; only they make up the
+	// population the percentiles are taken from.
+	Qualified   bool       `json:"qualified"`
+	Values      []*float64 `json:"values"`
+	Percentiles []*float64 `json:"percentiles"`
+}
+
+// League is every player of a league with their per 90 metrics and
+// their percentiles within their position.
+type League struct {
+	LeagueID   int       `json:"leagueId"`
+	MinMinutes int       `json:"minMinutes"`
+	Metrics    []Metric  `json:"metrics"`
+	Players    []Profile `json:"players"`
+}
+
+// Build profiles the players of the pages of a league's players endpoint,
+// counting only their statistics in that league.
+func Build(leagueId int, pages []apifootball.Players, minMinutes int) League {
+	league := League{LeagueID: leagueId, MinMinutes: minMinutes, Metrics: Metrics()}
+	seen := map[int]bool{}
+	for _, page := range pages {
+		for _, entry := range page.Response {
+			if seen[entry.Player.ID] {
+				continue
+			}
+			seen[entry.Player.ID] = true
+
+			var t Totals
+			profile := Profile{Player: Player{entry.Player.ID, entry.Player.Name, entry.Player.Age, entry.Player.Nationality, entry.Player.Photo}}
+			most := -1
+			for _, s := range entry.Statistics {
+				if s.League.ID != leagueId {
+					continue
+				}
+				t.add(s)
+				profile.Teams = append(profile.Teams, Team{s.Team.ID, s.Team.Name, s.Team.Logo})
+				if s.Games.Minutes.Int > most {
+					most = s.Games.Minutes.Int
+					profile.Position = s.Games.Position
+				}
+			}
+			if most < 0 {
+				continue
+			}
+			profile.Minutes, profile.Appearances = t.Minutes, t.Appearances
+			profile.Qualified = t.Minutes >= minMinutes
+			profile.Values = values(t)
+			league.Players = append(league.Players, profile)
+		}
+	}
+	league.rank()
+	sort.SliceStable(league.Players, func(i, j int) bool {
+		return league.Players[i].Minutes > league.Players[j].Minutes
+	})
+	return league
+}
+
+func values(t Totals) []*float64 {
+	list := make([]*float64, len(axes))
+	for i, m := range axes {
+		v, ok := m.value(t)
+		if !ok || m.Per90 && t.Minutes == 0 {
+			continue
+		}
+		if m.Per90 {
+			v = v * 90 / float64(t.Minutes)
+		}
+		v = round(v, 2)
+		list[i] = &v
+	}
+	return list
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
+
+// rank sets the percentiles of every player against the qualified players
+// of their position: the share of them they are better than, ties counting
+// half.
+func (l *League) rank() {
+	groups := map[string][][]float64{}
+	for _, p := range l.Players {
+		if !p.Qualified {
+			continue
+		}
+		population, ok := groups[p.Position]
+		if !ok {
+			population = make([][]float64, len(axes))
+			groups[p.Position] = population
+		}
+		for i, v := range p.Values {
+			if v != nil {
+				population[i] = append(population[i], *v)
+			}
+		}
+	}
+	for i := range l.Players {
+		p := &l.Players[i]
+		p.Percentiles = make([]*float64, len(axes))
+		population := groups[p.Position]
+		for m, v := range p.Values {
+			if v == nil || population == nil || len(population[m]) == 0 {
+				continue
+			}
+			var below, equal int
+			for _, other := range population[m] {
+				switch {
+				case other < *v:
+					below++
+				case other == *v:
+					equal++
+				}
+			}
+			if axes[m].LowerIsBetter {
+				below = len(population[m]) - below - equal
+			}
+			pct := round(100*(float64(below)+float64(equal)/2)/float64(len(population[m])), 1)
+			p.Percentiles[m] = &pct
+		}
+	}
+}
+
+// Player returns the profile of a player.
+func (l League) Player(id int) (Profile, bool) {
+	for _, p := range l.Players {
+		if p.Player.ID == id {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// Position keeps the players of a position, or all of them for "".
+func (l League) Position(position string) League {
+	if position == "" {
+		return l
+	}
+	filtered := l
+	filtered.Players = nil
+	for _, p := range l.Players {
+		if p.Position == position {
+			filtered.Players = append(filtered.Players, p)
+		}
+	}
+	return filtered
+}
+
+// Comparison sets players side by side, ready for a radar chart: one axis
+// per metric, the percentile as the radius and the value as its label.
+type Comparison struct {
+	LeagueID int       `json:"leagueId"`
+	Axes     []Metric  `json:"axes"`
+	Players  []Profile `json:"players"`
+	// Missing lists the ids without statistics in the league.
+	Missing []int `json:"missing"`
+}
+
+func (l League) Compare(ids []int) Comparison {
+	comparison := Comparison{LeagueID: l.LeagueID, Axes: l.Metrics, Players: []Profile{}, Missing: []int{}}
+	for _, id := range ids {
+		if p, ok := l.Player(id); ok {
+			comparison.Players = append(comparison.Players, p)
+		} else {
+			comparison.Missing = append(comparison.Missing, id)
+		}
+	}
+	return comparison
+}
@@ -0,0 +1,200 @@
+package players
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+)
+
+type entry struct {
+	id       int
+	position string
+	// stats are the statistics entries of the player, one per team or
+	// competition.
+	stats []map[string]interface{}
+}
+
+func stat(league int, team int, minutes int, goals int, fouls int) map[string]interface{} {
+	return map[string]interface{}{
+		"team":   map[string]interface{}{"id": team, "name": "Team"},
+		"league": map[string]interface{}{"id": league},
+		"games":  map[string]interface{}{"appearences": minutes / 90, "minutes": minutes, "position": "Attacker"},
+		"goals":  map[string]interface{}{"total": goals},
+		"fouls":  map[string]interface{}{"committed": fouls},
+	}
+}
+
+func page(t *testing.T, entries ...entry) apifootball.Players {
+	var response []map[string]interface{}
+	for _, e := range entries {
+		for _, s := range e.stats {
+			s["games"].(map[string]interface{})["position"] = e.position
+		}
+		response = append(response, map[string]interface{}{
+			"player":     map[string]interface{}{"id": e.id, "name": "Player"},
+			"statistics": e.stats,
+		})
+	}
+	payload, _ := json.Marshal(map[string]interface{}{"response": response})
+	var players apifootball.Players
+	if err := json.Unmarshal(payload, &players); err != nil {
+		t.Fatal(err)
+	}
+	return players
+}
+
+func index(key string) int {
+	for i, m := range axes {
+		if m.Key == key {
+			return i
+		}
+	}
+	panic(key)
+}
+
+func TestBuild(t *testing.T) {
+	league := Build(135, []apifootball.Players{
+		page(t,
+			// Transferred in January: both teams count, the cup does not.
+			entry{1, "Attacker", []map[string]interface{}{stat(135, 10, 450, 3, 10), stat(135, 11, 450, 3, 0), stat(137, 10, 90, 5, 0)}},
+			entry{2, "Attacker", []map[string]interface{}{stat(135, 10, 900, 2, 2)}},
+		),
+		page(t,
+			entry{3, "Attacker", []map[string]interface{}{stat(135, 12, 900, 2, 6)}},
+			entry{4, "Attacker", []map[string]interface{}{stat(135, 12, 180, 4, 0)}},
+			entry{5, "Defender", []map[string]interface{}{stat(135, 12, 900, 0, 20)}},
+			entry{6, "Attacker", []map[string]interface{}{stat(137, 12, 900, 9, 0)}},
+		),
+	}, DefaultMinMinutes)
+
+	if len(league.Players) != 5 {
+		t.Fatalf("%d players, want 5 without the cup only player", len(league.Players))
+	}
+	goals, fouls := index("goals"), index("foulsCommitted")
+
+	first, ok := league.Player(1)
+	if !ok || first.Minutes != 900 || len(first.Teams) != 2 || !first.Qualified {
+		t.Fatalf("player 1 = %+v", first)
+	}
+	if *first.Values[goals] != 0.6 || *first.Values[fouls] != 1 {
+		t.Errorf("player 1 goals %v fouls %v per 90", *first.Values[goals], *first.Values[fouls])
+	}
+	// Against players 1, 2 and 3: better than two, level with himself.
+	if *first.Percentiles[goals] != 83.3 {
+		t.Errorf("player 1 goals percentile = %v", *first.Percentiles[goals])
+	}
+	// Fouls are better low: players 2 and 3 commit fewer.
+	if *first.Percentiles[fouls] != 16.7 {
+		t.Errorf("player 1 fouls percentile = %v", *first.Percentiles[fouls])
+	}
+
+	// Unqualified, player 4 is ranked but does not count for the others.
+	fourth, _ := league.Player(4)
+	if fourth.Qualified || *fourth.Values[goals] != 2 || *fourth.Percentiles[goals] != 100 {
+		t.Errorf("player 4 = %+v", fourth)
+	}
+	// The only defender is ranked against himself.
+	fifth, _ := league.Player(5)
+	if *fifth.Percentiles[goals] != 50 {
+		t.Errorf("player 5 goals percentile = %v", *fifth.Percentiles[goals])
+	}
+	if fifth.Values[index("duelSuccess")] != nil || fifth.Percentiles[index("duelSuccess")] != nil {
+		t.Error("a rate without attempts has a value")
+	}
+
+	if attackers := league.Position("Attacker"); len(attackers.Players) != 4 {
+		t.Errorf("%d attackers, want 4", len(attackers.Players))
+	}
+	comparison := league.Compare([]int{2, 6, 1})
+	if len(comparison.Players) != 2 || comparison.Players[0].Player.ID != 2 || len(comparison.Missing) != 1 || comparison.Missing[0] != 6 {
+		t.Errorf("comparison = %+v", comparison)
+	}
+	if len(comparison.Axes) != len(comparison.Players[0].Percentiles) {
+		t.Error("axes and percentiles differ in length")
+	}
+}
+
+func TestCache(t *testing.T) {
+	server := fake.New(fake.Options{DailyLimit: -1})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	client := apifootball.New("test-token", ts.URL+"/")
+
+	cache := NewCache(time.Hour)
+	league, err := cache.League(context.Background(), client, 135)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(league.Players) <= 20 {
+		t.Fatalf("%d players, want every page", len(league.Players))
+	}
+	qualified := 0
+	for _, p := range league.Players {
+		if p.Qualified {
+			qualified++
+		}
+		for i, pct := range p.Percentiles {
+			if pct != nil && (*pct < 0 || *pct > 100) {
+				t.Errorf("player %d has percentile %v for %s", p.Player.ID, *pct, axes[i].Key)
+			}
+		}
+	}
+	if qualified == 0 {
+		t.Error("no player qualified")
+	}
+
+	used := server.Used()
+	if _, err := cache.League(context.Background(), client, 135); err != nil || server.Used() != used {
+		t.Errorf("cached league cost %d requests (%v)", server.Used()-used, err)
+	}
+}
+
+func TestSeasonOf(t *testing.T) {
+	league := stat(135, 10, 900, 4, 3)
+	league["games"].(map[string]interface{})["rating"] = "7.0"
+	league["passes"] = map[string]interface{}{"accuracy": 80}
+	cup := stat(137, 10, 90, 2, 0)
+	cup["games"].(map[string]interface{})["rating"] = "9.2"
+	cup["passes"] = map[string]interface{}{"accuracy": 90}
+	// Loaned out for the Champions League group the team did not play.
+	registered := stat(2, 11, 0, 0, 0)
+	players := page(t, entry{7, "Midfielder", []map[string]interface{}{cup, league, registered}})
+
+	season, ok := SeasonOf(players)
+	if !ok {
+		t.Fatal("no season")
+	}
+	if len(season.Competitions) != 2 || season.Competitions[0].Competition.ID != 135 {
+		t.Fatalf("competitions = %+v", season.Competitions)
+	}
+	combined := season.Combined
+	if combined.Minutes != 990 || combined.Appearances != 11 || combined.Goals != 6 || combined.FoulsCommitted != 3 {
+		t.Errorf("combined = %+v", combined)
+	}
+	// 900 minutes at 7.0 and 90 at 9.2.
+	if combined.Rating == nil || *combined.Rating != 7.2 {
+		t.Errorf("combined rating = %v", combined.Rating)
+	}
+	// 10 matches at 80% and one at 90%.
+	if combined.PassAccuracy == nil || *combined.PassAccuracy != 80.9 {
+		t.Errorf("combined pass accuracy = %v", combined.PassAccuracy)
+	}
+	if cupRating := season.Competitions[1].Totals.Rating; cupRating == nil || *cupRating != 9.2 {
+		t.Errorf("cup rating = %v", cupRating)
+	}
+	if len(season.Teams) != 1 || season.Teams[0].ID != 10 {
+		t.Errorf("teams = %+v", season.Teams)
+	}
+
+	if _, ok := SeasonOf(apifootball.Players{}); ok {
+		t.Error("season of an empty response")
+	}
+	if _, ok := SeasonOf(page(t, entry{7, "Midfielder", []map[string]interface{}{registered}})); ok {
+		t.Error("season of a player without an appearance")
+	}
+}
@@ -0,0 +1,172 @@
+package players
+
+import (
+	"sort"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+// Totals are a player's numbers in one competition, or summed over all of
+// them. Rating and PassAccuracy are null when no match had one.
+type Totals struct {
+	Appearances int      `json:"appearances"`
+	Lineups     int      `json:"lineups"`
+	Minutes     int      `json:"minutes"`
+	Rating      *float64 `json:"rating"`
+	Goals       int      `json:"goals"`
+	Assists     int      `json:"assists"`
+	Shots       int      `json:"shots"`
+	ShotsOn     int      `json:"shotsOn"`
+	Passes      int      `json:"passes"`
+	KeyPasses   int      `json:"keyPasses"`
+	// PassAccuracy is the mean over the player's matches.
+	PassAccuracy    *float64 `json:"passAccuracy"`
+	Tackles         int      `json:"tackles"`
+	Interceptions   int      `json:"interceptions"`
+	Blocks          int      `json:"blocks"`
+	Duels           int      `json:"duels"`
+	DuelsWon        int      `json:"duelsWon"`
+	Dribbles        int      `json:"dribbles"`
+	DribblesWon     int      `json:"dribblesWon"`
+	FoulsDrawn      int      `json:"foulsDrawn"`
+	FoulsCommitted  int      `json:"foulsCommitted"`
+	Yellow          int      `json:"yellow"`
+	YellowRed       int      `json:"yellowRed"`
+	Red             int      `json:"red"`
+	PenaltiesScored int      `json:"penaltiesScored"`
+	PenaltiesMissed int      `json:"penaltiesMissed"`
+	Saves           int      `json:"saves"`
+	Conceded        int      `json:"conceded"`
+
+	// The weights of the means: the minutes with a rating and the
+	// appearances with a pass accuracy.
+	ratingMinutes int
+	ratingSum     float64
+	accuracyApps  int
+	accuracySum   float64
+}
+
+// add sums an entry in. The provider's rating is the mean of the player's
+// matches, so ratings are weighted by minutes played: a 90 minute match
+// counts more than a cameo. Pass accuracy is weighted by appearances.
+func (t *Totals) add(s apifootball.Statistic) {
+	t.Appearances += s.Games.Appearences.Int
+	t.Lineups += s.Games.Lineups.Int
+	t.Minutes += s.Games.Minutes.Int
+	if s.Games.Rating.Valid && s.Games.Minutes.Int > 0 {
+		t.ratingSum += s.Games.Rating.Float * float64(s.Games.Minutes.Int)
+		t.ratingMinutes += s.Games.Minutes.Int
+	}
+	t.Goals += s.Goals.Total.Int
+	t.Assists += s.Goals.Assists.Int
+	t.Shots += s.Shots.Total.Int
+	t.ShotsOn += s.Shots.On.Int
+	t.Passes += s.Passes.Total.Int
+	t.KeyPasses += s.Passes.Key.Int
+	if s.Passes.Accuracy.Valid && s.Games.Appearences.Int > 0 {
+		t.accuracySum += float64(s.Passes.Accuracy.Int * s.Games.Appearences.Int)
+		t.accuracyApps += s.Games.Appearences.Int
+	}
+	t.Tackles += s.Tackles.Total.Int
+	t.Interceptions += s.Tackles.Interceptions.Int
+	t.Blocks += s.Tackles.Blocks.Int
+	t.Duels += s.Duels.Total.Int
+	t.DuelsWon += s.Duels.Won.Int
+	t.Dribbles += s.Dribbles.Attempts.Int
+	t.DribblesWon += s.Dribbles.Success.Int
+	t.FoulsDrawn += s.Fouls.Drawn.Int
+	t.FoulsCommitted += s.Fouls.Committed.Int
+	t.Yellow += s.Cards.Yellow
+	t.YellowRed += s.Cards.Yellowred
+	t.Red += s.Cards.Red
+	t.PenaltiesScored += s.Penalty.Scored.Int
+	t.PenaltiesMissed += s.Penalty.Missed.Int
+	t.Saves += s.Goals.Saves.Int
+	t.Conceded += s.Goals.Conceded.Int
+	t.means()
+}
+
+func (t *Totals) means() {
+	t.Rating, t.PassAccuracy = nil, nil
+	if t.ratingMinutes > 0 {
+		rating := round(t.ratingSum/float64(t.ratingMinutes), 2)
+		t.Rating = &rating
+	}
+	if t.accuracyApps > 0 {
+		accuracy := round(t.accuracySum/float64(t.accuracyApps), 1)
+		t.PassAccuracy = &accuracy
+	}
+}
+
+type Competition struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Country string `json:"country"`
+	Logo    string `json:"logo"`
+	Season  int    `json:"season"`
+}
+
+// Entry is a player's season in one competition for one team.
+type Entry struct {
+	Competition Competition `json:"competition"`
+	Team        Team        `json:"team"`
+	Position    string      `json:"position"`
+	Totals      Totals      `json:"totals"`
+}
+
+// Season is a player's season, per competition and over all of them. The
+// provider has one statistics entry per competition and team; they are
+// summed into Combined.
+type Season struct {
+	Player       Player  `json:"player"`
+	Season       int     `json:"season"`
+	Competitions []Entry `json:"competitions"`
+	Combined     Totals  `json:"combined"`
+	// Teams are the player's teams of the season, the one they played
+	// most for first.
+	Teams []Team `json:"teams"`
+}
+
+// SeasonOf aggregates the entries of a players response for one player.
+// It reports false when the player has no appearance in any competition.
+func SeasonOf(players apifootball.Players) (Season, bool) {
+	if len(players.Response) == 0 {
+		return Season{}, false
+	}
+	p := players.Response[0]
+	season := Season{
+		Player:       Player{p.Player.ID, p.Player.Name, p.Player.Age, p.Player.Nationality, p.Player.Photo},
+		Competitions: []Entry{},
+		Teams:        []Team{},
+	}
+	minutes := map[int]int{}
+	for _, s := range p.Statistics {
+		var totals Totals
+		totals.add(s)
+		// Entries of competitions the player was only registered for carry
+		// no appearance and would only clutter the list.
+		if s.Games.Appearences.Int == 0 && s.Games.Minutes.Int == 0 {
+			continue
+		}
+		season.Season = s.League.Season
+		season.Combined.add(s)
+		team := Team{s.Team.ID, s.Team.Name, s.Team.Logo}
+		if _, ok := minutes[team.ID]; !ok {
+			season.Teams = append(season.Teams, team)
+		}
+		minutes[team.ID] += s.Games.Minutes.Int
+		season.Competitions = append(season.Competitions, Entry{
+			Competition: Competition{s.League.ID, s.League.Name, s.League.Country, s.League.Logo, s.League.Season},
+			Team:        team,
+			Position:    s.Games.Position,
+			Totals:      totals,
+		})
+	}
+	sort.SliceStable(season.Competitions, func(i, j int) bool {
+		return season.Competitions[i].Totals.Minutes > season.Competitions[j].Totals.Minutes
+	})
+	sort.SliceStable(season.Teams, func(i, j int) bool {
+		return minutes[season.Teams[i].ID] > minutes[season.Teams[j].ID]
+	})
+	return season, len(season.Competitions) > 0
+}
@@ -0,0 +1,288 @@
+// Package ratings keeps Elo ratings of teams across competitions, so clubs
+// of different leagues and cups can be compared on one scale.
+package ratings
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+type Params struct {
+	// Initial is the rating of a team before its first match.
+	Initial float64 `json:"initial"`
+	// K is the weight of a single match.
+	K float64 `json:"k"`
+	// HomeAdvantage is added to the home team's rating when computing the
+	// expected result.
+	HomeAdvantage float64 `json:"homeAdvantage"`
+}
+
+// DefaultParams follow the World Football Elo ratings, with a club-sized
+// home advantage.
+var DefaultParams = Params{Initial: 1500, K: 20, HomeAdvantage: 65}
+
+type Team struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+type League struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Match is a finished fixture as far as ratings are concerned.
+type Match struct {
+	FixtureID int       `json:"fixtureId"`
+	Date      time.Time `json:"date"`
+	League    League    `json:"league"`
+	Home      Team      `json:"home"`
+	Away      Team      `json:"away"`
+	HomeGoals int       `json:"homeGoals"`
+	AwayGoals int       `json:"awayGoals"`
+}
+
+// Change is the effect of one match on a team's rating.
+type Change struct {
+	FixtureID    int       `json:"fixtureId"`
+	Date         time.Time `json:"date"`
+	League       League    `json:"league"`
+	Opponent     Team      `json:"opponent"`
+	Home         bool      `json:"home"`
+	GoalsFor     int       `json:"goalsFor"`
+	GoalsAgainst int       `json:"goalsAgainst"`
+	// Expected is the expected result, 1 for a certain win.
+	Expected float64 `json:"expected"`
+	Before   float64 `json:"before"`
+	After    float64 `json:"after"`
+}
+
+type TeamHistory struct {
+	Team    Team     `json:"team"`
+	Rating  float64  `json:"rating"`
+	History []Change `json:"history"`
+}
+
+type Rating struct {
+	Rank    int       `json:"rank"`
+	Team    Team      `json:"team"`
+	Rating  float64   `json:"rating"`
+	Played  int       `json:"played"`
+	Updated time.Time `json:"updated"`
+}
+
+// Engine rates teams by playing their matches in chronological order.
+type Engine struct {
+	mu      sync.RWMutex
+	params  Params
+	matches []Match
+	// seen is the rated match of each fixture id.
+	seen  map[int]Match
+	teams map[int]*TeamHistory
+}
+
+func New(params Params) *Engine {
+	return &Engine{params: params, seen: map[int]Match{}, teams: map[int]*TeamHistory{}}
+}
+
+// Add rates the finished fixtures not seen before, or seen with another
+// score, and returns how many were added. Fixtures played before the last
+// rated match, such as a postponed match with a newly known result, and
+// corrected scores make the engine replay every match.
+func (e *Engine) Add(fixtures []apifootball.Fixture) int {
+	var added []Match
+	e.mu.RLock()
+	for _, fixture := range fixtures {
+		if !standings.Finished(fixture) {
+			continue
+		}
+		match := matchOf(fixture)
+		if rated, ok := e.seen[match.FixtureID]; ok && sameResult(rated, match) {
+			continue
+		}
+		added = append(added, match)
+	}
+	e.mu.RUnlock()
+	if len(added) == 0 {
+		return 0
+	}
+	e.add(added)
+	return len(added)
+}
+
+func sameResult(a Match, b Match) bool {
+	return a.HomeGoals == b.HomeGoals && a.AwayGoals == b.AwayGoals
+}
+
+func (e *Engine) add(added []Match) {
+	sortMatches(added)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	replay := len(e.matches) > 0 && before(added[0], e.matches[len(e.matches)-1])
+	for _, match := range added {
+		if _, ok := e.seen[match.FixtureID]; ok {
+			replay = true
+		}
+	}
+	for _, match := range added {
+		rated, ok := e.seen[match.FixtureID]
+		if ok && sameResult(rated, match) {
+			continue
+		}
+		e.seen[match.FixtureID] = match
+		if ok {
+			for i := range e.matches {
+				if e.matches[i].FixtureID == match.FixtureID {
+					e.matches[i] = match
+				}
+			}
+			continue
+		}
+		e.matches = append(e.matches, match)
+		if !replay {
+			e.play(match)
+		}
+	}
+	if replay {
+		sortMatches(e.matches)
+		e.teams = map[int]*TeamHistory{}
+		for _, match := range e.matches {
+			e.play(match)
+		}
+	}
+}
+
+func matchOf(fixture apifootball.Fixture) Match {
+	return Match{
+		FixtureID: fixture.Fixture.ID,
+		Date:      fixture.Fixture.Date,
+		League:    League{fixture.League.ID, fixture.League.Name},
+		Home:      Team{fixture.Teams.Home.ID, fixture.Teams.Home.Name, fixture.Teams.Home.Logo},
+		Away:      Team{fixture.Teams.Away.ID, fixture.Teams.Away.Name, fixture.Teams.Away.Logo},
+		HomeGoals: fixture.Goals.Home.Int,
+		AwayGoals: fixture.Goals.Away.Int,
+	}
+}
+
+func before(a Match, b Match) bool {
+	if !a.Date.Equal(b.Date) {
+		return a.Date.Before(b.Date)
+	}
+	return a.FixtureID < b.FixtureID
+}
+
+func sortMatches(matches []Match) {
+	sort.Slice(matches, func(i, j int) bool { return before(matches[i], matches[j]) })
+}
+
+func (e *Engine) team(team Team) *TeamHistory {
+	history, ok := e.teams[team.ID]
+	if !ok {
+		history = &TeamHistory{Team: team, Rating: e.params.Initial}
+		e.teams[team.ID] = history
+	}
+	history.Team = team
+	return history
+}
+
+func (e *Engine) play(match Match) {
+	home, away := e.team(match.Home), e.team(match.Away)
+	expected := Expected(home.Rating+e.params.HomeAdvantage, away.Rating)
+	result := 0.5
+	switch {
+	case match.HomeGoals > match.AwayGoals:
+		result = 1
+	case match.HomeGoals < match.AwayGoals:
+		result = 0
+	}
+	delta := e.params.K * Margin(match.HomeGoals-match.AwayGoals) * (result - expected)
+
+	home.History = append(home.History, Change{
+		FixtureID: match.FixtureID, Date: match.Date, League: match.League, Opponent: match.Away, Home: true,
+		GoalsFor: match.HomeGoals, GoalsAgainst: match.AwayGoals,
+		Expected: expected, Before: home.Rating, After: home.Rating + delta,
+	})
+	away.History = append(away.History, Change{
+		FixtureID: match.FixtureID, Date: match.Date, League: match.League, Opponent: match.Home,
+		GoalsFor: match.AwayGoals, GoalsAgainst: match.HomeGoals,
+		Expected: 1 - expected, Before: away.Rating, After: away.Rating - delta,
+	})
+	home.Rating += delta
+	away.Rating -= delta
+}
+
+// Expected is the expected result of a team rated a against one rated b.
+func Expected(a float64, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// Margin weighs a win by its goal difference: 1 up to one goal, 1.5 for
+// two and (11+n)/8 for n goals from three.
+func Margin(diff int) float64 {
+	if diff < 0 {
+		diff = -diff
+	}
+	switch {
+	case diff <= 1:
+		return 1
+	case diff == 2:
+		return 1.5
+	}
+	return (11 + float64(diff)) / 8
+}
+
+// Ratings lists the teams from the highest rated. With leagueId other than
+// zero, only teams with a match in that league are listed.
+func (e *Engine) Ratings(leagueId int) []Rating {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	ratings := make([]Rating, 0, len(e.teams))
+	for _, team := range e.teams {
+		if leagueId != 0 && !playedIn(team, leagueId) {
+			continue
+		}
+		rating := Rating{Team: team.Team, Rating: team.Rating, Played: len(team.History)}
+		if n := len(team.History); n > 0 {
+			rating.Updated = team.History[n-1].Date
+		}
+		ratings = append(ratings, rating)
+	}
+	sort.Slice(ratings, func(i, j int) bool {
+		if ratings[i].Rating != ratings[j].Rating {
+			return ratings[i].Rating > ratings[j].Rating
+		}
+		return ratings[i].Team.Name < ratings[j].Team.Name
+	})
+	for i := range ratings {
+		ratings[i].Rank = i + 1
+	}
+	return ratings
+}
+
+func playedIn(team *TeamHistory, leagueId int) bool {
+	for _, change := range team.History {
+		if change.League.ID == leagueId {
+			return true
+		}
+	}
+	return false
+}
+
+// History returns the rating history of a team, oldest match first.
+func (e *Engine) History(teamId int) (TeamHistory, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	team, ok := e.teams[teamId]
+	if !ok {
+		return TeamHistory{}, false
+	}
+	history := *team
+	history.History = append([]Change(nil), team.History...)
+	return history, true
+}
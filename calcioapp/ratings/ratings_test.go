@@ -0,0 +1,196 @@
+package ratings
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+	"github.com/nero-15/calcio-app/internal/fixturetest"
+)
+
+func fixture(id int, day int, home int, away int, homeGoals int, awayGoals int) apifootball.Fixture {
+	return fixturetest.Finished(id, fixturetest.Date(2021, time.August, day), home, away, homeGoals, awayGoals)
+}
+
+func near(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestMatch(t *testing.T) {
+	if got := Expected(1600, 1600); !near(got, 0.5) {
+		t.Errorf("Expected(1600, 1600) = %f", got)
+	}
+	if got := Expected(1900, 1500); !near(got, 1/(1+math.Pow(10, -1))) {
+		t.Errorf("Expected(1900, 1500) = %f", got)
+	}
+	for diff, want := range map[int]float64{0: 1, 1: 1, -2: 1.5, 3: 1.75, 5: 2} {
+		if got := Margin(diff); !near(got, want) {
+			t.Errorf("Margin(%d) = %f, want %f", diff, got, want)
+		}
+	}
+
+	e := New(DefaultParams)
+	e.Add([]apifootball.Fixture{fixture(1, 1, 1, 2, 1, 1)})
+	home, _ := e.History(1)
+	away, _ := e.History(2)
+	if home.Rating >= 1500 || !near(home.Rating+away.Rating, 3000) {
+		t.Errorf("a home draw between equals gave %f and %f", home.Rating, away.Rating)
+	}
+
+	e = New(DefaultParams)
+	e.Add([]apifootball.Fixture{fixture(1, 1, 1, 2, 4, 0)})
+	big, _ := e.History(1)
+	e = New(DefaultParams)
+	e.Add([]apifootball.Fixture{fixture(1, 1, 1, 2, 1, 0)})
+	small, _ := e.History(1)
+	if big.Rating <= small.Rating {
+		t.Errorf("4-0 gave %f, 1-0 gave %f", big.Rating, small.Rating)
+	}
+}
+
+func TestLateResultReplays(t *testing.T) {
+	fixtures := []apifootball.Fixture{
+		fixture(1, 1, 1, 2, 2, 0),
+		fixture(2, 8, 2, 3, 1, 1),
+		fixture(3, 15, 3, 1, 0, 3),
+		fixture(4, 22, 1, 3, 1, 2),
+	}
+	inOrder := New(DefaultParams)
+	inOrder.Add(fixtures)
+
+	late := New(DefaultParams)
+	late.Add([]apifootball.Fixture{fixtures[0], fixtures[2], fixtures[3]})
+	if added := late.Add(fixtures); added != 1 {
+		t.Errorf("added %d fixtures, want 1", added)
+	}
+	if !reflect.DeepEqual(inOrder.Ratings(0), late.Ratings(0)) {
+		t.Errorf("ratings after a late result differ:\n%+v\n%+v", inOrder.Ratings(0), late.Ratings(0))
+	}
+	history, _ := late.History(2)
+	if len(history.History) != 2 || history.History[1].FixtureID != 2 {
+		t.Errorf("history = %+v", history.History)
+	}
+}
+
+func TestCorrectedScoreReplays(t *testing.T) {
+	fixtures := []apifootball.Fixture{fixture(1, 1, 1, 2, 2, 0), fixture(2, 8, 2, 3, 1, 1)}
+	e := New(DefaultParams)
+	e.Add(fixtures)
+
+	// The first result is corrected to an away win.
+	corrected := []apifootball.Fixture{fixture(1, 1, 1, 2, 0, 1), fixtures[1]}
+	if added := e.Add(corrected); added != 1 {
+		t.Errorf("added %d fixtures, want the corrected one", added)
+	}
+	want := New(DefaultParams)
+	want.Add(corrected)
+	if !reflect.DeepEqual(e.Ratings(0), want.Ratings(0)) {
+		t.Errorf("ratings after a correction differ:\n%+v\n%+v", e.Ratings(0), want.Ratings(0))
+	}
+	if history, _ := e.History(1); len(history.History) != 1 || history.History[0].GoalsFor != 0 {
+		t.Errorf("history = %+v", history.History)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	e := New(DefaultParams)
+	e.Add([]apifootball.Fixture{fixture(1, 1, 1, 2, 2, 0), fixture(2, 8, 2, 3, 1, 1)})
+	file := filepath.Join(t.TempDir(), "ratings", "ratings.json")
+	if err := e.Save(file); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(file, DefaultParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(e.Ratings(0), loaded.Ratings(0)) {
+		t.Errorf("loaded ratings differ:\n%+v\n%+v", e.Ratings(0), loaded.Ratings(0))
+	}
+	if added := loaded.Add([]apifootball.Fixture{fixture(2, 8, 2, 3, 1, 1)}); added != 0 {
+		t.Errorf("a saved fixture was added again")
+	}
+
+	params := DefaultParams
+	params.K = 40
+	rerated, err := Load(file, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history, _ := rerated.History(1); !near(history.Rating-1500, 2*(e.Ratings(0)[0].Rating-1500)) {
+		t.Errorf("loading with another K did not rate again: %f", history.Rating)
+	}
+
+	empty, err := Load(filepath.Join(t.TempDir(), "missing.json"), DefaultParams)
+	if err != nil || len(empty.Ratings(0)) != 0 {
+		t.Errorf("missing file gave %v, %v", empty.Ratings(0), err)
+	}
+}
+
+func TestUpdaterOutage(t *testing.T) {
+	calls := 0
+	updater := &Updater{Engine: New(DefaultParams), Leagues: []int{135}, Interval: time.Hour,
+		Fetch: func(ctx context.Context, leagueId int) ([]apifootball.Fixture, error) {
+			calls++
+			if ctx.Err() != nil {
+				t.Error("the update was cancelled with the request")
+			}
+			return nil, errors.New("503")
+		}}
+	// A cancelled request does not cancel the update it starts.
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	updater.Update(cancelled)
+	updater.Update(context.Background())
+	if calls != 1 {
+		t.Errorf("fetched %d times within the interval while the provider is down", calls)
+	}
+}
+
+func TestUpdater(t *testing.T) {
+	server := httptest.NewServer(fake.New(fake.Options{DailyLimit: -1}))
+	defer server.Close()
+	client := apifootball.New("test-token", server.URL+"/")
+	file := filepath.Join(t.TempDir(), "ratings.json")
+	updater := &Updater{Engine: New(DefaultParams), Leagues: []int{135, 137}, File: file, Interval: time.Hour, Fetch: FixturesFrom(client)}
+	updater.Update(context.Background())
+
+	ratings := updater.Engine.Ratings(0)
+	if len(ratings) != 20 {
+		t.Fatalf("rated %d teams, want 20", len(ratings))
+	}
+	sum := 0.0
+	for _, rating := range ratings {
+		sum += rating.Rating
+	}
+	if !near(sum, 20*1500) {
+		t.Errorf("ratings sum to %f, want %d", sum, 20*1500)
+	}
+	cup := 0
+	for _, rating := range updater.Engine.Ratings(0) {
+		history, _ := updater.Engine.History(rating.Team.ID)
+		for _, change := range history.History {
+			if change.League.ID == 137 {
+				cup++
+			}
+		}
+	}
+	if cup == 0 {
+		t.Error("no cup match rated")
+	}
+	if len(updater.Engine.Ratings(137)) >= len(ratings) {
+		t.Error("league filter kept teams without a cup match")
+	}
+
+	loaded, err := Load(file, DefaultParams)
+	if err != nil || !reflect.DeepEqual(loaded.Ratings(0), ratings) {
+		t.Errorf("saved ratings differ: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package ratings
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// FetchFixtures returns the fixtures of a competition.
+type FetchFixtures func(ctx context.Context, leagueId int) ([]apifootball.Fixture, error)
+
+// FixturesFrom fetches the season's fixtures of a competition from client.
+func FixturesFrom(client *apifootball.APIClient) FetchFixtures {
+	return func(ctx context.Context, leagueId int) ([]apifootball.Fixture, error) {
+		fixtures, err := client.WithContext(ctx).GetFixturesByLeagueId(strconv.Itoa(leagueId))
+		return fixtures.Response, err
+	}
+}
+
+// updateTimeout bounds an update, which no longer stops with the request
+// that started it.
+const updateTimeout = time.Minute
+
+// Updater feeds an engine with the fixtures of several competitions, at
+// most once per interval, and saves it after new results.
+type Updater struct {
+	Engine   *Engine
+	Leagues  []int
+	File     string
+	Interval time.Duration
+	Fetch    FetchFixtures
+
+	mu        sync.Mutex
+	attempted time.Time
+}
+
+// Update fetches every competition concurrently unless it was tried less
+// than Interval ago, successfully or not, so that a provider outage costs
+// one attempt per Interval. The fetch keeps the request id of ctx but not
+// its cancellation. A competition failing to load is logged and skipped;
+// its matches are rated on a later update.
+func (u *Updater) Update(ctx context.Context) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !u.attempted.IsZero() && time.Since(u.attempted) < u.Interval {
+		return
+	}
+	u.attempted = time.Now()
+	ctx, cancel := context.WithTimeout(logging.Detach(ctx), updateTimeout)
+	defer cancel()
+
+	results := make([][]apifootball.Fixture, len(u.Leagues))
+	var wg sync.WaitGroup
+	for i, leagueId := range u.Leagues {
+		wg.Add(1)
+		go func(i int, leagueId int) {
+			defer wg.Done()
+			fixtures, err := u.Fetch(ctx, leagueId)
+			if err != nil {
+				logging.FromContext(ctx).WithError(err).WithField("league", leagueId).Warn("ratings: fixtures not loaded")
+				return
+			}
+			results[i] = fixtures
+		}(i, leagueId)
+	}
+	wg.Wait()
+
+	var fixtures []apifootball.Fixture
+	for _, result := range results {
+		fixtures = append(fixtures, result...)
+	}
+	if added := u.Engine.Add(fixtures); added > 0 && u.File != "" {
+		if err := u.Engine.Save(u.File); err != nil {
+			logging.FromContext(ctx).WithError(err).Error("ratings: not saved")
+		}
+		logging.FromContext(ctx).WithFields(logrus.Fields{"matches": added}).Info("ratings updated")
+	}
+}
@@ -0,0 +1,80 @@
+package ratings
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// state is what is written to disk: the matches rated and the history they
+// produced, so a restart does not cost a request per competition.
+type state struct {
+	Params  Params        `json:"params"`
+	Matches []Match       `json:"matches"`
+	Teams   []TeamHistory `json:"teams"`
+}
+
+// Save writes the engine to file, replacing it atomically.
+func (e *Engine) Save(file string) error {
+	e.mu.RLock()
+	s := state{Params: e.params, Matches: e.matches}
+	for _, team := range e.teams {
+		s.Teams = append(s.Teams, *team)
+	}
+	out, err := json.Marshal(s)
+	e.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(out); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// Load reads an engine saved to file. A missing file gives an empty engine.
+// When params differ from the saved ones the matches are rated again.
+func Load(file string, params Params) (*Engine, error) {
+	e := New(params)
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return e, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s state
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("ratings: %s: %v", file, err)
+	}
+	if len(s.Matches) == 0 {
+		return e, nil
+	}
+	if s.Params != params {
+		e.add(s.Matches)
+		return e, nil
+	}
+	e.matches = s.Matches
+	for _, match := range s.Matches {
+		e.seen[match.FixtureID] = match
+	}
+	for i := range s.Teams {
+		e.teams[s.Teams[i].Team.ID] = &s.Teams[i]
+	}
+	return e, nil
+}
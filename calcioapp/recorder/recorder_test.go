@@ -0,0 +1,61 @@
+package recorder
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordScrubsCredentialsAndReplays(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-apisports-key") != "secret-token" {
+			t.Errorf("provider did not receive the real token")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":1,"response":{"requests":{"current":3}}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	get := func(transport *Transport) string {
+		req, _ := http.NewRequest("GET", server.URL+"/status?b=2&a=1", nil)
+		req.Header.Set("x-apisports-key", "secret-token")
+		resp, err := transport.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d", resp.StatusCode)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	recorded := get(New(dir, Record))
+	golden, err := ioutil.ReadFile(filepath.Join(dir, "GET_status_a=1_b=2.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(golden), "secret-token") {
+		t.Errorf("golden file contains the api token:\n%s", golden)
+	}
+	if !strings.Contains(string(golden), redacted) {
+		t.Errorf("golden file does not mark the scrubbed header:\n%s", golden)
+	}
+
+	server.Close()
+	if replayed := get(New(dir, Replay)); replayed != recorded {
+		t.Errorf("replayed body = %s, want %s", replayed, recorded)
+	}
+}
+
+func TestReplayUnknownRequest(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://v3.football.api-sports.io/standings?league=1", nil)
+	if _, err := New(t.TempDir(), Replay).RoundTrip(req); err == nil {
+		t.Error("expected an error for a request without recording")
+	}
+}
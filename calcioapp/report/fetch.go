@@ -0,0 +1,70 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+// ErrNotFound is returned for an unknown fixture.
+var ErrNotFound = errors.New("report: fixture not found")
+
+// Fetch collects the parts of a fixture's report: the fixture, then the
+// statistics, events, lineups and players of both teams concurrently.
+func Fetch(ctx context.Context, client *apifootball.APIClient, fixtureId int) (Parts, error) {
+	client = client.WithContext(ctx)
+	id := strconv.Itoa(fixtureId)
+	fixtures, err := client.GetFixtureByFixtureId(id)
+	if err != nil {
+		return Parts{}, err
+	}
+	if fixtures.Results == 0 {
+		return Parts{}, ErrNotFound
+	}
+
+	parts := Parts{Fixture: fixtures.Response[0]}
+	teams := [2]string{
+		strconv.Itoa(parts.Fixture.Teams.Home.ID),
+		strconv.Itoa(parts.Fixture.Teams.Away.ID),
+	}
+	var errs [8]error
+	var wg sync.WaitGroup
+	for side, team := range teams {
+		side, team := side, team
+		requests := []func() error{
+			func() (err error) {
+				parts.Statistics[side], err = client.GetStatisticsByTeamIdAndFixtureId(team, id)
+				return
+			},
+			func() (err error) {
+				parts.Events[side], err = client.GetEventsByTeamIdAndFixtureId(team, id)
+				return
+			},
+			func() (err error) {
+				parts.Lineups[side], err = client.GetLineupsByTeamIdAndFixtureId(team, id)
+				return
+			},
+			func() (err error) {
+				parts.Players[side], err = client.GetPlayersByTeamIdAndFixtureId(team, id)
+				return
+			},
+		}
+		for i, request := range requests {
+			wg.Add(1)
+			go func(i int, request func() error) {
+				defer wg.Done()
+				errs[i] = request()
+			}(side*len(requests)+i, request)
+		}
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return Parts{}, err
+		}
+	}
+	return parts, nil
+}
@@ -0,0 +1,154 @@
+package report
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/media"
+)
+
+// value prints a statistic as the provider sends it, "-" when missing.
+func value(v apifootball.StatisticValue) string {
+	if !v.Valid {
+		return "-"
+	}
+	s := strconv.FormatFloat(v.Value, 'f', -1, 64)
+	if v.Percent {
+		s += "%"
+	}
+	return s
+}
+
+// cell escapes the pipes of a Markdown table cell.
+func cell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+var funcs = map[string]interface{}{
+	"value": value,
+	"cell":  cell,
+	"date":  func(r Report) string { return r.Date.UTC().Format("Monday 2 January 2006, 15:04 UTC") },
+}
+
+var markdown = template.Must(template.New("markdown").Funcs(funcs).Parse(`# {{.Home.Team.Name}} {{.Score}} {{.Away.Team.Name}}
+
+{{.Competition.Name}}{{with .Competition.Round}}, {{.}}{{end}} · {{date .}}{{with .Venue}} · {{.}}{{end}}{{with .City}}, {{.}}{{end}}
+
+Status: {{.Status}}{{with .Halftime}} · Half time: {{.}}{{end}}{{with .Penalties}} · Penalties: {{.}}{{end}}{{with .Referee}} · Referee: {{.}}{{end}}
+{{with .ManOfTheMatch}}
+**Man of the match:** {{.Player.Name}} ({{.Team.Name}}), rating {{.Rating}}
+{{end}}{{if .Timeline}}
+## Timeline
+
+| Minute | Team | Event | Player | |
+|---|---|---|---|---|
+{{range .Timeline}}| {{.Clock}} | {{cell .Team.Name}} | {{cell .Detail}} | {{cell .Player}} | {{cell .Assist}} |
+{{end}}{{end}}{{if .Statistics}}
+## Statistics
+
+| {{cell .Home.Team.Name}} | | {{cell .Away.Team.Name}} |
+|--:|:-:|:--|
+{{range .Statistics}}| {{value .Home}} | {{cell .Type}} | {{value .Away}} |
+{{end}}{{end}}{{if or .Home.StartXI .Away.StartXI}}
+## Lineups
+{{range .Sides}}
+### {{.Team.Name}}{{with .Formation}} ({{.}}){{end}}
+
+{{range .StartXI}}- {{.Number}} {{.Name}} ({{.Position}})
+{{end}}{{with .Coach}}
+Coach: {{.}}
+{{end}}{{end}}{{end}}`))
+
+var html = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Home.Team.Name}} {{.Score}} {{.Away.Team.Name}}</title>
+<style>
+body { font-family: sans-serif; max-width: 48rem; margin: 2rem auto; }
+header { text-align: center; }
+header img { height: 3rem; vertical-align: middle; }
+table { width: 100%; border-collapse: collapse; margin-bottom: 1.5rem; }
+td, th { padding: .25rem .5rem; border-bottom: 1px solid #ddd; }
+.statistics td:first-child { text-align: right; }
+.statistics td:nth-child(2) { text-align: center; color: #555; }
+.lineups { display: flex; gap: 2rem; }
+.lineups section { flex: 1; }
+</style>
+</head>
+<body>
+<header>
+<p>{{.Competition.Name}}{{with .Competition.Round}}, {{.}}{{end}} · {{date .}}</p>
+<h1><img src="{{.Home.Team.Logo}}" alt=""> {{.Home.Team.Name}} {{.Score}} {{.Away.Team.Name}} <img src="{{.Away.Team.Logo}}" alt=""></h1>
+<p>{{.Status}}{{with .Halftime}} · Half time {{.}}{{end}}{{with .Penalties}} · Penalties {{.}}{{end}}</p>
+<p>{{with .Venue}}{{.}}{{end}}{{with .City}}, {{.}}{{end}}{{with .Referee}} · Referee {{.}}{{end}}</p>
+</header>
+{{with .ManOfTheMatch}}<p class="motm"><strong>Man of the match:</strong> {{.Player.Name}} ({{.Team.Name}}), rating {{.Rating}}</p>
+{{end}}{{if .Timeline}}<h2>Timeline</h2>
+<table class="timeline">
+{{range .Timeline}}<tr class="{{.Side}}"><td>{{.Clock}}</td><td>{{.Team.Name}}</td><td>{{.Detail}}</td><td>{{.Player}}</td><td>{{.Assist}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Statistics}}<h2>Statistics</h2>
+<table class="statistics">
+<tr><th>{{.Home.Team.Name}}</th><th></th><th>{{.Away.Team.Name}}</th></tr>
+{{range .Statistics}}<tr><td>{{value .Home}}</td><td>{{.Type}}</td><td>{{value .Away}}</td></tr>
+{{end}}</table>
+{{end}}{{if or .Home.StartXI .Away.StartXI}}<h2>Lineups</h2>
+<div class="lineups">
+{{range .Sides}}<section>
+<h3>{{.Team.Name}}{{with .Formation}} ({{.}}){{end}}</h3>
+<ol>
+{{range .StartXI}}<li>{{.Number}} {{.Name}} ({{.Position}})</li>
+{{end}}</ol>
+{{with .Coach}}<p>Coach: {{.}}</p>{{end}}
+</section>
+{{end}}</div>
+{{end}}</body>
+</html>
+`))
+
+// Sides are the home and the away side, for templates.
+func (r Report) Sides() []Side {
+	return []Side{r.Home, r.Away}
+}
+
+// Markdown writes the report as a Markdown document.
+func Markdown(w io.Writer, r Report) error {
+	return markdown.Execute(w, r)
+}
+
+// HTML writes the report as a standalone HTML page.
+func HTML(w io.Writer, r Report) error {
+	return html.Execute(w, r)
+}
+
+// Formats a report is rendered in, with their content types.
+var Formats = map[string]string{
+	"json":     "application/json; charset=UTF-8",
+	"markdown": "text/markdown; charset=UTF-8",
+	"html":     "text/html; charset=UTF-8",
+}
+
+// Negotiate picks the format of a request: the format query parameter if
+// any, else the one the Accept header prefers among those the report is
+// rendered in, else JSON. It returns false for an unknown format parameter.
+func Negotiate(format string, accept string) (string, bool) {
+	if format != "" {
+		if format == "md" {
+			format = "markdown"
+		}
+		_, ok := Formats[format]
+		return format, ok
+	}
+	switch media.Negotiate(accept, "application/json", "text/markdown", "text/html") {
+	case "text/markdown":
+		return "markdown", true
+	case "text/html":
+		return "html", true
+	}
+	return "json", true
+}
@@ -0,0 +1,266 @@
+// Package report builds match reports from the per team fixture endpoints:
+// statistics side by side, a timeline of events, the lineups and a man of
+// the match, rendered as JSON, Markdown or HTML.
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+type Team struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+type Competition struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Logo   string `json:"logo"`
+	Season int    `json:"season"`
+	Round  string `json:"round"`
+}
+
+type Player struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Number   int    `json:"number"`
+	Position string `json:"position"`
+}
+
+// Side is a team of the match with its lineup, empty until the provider
+// publishes it.
+type Side struct {
+	Team        Team     `json:"team"`
+	Goals       *int     `json:"goals"`
+	Formation   string   `json:"formation"`
+	Coach       string   `json:"coach"`
+	StartXI     []Player `json:"startXI"`
+	Substitutes []Player `json:"substitutes"`
+}
+
+// Row is one statistic of both teams; a value is null when the provider
+// has none for a team.
+type Row struct {
+	Type string                     `json:"type"`
+	Home apifootball.StatisticValue `json:"home"`
+	Away apifootball.StatisticValue `json:"away"`
+}
+
+// Event is a goal, card, substitution or VAR decision of the timeline.
+type Event struct {
+	Minute int    `json:"minute"`
+	Extra  int    `json:"extra"`
+	Side   string `json:"side"`
+	Team   Team   `json:"team"`
+	Player string `json:"player"`
+	// Assist is the assisting player of a goal or the player coming on in
+	// a substitution.
+	Assist   string `json:"assist"`
+	Type     string `json:"type"`
+	Detail   string `json:"detail"`
+	Comments string `json:"comments"`
+}
+
+// Clock is the minute of the event as shown on a scoreboard, e.g. 45+2'.
+func (e Event) Clock() string {
+	if e.Extra > 0 {
+		return fmt.Sprintf("%d+%d'", e.Minute, e.Extra)
+	}
+	return fmt.Sprintf("%d'", e.Minute)
+}
+
+type Rated struct {
+	Player  Player  `json:"player"`
+	Photo   string  `json:"photo"`
+	Team    Team    `json:"team"`
+	Rating  float64 `json:"rating"`
+	Minutes int     `json:"minutes"`
+	Goals   int     `json:"goals"`
+	Assists int     `json:"assists"`
+}
+
+type Report struct {
+	FixtureID   int         `json:"fixtureId"`
+	Date        time.Time   `json:"date"`
+	Status      string      `json:"status"`
+	Competition Competition `json:"competition"`
+	Venue       string      `json:"venue"`
+	City        string      `json:"city"`
+	Referee     string      `json:"referee"`
+	Home        Side        `json:"home"`
+	Away        Side        `json:"away"`
+	Halftime    string      `json:"halftime"`
+	Penalties   string      `json:"penalties"`
+	Statistics  []Row       `json:"statistics"`
+	Timeline    []Event     `json:"timeline"`
+	// ManOfTheMatch is the best rated player, null before the provider
+	// rates the match.
+	ManOfTheMatch *Rated `json:"manOfTheMatch"`
+}
+
+// Score is the result on the scoreboard, e.g. "2 - 1", or "vs" before
+// kick-off.
+func (r Report) Score() string {
+	if r.Home.Goals == nil || r.Away.Goals == nil {
+		return "vs"
+	}
+	return fmt.Sprintf("%d - %d", *r.Home.Goals, *r.Away.Goals)
+}
+
+// Parts are the per team responses a report is built from, home team
+// first in each pair.
+type Parts struct {
+	Fixture    apifootball.Fixture
+	Statistics [2]apifootball.FixturesStatistics
+	Events     [2]apifootball.Events
+	Lineups    [2]apifootball.Lineups
+	Players    [2]apifootball.FixturesPlayers
+}
+
+func pair(score apifootball.Score) string {
+	if !score.Home.Valid || !score.Away.Valid {
+		return ""
+	}
+	return fmt.Sprintf("%d - %d", score.Home.Int, score.Away.Int)
+}
+
+func goals(n apifootball.NullInt) *int {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int
+}
+
+// Build merges the parts into a report.
+func Build(parts Parts) Report {
+	f := parts.Fixture
+	home, away := f.Teams.Home, f.Teams.Away
+	r := Report{
+		FixtureID:   f.Fixture.ID,
+		Date:        f.Fixture.Date,
+		Status:      f.Fixture.Status.Long,
+		Competition: Competition{f.League.ID, f.League.Name, f.League.Logo, f.League.Season, f.League.Round},
+		Venue:       f.Fixture.Venue.Name.String,
+		City:        f.Fixture.Venue.City.String,
+		Referee:     f.Fixture.Referee.String,
+		Home:        Side{Team: Team{home.ID, home.Name, home.Logo}, Goals: goals(f.Goals.Home), StartXI: []Player{}, Substitutes: []Player{}},
+		Away:        Side{Team: Team{away.ID, away.Name, away.Logo}, Goals: goals(f.Goals.Away), StartXI: []Player{}, Substitutes: []Player{}},
+		Halftime:    pair(f.Score.Halftime),
+		Penalties:   pair(f.Score.Penalty),
+		Statistics:  statistics(parts.Statistics),
+		Timeline:    []Event{},
+	}
+	lineup(&r.Home, parts.Lineups[0])
+	lineup(&r.Away, parts.Lineups[1])
+
+	for i, events := range parts.Events {
+		side := [2]string{"home", "away"}[i]
+		for _, e := range events.Response {
+			r.Timeline = append(r.Timeline, Event{
+				Minute:   e.Time.Elapsed,
+				Extra:    e.Time.Extra.Int,
+				Side:     side,
+				Team:     Team{e.Team.ID, e.Team.Name, e.Team.Logo},
+				Player:   e.Player.Name,
+				Assist:   e.Assist.Name.String,
+				Type:     e.Type,
+				Detail:   e.Detail,
+				Comments: e.Comments.String,
+			})
+		}
+	}
+	sort.SliceStable(r.Timeline, func(i, j int) bool {
+		a, b := r.Timeline[i], r.Timeline[j]
+		if a.Minute != b.Minute {
+			return a.Minute < b.Minute
+		}
+		return a.Extra < b.Extra
+	})
+
+	r.ManOfTheMatch = manOfTheMatch(parts.Players)
+	return r
+}
+
+// statistics sets the statistics of both teams side by side, in the order
+// the provider lists them.
+func statistics(teams [2]apifootball.FixturesStatistics) []Row {
+	rows := []Row{}
+	index := map[string]int{}
+	for side, statistics := range teams {
+		for _, response := range statistics.Response {
+			for _, s := range response.Statistics {
+				i, ok := index[s.Type]
+				if !ok {
+					i = len(rows)
+					index[s.Type] = i
+					rows = append(rows, Row{Type: s.Type})
+				}
+				if side == 0 {
+					rows[i].Home = s.Value
+				} else {
+					rows[i].Away = s.Value
+				}
+			}
+		}
+	}
+	return rows
+}
+
+func lineup(side *Side, lineups apifootball.Lineups) {
+	for _, l := range lineups.Response {
+		side.Formation = l.Formation
+		side.Coach = l.Coach.Name
+		for _, p := range l.Startxi {
+			side.StartXI = append(side.StartXI, Player{p.Player.ID, p.Player.Name, p.Player.Number, p.Player.Pos})
+		}
+		for _, p := range l.Substitutes {
+			side.Substitutes = append(side.Substitutes, Player{p.Player.ID, p.Player.Name, p.Player.Number, p.Player.Pos})
+		}
+	}
+}
+
+// manOfTheMatch is the best rated player of both teams. Goal involvements
+// and then minutes break ties.
+func manOfTheMatch(teams [2]apifootball.FixturesPlayers) *Rated {
+	var best *Rated
+	for _, players := range teams {
+		for _, response := range players.Response {
+			team := Team{response.Team.ID, response.Team.Name, response.Team.Logo}
+			for _, p := range response.Players {
+				for _, s := range p.Statistics {
+					if !s.Games.Rating.Valid {
+						continue
+					}
+					rated := &Rated{
+						Player:  Player{p.Player.ID, p.Player.Name, s.Games.Number, s.Games.Position},
+						Photo:   p.Player.Photo,
+						Team:    team,
+						Rating:  s.Games.Rating.Float,
+						Minutes: s.Games.Minutes.Int,
+						Goals:   s.Goals.Total.Int,
+						Assists: s.Goals.Assists.Int,
+					}
+					if best == nil || better(rated, best) {
+						best = rated
+					}
+				}
+			}
+		}
+	}
+	return best
+}
+
+func better(a *Rated, b *Rated) bool {
+	if a.Rating != b.Rating {
+		return a.Rating > b.Rating
+	}
+	if a.Goals+a.Assists != b.Goals+b.Assists {
+		return a.Goals+a.Assists > b.Goals+b.Assists
+	}
+	return a.Minutes > b.Minutes
+}
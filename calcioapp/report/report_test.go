@@ -0,0 +1,175 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+)
+
+func decode(t *testing.T, payload string, v interface{}) {
+	if err := json.Unmarshal([]byte(payload), v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func parts(t *testing.T) Parts {
+	var p Parts
+	decode(t, `{"fixture": {"id": 1, "status": {"long": "Match Finished", "short": "FT"}, "venue": {"name": "San Siro", "city": "Milano"}},
+		"league": {"id": 135, "name": "Serie A", "round": "Regular Season - 1"},
+		"teams": {"home": {"id": 1, "name": "Home | FC"}, "away": {"id": 2, "name": "Away"}},
+		"goals": {"home": 2, "away": 1}, "score": {"halftime": {"home": 1, "away": 1}}}`, &p.Fixture)
+	decode(t, `{"response": [{"team": {"id": 1}, "statistics": [{"type": "Shots on Goal", "value": 6}, {"type": "Ball Possession", "value": "58%"}]}]}`, &p.Statistics[0])
+	decode(t, `{"response": [{"team": {"id": 2}, "statistics": [{"type": "Ball Possession", "value": "42%"}, {"type": "Shots on Goal", "value": null}, {"type": "Offsides", "value": 3}]}]}`, &p.Statistics[1])
+	decode(t, `{"response": [
+		{"time": {"elapsed": 45, "extra": 2}, "team": {"id": 1, "name": "Home | FC"}, "player": {"name": "A"}, "assist": {"name": "B"}, "type": "Goal", "detail": "Normal Goal"},
+		{"time": {"elapsed": 80}, "team": {"id": 1, "name": "Home | FC"}, "player": {"name": "A"}, "type": "Goal", "detail": "Penalty"}]}`, &p.Events[0])
+	decode(t, `{"response": [
+		{"time": {"elapsed": 12}, "team": {"id": 2, "name": "Away"}, "player": {"name": "C"}, "type": "Goal", "detail": "Normal Goal"},
+		{"time": {"elapsed": 45}, "team": {"id": 2, "name": "Away"}, "player": {"name": "D"}, "type": "Card", "detail": "Yellow Card"}]}`, &p.Events[1])
+	decode(t, `{"response": [{"formation": "4-3-3", "coach": {"name": "Coach"}, "startXI": [{"player": {"id": 9, "name": "A", "number": 9, "pos": "F", "grid": "4:2"}}]}]}`, &p.Lineups[0])
+	decode(t, `{"response": [{"team": {"id": 1, "name": "Home | FC"}, "players": [
+		{"player": {"id": 9, "name": "A"}, "statistics": [{"games": {"minutes": 90, "rating": "8.1"}, "goals": {"total": 2}}]},
+		{"player": {"id": 10, "name": "B"}, "statistics": [{"games": {"minutes": 90, "rating": "8.1"}, "goals": {"assists": 1}}]},
+		{"player": {"id": 11, "name": "E"}, "statistics": [{"games": {"minutes": null, "rating": null}}]}]}]}`, &p.Players[0])
+	decode(t, `{"response": [{"team": {"id": 2, "name": "Away"}, "players": [
+		{"player": {"id": 20, "name": "C"}, "statistics": [{"games": {"minutes": 90, "rating": "7.9"}, "goals": {"total": 1}}]}]}]}`, &p.Players[1])
+	return p
+}
+
+func TestBuild(t *testing.T) {
+	r := Build(parts(t))
+	if r.Score() != "2 - 1" || r.Halftime != "1 - 1" || r.Venue != "San Siro" {
+		t.Errorf("score %q, half time %q, venue %q", r.Score(), r.Halftime, r.Venue)
+	}
+
+	if len(r.Statistics) != 3 {
+		t.Fatalf("statistics = %+v", r.Statistics)
+	}
+	if row := r.Statistics[0]; row.Type != "Shots on Goal" || row.Home.Value != 6 || row.Away.Valid {
+		t.Errorf("shots row = %+v", row)
+	}
+	if row := r.Statistics[2]; row.Type != "Offsides" || row.Home.Valid || row.Away.Value != 3 {
+		t.Errorf("away only row = %+v", row)
+	}
+
+	var clocks []string
+	for _, e := range r.Timeline {
+		clocks = append(clocks, e.Clock()+" "+e.Side)
+	}
+	if got := strings.Join(clocks, ", "); got != "12' away, 45' away, 45+2' home, 80' home" {
+		t.Errorf("timeline = %s", got)
+	}
+
+	// Ratings tie: the scorer of two beats the assist.
+	if m := r.ManOfTheMatch; m == nil || m.Player.ID != 9 || m.Team.ID != 1 || m.Goals != 2 {
+		t.Errorf("man of the match = %+v", m)
+	}
+	if r.Home.Formation != "4-3-3" || len(r.Home.StartXI) != 1 || len(r.Away.StartXI) != 0 {
+		t.Errorf("home %+v, away %+v", r.Home, r.Away)
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := Build(parts(t))
+	var md bytes.Buffer
+	if err := Markdown(&md, r); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# Home | FC 2 - 1 Away\n",
+		"| 45+2' | Home \\| FC | Normal Goal | A | B |\n",
+		"| 6 | Shots on Goal | - |\n",
+		"| 58% | Ball Possession | 42% |\n",
+		"**Man of the match:** A (Home | FC), rating 8.1\n",
+		"### Home | FC (4-3-3)\n\n- 9 A (F)\n",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown lacks %q:\n%s", want, md.String())
+		}
+	}
+
+	var html bytes.Buffer
+	if err := HTML(&html, r); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<title>Home | FC 2 - 1 Away</title>",
+		`<tr class="home"><td>45&#43;2&#39;</td>`,
+		"<tr><td>58%</td><td>Ball Possession</td><td>42%</td></tr>",
+	} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("html lacks %q:\n%s", want, html.String())
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	for _, c := range []struct {
+		format, accept, want string
+		ok                   bool
+	}{
+		{"", "", "json", true},
+		{"md", "text/html", "markdown", true},
+		{"html", "", "html", true},
+		{"pdf", "", "pdf", false},
+		{"", "text/html,application/xhtml+xml;q=0.9", "html", true},
+		{"", "image/png, text/markdown; charset=UTF-8", "markdown", true},
+		{"", "*/*", "json", true},
+		{"", "text/html;q=0.5, */*;q=0.8", "json", true},
+	} {
+		if got, ok := Negotiate(c.format, c.accept); got != c.want || ok != c.ok {
+			t.Errorf("Negotiate(%q, %q) = %q, %v", c.format, c.accept, got, ok)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	server := fake.New(fake.Options{DailyLimit: -1})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	client := apifootball.New("test-token", ts.URL+"/")
+
+	fixtures, err := client.GetFixturesByTeamId("505")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixtureId int
+	for _, f := range fixtures.Response {
+		if f.Fixture.Status.Short == "FT" {
+			fixtureId = f.Fixture.ID
+			break
+		}
+	}
+
+	used := server.Used()
+	p, err := Fetch(context.Background(), client, fixtureId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Used()-used != 9 {
+		t.Errorf("report cost %d requests, want 9", server.Used()-used)
+	}
+	r := Build(p)
+	if len(r.Statistics) == 0 || len(r.Home.StartXI) != 11 || len(r.Away.StartXI) != 11 || r.ManOfTheMatch == nil {
+		t.Errorf("report of fixture %d = %+v", fixtureId, r)
+	}
+	goals := 0
+	for _, e := range r.Timeline {
+		if e.Type == "Goal" && e.Detail != "Missed Penalty" {
+			goals++
+		}
+	}
+	if goals != *r.Home.Goals+*r.Away.Goals {
+		t.Errorf("%d goal events for a %s", goals, r.Score())
+	}
+
+	if _, err := Fetch(context.Background(), client, 1); err != ErrNotFound {
+		t.Errorf("unknown fixture: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package simulation
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+// Strength is how a team scores and concedes relative to the league
+// average, at home and away. 1 is average; a HomeAttack of 1.3 scores 30%
+// more home goals than the average home team.
+type Strength struct {
+	HomeAttack  float64 `json:"homeAttack"`
+	HomeDefence float64 `json:"homeDefence"`
+	AwayAttack  float64 `json:"awayAttack"`
+	AwayDefence float64 `json:"awayDefence"`
+}
+
+// Model predicts goals with independent Poisson distributions.
+type Model struct {
+	// HomeGoals and AwayGoals are the league's average goals per match.
+	HomeGoals float64          `json:"homeGoals"`
+	AwayGoals float64          `json:"awayGoals"`
+	Teams     map[int]Strength `json:"teams"`
+}
+
+// Expected returns the mean goals of both teams in a match.
+func (m Model) Expected(homeId int, awayId int) (float64, float64) {
+	home, away := m.strength(homeId), m.strength(awayId)
+	return m.HomeGoals * home.HomeAttack * away.AwayDefence, m.AwayGoals * away.AwayAttack * home.HomeDefence
+}
+
+func (m Model) strength(teamId int) Strength {
+	if s, ok := m.Teams[teamId]; ok {
+		return s
+	}
+	return Strength{1, 1, 1, 1}
+}
+
+// record is a team's goals split by venue.
+type record struct {
+	homePlayed, homeFor, homeAgainst int
+	awayPlayed, awayFor, awayAgainst int
+}
+
+// prior is how many average matches are blended into each team's record,
+// so that a team's first results do not make it unbeatable.
+const prior = 3
+
+func newModel(records map[int]record) Model {
+	var homePlayed, homeGoals, awayGoals int
+	for _, r := range records {
+		homePlayed += r.homePlayed
+		homeGoals += r.homeFor
+		awayGoals += r.awayFor
+	}
+	// The rates of a typical league stand in until home and away teams have
+	// scored: a zero average would make every strength NaN.
+	model := Model{HomeGoals: 1.5, AwayGoals: 1.2, Teams: map[int]Strength{}}
+	if homeGoals > 0 {
+		model.HomeGoals = float64(homeGoals) / float64(homePlayed)
+	}
+	if awayGoals > 0 {
+		model.AwayGoals = float64(awayGoals) / float64(homePlayed)
+	}
+	rate := func(goals int, played int, average float64) float64 {
+		return (float64(goals)/average + prior) / (float64(played) + prior)
+	}
+	for id, r := range records {
+		model.Teams[id] = Strength{
+			HomeAttack:  rate(r.homeFor, r.homePlayed, model.HomeGoals),
+			HomeDefence: rate(r.homeAgainst, r.homePlayed, model.AwayGoals),
+			AwayAttack:  rate(r.awayFor, r.awayPlayed, model.AwayGoals),
+			AwayDefence: rate(r.awayAgainst, r.awayPlayed, model.HomeGoals),
+		}
+	}
+	return model
+}
+
+// ModelFromStandings derives strengths from the home and away goals of a
+// table. They are the totals teams/statistics reports as Goals, but the
+// table costs one request for the whole league rather than one per team.
+func ModelFromStandings(rows []apifootball.StandingsRow) Model {
+	records := map[int]record{}
+	for _, row := range rows {
+		records[row.Team.ID] = record{
+			row.Home.Played, row.Home.Goals.For, row.Home.Goals.Against,
+			row.Away.Played, row.Away.Goals.For, row.Away.Goals.Against,
+		}
+	}
+	return newModel(records)
+}
+
+// poisson draws from a Poisson distribution with Knuth's method, fine for
+// the small means of football scores.
+func poisson(rnd *rand.Rand, mean float64) int {
+	limit := math.Exp(-mean)
+	k, p := 0, rnd.Float64()
+	for p > limit {
+		k++
+		p *= rnd.Float64()
+	}
+	return k
+}
@@ -0,0 +1,263 @@
+// Package simulation plays out the rest of a season many times to estimate
+// where each team finishes: title, European places and relegation.
+package simulation
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+type Options struct {
+	// Simulations is the number of seasons played. Defaults to 10000.
+	Simulations int
+	// Seed makes the result reproducible. The same seed and simulations
+	// give the same odds whatever the number of workers.
+	Seed int64
+	// Workers defaults to the number of CPUs.
+	Workers int
+}
+
+// chunk is the number of simulations seeded together; the unit of work.
+const chunk = 500
+
+// Zone is a group of final positions sharing a standings description, e.g.
+// "Promotion - Champions League (Group Stage)" or "Relegation - Serie B".
+type Zone struct {
+	Name  string `json:"name"`
+	Ranks []int  `json:"ranks"`
+}
+
+// Title is the zone of the first place, which the provider does not describe.
+const Title = "Title"
+
+type Team struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+type TeamOdds struct {
+	Team           Team    `json:"team"`
+	Rank           int     `json:"rank"`
+	Points         int     `json:"points"`
+	ExpectedPoints float64 `json:"expectedPoints"`
+	// Positions[i] is the probability of finishing in rank i+1.
+	Positions []float64          `json:"positions"`
+	Zones     map[string]float64 `json:"zones"`
+}
+
+type Result struct {
+	Simulations int        `json:"simulations"`
+	Remaining   int        `json:"remaining"`
+	Zones       []Zone     `json:"zones"`
+	Teams       []TeamOdds `json:"teams"`
+}
+
+type match struct {
+	home, away         int
+	homeMean, awayMean float64
+}
+
+// Simulate plays the fixtures of the league that are not finished, starting
+// from the current table. Teams level on points are ordered by goal
+// difference, goals scored and then at random: head-to-head records are
+// not modelled.
+func Simulate(ctx context.Context, rows []apifootball.StandingsRow, fixtures []apifootball.Fixture, model Model, opts Options) (Result, error) {
+	if opts.Simulations <= 0 {
+		opts.Simulations = 10000
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+
+	index := map[int]int{}
+	for i, row := range rows {
+		index[row.Team.ID] = i
+	}
+	var matches []match
+	for _, fixture := range fixtures {
+		home, okHome := index[fixture.Teams.Home.ID]
+		away, okAway := index[fixture.Teams.Away.ID]
+		if !okHome || !okAway || standings.Finished(fixture) || cancelled(fixture) {
+			continue
+		}
+		homeMean, awayMean := model.Expected(fixture.Teams.Home.ID, fixture.Teams.Away.ID)
+		matches = append(matches, match{home, away, homeMean, awayMean})
+	}
+
+	n := len(rows)
+	chunks := make(chan int)
+	var mu sync.Mutex
+	positions := make([][]int, n)
+	for i := range positions {
+		positions[i] = make([]int, n)
+	}
+	points := make([]int, n)
+
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := newSeason(rows)
+			localPositions := make([][]int, n)
+			for i := range localPositions {
+				localPositions[i] = make([]int, n)
+			}
+			localPoints := make([]int, n)
+			for c := range chunks {
+				rnd := rand.New(rand.NewSource(opts.Seed + int64(c)))
+				count := chunk
+				if rest := opts.Simulations - c*chunk; rest < count {
+					count = rest
+				}
+				for i := 0; i < count; i++ {
+					s.play(rnd, matches)
+					for rank, team := range s.order {
+						localPositions[team][rank]++
+						localPoints[team] += s.points[team]
+					}
+				}
+			}
+			mu.Lock()
+			for team := range localPositions {
+				for rank, count := range localPositions[team] {
+					positions[team][rank] += count
+				}
+				points[team] += localPoints[team]
+			}
+			mu.Unlock()
+		}()
+	}
+
+	var err error
+send:
+	for c := 0; c*chunk < opts.Simulations; c++ {
+		select {
+		case chunks <- c:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break send
+		}
+	}
+	close(chunks)
+	wg.Wait()
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Simulations: opts.Simulations, Remaining: len(matches), Zones: zones(rows)}
+	total := float64(opts.Simulations)
+	for i, row := range rows {
+		odds := TeamOdds{
+			Team:           Team{row.Team.ID, row.Team.Name, row.Team.Logo},
+			Rank:           row.Rank,
+			Points:         row.Points,
+			ExpectedPoints: float64(points[i]) / total,
+			Positions:      make([]float64, n),
+			Zones:          map[string]float64{},
+		}
+		for rank, count := range positions[i] {
+			odds.Positions[rank] = float64(count) / total
+		}
+		for _, zone := range result.Zones {
+			for _, rank := range zone.Ranks {
+				odds.Zones[zone.Name] += odds.Positions[rank-1]
+			}
+		}
+		result.Teams = append(result.Teams, odds)
+	}
+	return result, nil
+}
+
+func cancelled(fixture apifootball.Fixture) bool {
+	switch fixture.Fixture.Status.Short {
+	case "CANC", "ABD":
+		return true
+	}
+	return false
+}
+
+// zones groups the ranks of the table by description, the title first.
+func zones(rows []apifootball.StandingsRow) []Zone {
+	result := []Zone{{Name: Title, Ranks: []int{1}}}
+	byName := map[string]int{}
+	for _, row := range rows {
+		if row.Description == "" {
+			continue
+		}
+		i, ok := byName[row.Description]
+		if !ok {
+			i = len(result)
+			byName[row.Description] = i
+			result = append(result, Zone{Name: row.Description})
+		}
+		result[i].Ranks = append(result[i].Ranks, row.Rank)
+	}
+	return result
+}
+
+// season is the state of one simulated season, reused between runs.
+type season struct {
+	basePoints, baseDiff, baseFor []int
+	points, diff, goalsFor        []int
+	luck                          []float64
+	order                         []int
+}
+
+func newSeason(rows []apifootball.StandingsRow) *season {
+	n := len(rows)
+	s := &season{
+		basePoints: make([]int, n), baseDiff: make([]int, n), baseFor: make([]int, n),
+		points: make([]int, n), diff: make([]int, n), goalsFor: make([]int, n),
+		luck: make([]float64, n), order: make([]int, n),
+	}
+	for i, row := range rows {
+		s.basePoints[i], s.baseDiff[i], s.baseFor[i] = row.Points, row.Goalsdiff, row.All.Goals.For
+	}
+	return s
+}
+
+func (s *season) play(rnd *rand.Rand, matches []match) {
+	copy(s.points, s.basePoints)
+	copy(s.diff, s.baseDiff)
+	copy(s.goalsFor, s.baseFor)
+	for _, m := range matches {
+		home, away := poisson(rnd, m.homeMean), poisson(rnd, m.awayMean)
+		s.diff[m.home] += home - away
+		s.diff[m.away] += away - home
+		s.goalsFor[m.home] += home
+		s.goalsFor[m.away] += away
+		switch {
+		case home > away:
+			s.points[m.home] += 3
+		case home < away:
+			s.points[m.away] += 3
+		default:
+			s.points[m.home]++
+			s.points[m.away]++
+		}
+	}
+	for i := range s.order {
+		s.order[i] = i
+		s.luck[i] = rnd.Float64()
+	}
+	sort.Slice(s.order, func(i, j int) bool {
+		a, b := s.order[i], s.order[j]
+		switch {
+		case s.points[a] != s.points[b]:
+			return s.points[a] > s.points[b]
+		case s.diff[a] != s.diff[b]:
+			return s.diff[a] > s.diff[b]
+		case s.goalsFor[a] != s.goalsFor[b]:
+			return s.goalsFor[a] > s.goalsFor[b]
+		}
+		return s.luck[a] > s.luck[b]
+	})
+}
@@ -0,0 +1,88 @@
+package simulation
+
+import (
+	"context"
+	"math"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+)
+
+func league(t *testing.T) ([]apifootball.StandingsRow, []apifootball.Fixture) {
+	server := httptest.NewServer(fake.New(fake.Options{DailyLimit: -1}))
+	t.Cleanup(server.Close)
+	client := apifootball.New("test-token", server.URL+"/")
+	table, err := client.GetStandingsByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixtures, err := client.GetFixturesByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table.Response[0].League.Standings[0], fixtures.Response
+}
+
+func TestSimulate(t *testing.T) {
+	rows, fixtures := league(t)
+	result, err := Simulate(context.Background(), rows, fixtures, ModelFromStandings(rows), Options{Simulations: 2000, Seed: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Remaining == 0 || len(result.Teams) != len(rows) {
+		t.Fatalf("result = %+v", result)
+	}
+
+	for rank := range rows {
+		sum := 0.0
+		for _, team := range result.Teams {
+			sum += team.Positions[rank]
+		}
+		if math.Abs(sum-1) > 1e-9 {
+			t.Errorf("probabilities of rank %d sum to %f", rank+1, sum)
+		}
+	}
+	title := 0.0
+	for _, team := range result.Teams {
+		title += team.Zones[Title]
+		if team.ExpectedPoints < float64(team.Points) {
+			t.Errorf("%s expects %f points, already has %d", team.Team.Name, team.ExpectedPoints, team.Points)
+		}
+	}
+	if math.Abs(title-1) > 1e-9 {
+		t.Errorf("title odds sum to %f", title)
+	}
+	if leader, last := result.Teams[0], result.Teams[len(result.Teams)-1]; leader.Zones[Title] <= last.Zones[Title] {
+		t.Errorf("leader title odds %f not above last placed %f", leader.Zones[Title], last.Zones[Title])
+	}
+	if len(result.Zones) < 3 {
+		t.Errorf("zones = %+v, want title, europe and relegation", result.Zones)
+	}
+}
+
+func TestSimulateIsReproducible(t *testing.T) {
+	rows, fixtures := league(t)
+	model := ModelFromStandings(rows)
+	one, _ := Simulate(context.Background(), rows, fixtures, model, Options{Simulations: 1200, Seed: 3, Workers: 1})
+	many, _ := Simulate(context.Background(), rows, fixtures, model, Options{Simulations: 1200, Seed: 3, Workers: 4})
+	if !reflect.DeepEqual(one, many) {
+		t.Error("the same seed gave different odds with a different number of workers")
+	}
+}
+
+func TestModelWithoutGoals(t *testing.T) {
+	rows, _ := league(t)
+	for i := range rows {
+		rows[i].Home.Played, rows[i].Away.Played = 1, 1
+		rows[i].Home.Goals.For, rows[i].Home.Goals.Against = 0, 0
+		rows[i].Away.Goals.For, rows[i].Away.Goals.Against = 0, 0
+	}
+	model := ModelFromStandings(rows)
+	home, away := model.Expected(rows[0].Team.ID, rows[1].Team.ID)
+	if model.HomeGoals != 1.5 || model.AwayGoals != 1.2 || math.IsNaN(home) || math.IsNaN(away) || home == 0 || away == 0 {
+		t.Errorf("goalless league: averages %v and %v, expected goals %v and %v", model.HomeGoals, model.AwayGoals, home, away)
+	}
+}
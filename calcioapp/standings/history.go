@@ -0,0 +1,163 @@
+package standings
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/metrics"
+)
+
+// Position is a team's place in the table after a round.
+type Position struct {
+	Round  int `json:"round"`
+	Rank   int `json:"rank"`
+	Points int `json:"points"`
+}
+
+type TeamHistory struct {
+	Team    Team       `json:"team"`
+	History []Position `json:"history"`
+}
+
+// History is every team's rank and points after each round, ordered by the
+// latest table.
+type History struct {
+	Rules  string        `json:"rules"`
+	Rounds []int         `json:"rounds"`
+	Teams  []TeamHistory `json:"teams"`
+}
+
+type snapshot struct {
+	hash  uint64
+	table Table
+}
+
+// SnapshotCache keeps the table after each round of a league. A snapshot is
+// recomputed only when the fixtures it counts change, so a new matchday
+// costs one table rather than the whole season.
+type SnapshotCache struct {
+	mu        sync.Mutex
+	snapshots map[string]snapshot
+}
+
+func NewSnapshotCache() *SnapshotCache {
+	return &SnapshotCache{snapshots: map[string]snapshot{}}
+}
+
+// History computes the tables after every round with a finished fixture.
+// The table after a round counts the fixtures of that round and the ones
+// before it. A postponed fixture, one played after the normal date of the
+// next round, counts from the first round whose normal date it was played by.
+func (c *SnapshotCache) History(leagueId int, fixtures []apifootball.Fixture, rules Rules) History {
+	dates := normalDates(fixtures)
+	rounds := make([]int, 0, len(dates))
+	for round := range dates {
+		rounds = append(rounds, round)
+	}
+	sort.Ints(rounds)
+	// due is when a round's fixtures are late: the next round's normal date.
+	due := map[int]time.Time{}
+	for i := 0; i+1 < len(rounds); i++ {
+		due[rounds[i]] = dates[rounds[i+1]]
+	}
+
+	history := History{Rules: rules.Name, Rounds: rounds}
+	byTeam := map[int]*TeamHistory{}
+	var latest Table
+	for _, round := range rounds {
+		table := c.table(leagueId, fixtures, rules, round, played(round, dates[round], due))
+		for _, row := range table.Rows {
+			team, ok := byTeam[row.Team.ID]
+			if !ok {
+				team = &TeamHistory{Team: row.Team}
+				byTeam[row.Team.ID] = team
+			}
+			team.History = append(team.History, Position{round, row.Rank, row.Points})
+		}
+		latest = table
+	}
+	for _, row := range latest.Rows {
+		history.Teams = append(history.Teams, *byTeam[row.Team.ID])
+	}
+	return history
+}
+
+// played is the filter of the table after round: fixtures up to the round,
+// postponed ones only when played by its normal date.
+func played(round int, date time.Time, due map[int]time.Time) Filter {
+	upToRound := UpToRound(round)
+	return func(fixture apifootball.Fixture) bool {
+		if !upToRound(fixture) {
+			return false
+		}
+		r, _ := RoundNumber(fixture.League.Round)
+		late, ok := due[r]
+		postponed := ok && fixture.Fixture.Date.After(late)
+		return !postponed || !fixture.Fixture.Date.After(date)
+	}
+}
+
+func (c *SnapshotCache) table(leagueId int, fixtures []apifootball.Fixture, rules Rules, round int, filter Filter) Table {
+	key := fmt.Sprintf("%d/%s/%d", leagueId, rules.Name, round)
+	hash := fingerprint(fixtures, filter)
+
+	c.mu.Lock()
+	cached, ok := c.snapshots[key]
+	c.mu.Unlock()
+	if ok && cached.hash == hash {
+		metrics.CacheHit("standings_history")
+		return cached.table
+	}
+	metrics.CacheMiss("standings_history")
+
+	table := Compute(fixtures, rules, filter)
+	table.Round = round
+	c.mu.Lock()
+	c.snapshots[key] = snapshot{hash, table}
+	c.mu.Unlock()
+	return table
+}
+
+// normalDates maps each round with a finished fixture to its normal date,
+// the median kickoff of its finished fixtures. Unlike the last kickoff, it
+// does not move when a few of the round's matches are postponed.
+func normalDates(fixtures []apifootball.Fixture) map[int]time.Time {
+	kickoffs := map[int][]time.Time{}
+	for _, fixture := range fixtures {
+		round, ok := RoundNumber(fixture.League.Round)
+		if !ok || !Finished(fixture) {
+			continue
+		}
+		kickoffs[round] = append(kickoffs[round], fixture.Fixture.Date)
+	}
+	dates := map[int]time.Time{}
+	for round, times := range kickoffs {
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		dates[round] = times[(len(times)-1)/2]
+	}
+	return dates
+}
+
+// fingerprint hashes the teams and results of the finished fixtures passing filter.
+func fingerprint(fixtures []apifootball.Fixture, filter Filter) uint64 {
+	ids := make([]int, 0, len(fixtures))
+	results := map[int]string{}
+	for _, fixture := range fixtures {
+		if !Finished(fixture) || !filter(fixture) {
+			continue
+		}
+		id := fixture.Fixture.ID
+		ids = append(ids, id)
+		results[id] = fmt.Sprintf("%d-%d:%d-%d", fixture.Teams.Home.ID, fixture.Teams.Away.ID, fixture.Goals.Home.Int, fixture.Goals.Away.Int)
+	}
+	sort.Ints(ids)
+	h := fnv.New64a()
+	for _, id := range ids {
+		fmt.Fprintf(h, "%d=%s;", id, results[id])
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,71 @@
+package standings
+
+import "strings"
+
+// Tiebreaker orders teams level on points.
+type Tiebreaker int
+
+const (
+	// HeadToHeadPoints compares the points won in the matches between the tied teams.
+	HeadToHeadPoints Tiebreaker = iota
+	// HeadToHeadGoalDifference compares the goal difference in the matches between the tied teams.
+	HeadToHeadGoalDifference
+	// HeadToHeadGoalsScored compares the goals scored in the matches between the tied teams.
+	HeadToHeadGoalsScored
+	// HeadToHeadAwayGoals compares the away goals scored in the matches between the tied teams.
+	HeadToHeadAwayGoals
+	GoalDifference
+	GoalsScored
+	Wins
+)
+
+// Rules are the points per result and the tiebreakers of a competition.
+// Teams still level after every tiebreaker are ordered by name, standing
+// in for the play-off or draw the competitions use.
+type Rules struct {
+	Name        string
+	Win         int
+	Draw        int
+	Tiebreakers []Tiebreaker
+}
+
+// SerieA breaks ties on the matches between the tied teams first.
+var SerieA = Rules{
+	Name:        "serie-a",
+	Win:         3,
+	Draw:        1,
+	Tiebreakers: []Tiebreaker{HeadToHeadPoints, HeadToHeadGoalDifference, GoalDifference, GoalsScored},
+}
+
+// PremierLeague breaks ties on the whole season first.
+var PremierLeague = Rules{
+	Name:        "premier-league",
+	Win:         3,
+	Draw:        1,
+	Tiebreakers: []Tiebreaker{GoalDifference, GoalsScored, HeadToHeadPoints, HeadToHeadAwayGoals},
+}
+
+var rulesByLeague = map[int]Rules{
+	135: SerieA,
+	136: SerieA, // Serie B
+	39:  PremierLeague,
+}
+
+// RulesFor returns the rules of an API-Football league, defaulting to goal
+// difference first.
+func RulesFor(leagueId int) Rules {
+	if rules, ok := rulesByLeague[leagueId]; ok {
+		return rules
+	}
+	return PremierLeague
+}
+
+// ParseRules finds rules by name, e.g. "serie-a".
+func ParseRules(name string) (Rules, bool) {
+	for _, rules := range []Rules{SerieA, PremierLeague} {
+		if strings.EqualFold(rules.Name, name) {
+			return rules, true
+		}
+	}
+	return Rules{}, false
+}
@@ -0,0 +1,302 @@
+// Package standings computes league tables from fixtures, so the table can
+// be shown as it was after any date or round and not only as the provider's
+// current snapshot.
+package standings
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+type Team struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+type Record struct {
+	Played int `json:"played"`
+	Win    int `json:"win"`
+	Draw   int `json:"draw"`
+	Lose   int `json:"lose"`
+	Goals  struct {
+		For     int `json:"for"`
+		Against int `json:"against"`
+	} `json:"goals"`
+}
+
+func (r *Record) add(goalsFor int, goalsAgainst int) {
+	r.Played++
+	r.Goals.For += goalsFor
+	r.Goals.Against += goalsAgainst
+	switch {
+	case goalsFor > goalsAgainst:
+		r.Win++
+	case goalsFor < goalsAgainst:
+		r.Lose++
+	default:
+		r.Draw++
+	}
+}
+
+// Row mirrors a row of the provider's standings.
+type Row struct {
+	Rank      int    `json:"rank"`
+	Team      Team   `json:"team"`
+	Points    int    `json:"points"`
+	GoalsDiff int    `json:"goalsDiff"`
+	Form      string `json:"form"`
+	All       Record `json:"all"`
+	Home      Record `json:"home"`
+	Away      Record `json:"away"`
+
+	results []byte
+}
+
+type Table struct {
+	Rules string `json:"rules"`
+	// Round is the last round included, 0 when the fixtures are not numbered.
+	Round int `json:"round"`
+	// AsOf is the kickoff of the last fixture included.
+	AsOf time.Time `json:"asOf"`
+	Rows []Row     `json:"standings"`
+}
+
+// Row returns the row of a team.
+func (t Table) Row(teamId int) (Row, bool) {
+	for _, row := range t.Rows {
+		if row.Team.ID == teamId {
+			return row, true
+		}
+	}
+	return Row{}, false
+}
+
+// Filter selects the fixtures counted in a table.
+type Filter func(fixture apifootball.Fixture) bool
+
+// Until counts fixtures kicking off before the end of date's day.
+func Until(date time.Time) Filter {
+	end := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location()).AddDate(0, 0, 1)
+	return func(fixture apifootball.Fixture) bool {
+		return fixture.Fixture.Date.Before(end)
+	}
+}
+
+// UpToRound counts fixtures of rounds 1 to round.
+func UpToRound(round int) Filter {
+	return func(fixture apifootball.Fixture) bool {
+		n, ok := RoundNumber(fixture.League.Round)
+		return ok && n <= round
+	}
+}
+
+var roundNumber = regexp.MustCompile(`(\d+)\s*$`)
+
+// RoundNumber reads the matchday from a round such as "Regular Season - 20".
+func RoundNumber(round string) (int, bool) {
+	match := roundNumber.FindStringSubmatch(round)
+	if match == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(match[1])
+	return n, err == nil
+}
+
+// Finished reports whether a fixture has a final result.
+func Finished(fixture apifootball.Fixture) bool {
+	switch fixture.Fixture.Status.Short {
+	case "FT", "AET", "PEN", "AWD", "WO":
+		return fixture.Goals.Home.Valid && fixture.Goals.Away.Valid
+	}
+	return false
+}
+
+// Compute builds the table of the finished fixtures passing every filter.
+// Teams of the remaining fixtures are listed with an empty record.
+func Compute(fixtures []apifootball.Fixture, rules Rules, filters ...Filter) Table {
+	sorted := make([]apifootball.Fixture, len(fixtures))
+	copy(sorted, fixtures)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Fixture.Date.Before(sorted[j].Fixture.Date)
+	})
+
+	table := Table{Rules: rules.Name}
+	rows := map[int]*Row{}
+	var counted []apifootball.Fixture
+	for _, fixture := range sorted {
+		home := rowFor(rows, fixture.Teams.Home.ID, fixture.Teams.Home.Name, fixture.Teams.Home.Logo)
+		away := rowFor(rows, fixture.Teams.Away.ID, fixture.Teams.Away.Name, fixture.Teams.Away.Logo)
+		if !Finished(fixture) || !passes(fixture, filters) {
+			continue
+		}
+		counted = append(counted, fixture)
+		homeGoals, awayGoals := fixture.Goals.Home.Int, fixture.Goals.Away.Int
+		home.All.add(homeGoals, awayGoals)
+		home.Home.add(homeGoals, awayGoals)
+		away.All.add(awayGoals, homeGoals)
+		away.Away.add(awayGoals, homeGoals)
+		home.results = append(home.results, result(homeGoals, awayGoals))
+		away.results = append(away.results, result(awayGoals, homeGoals))
+
+		table.AsOf = fixture.Fixture.Date
+		if n, ok := RoundNumber(fixture.League.Round); ok && n > table.Round {
+			table.Round = n
+		}
+	}
+
+	list := make([]*Row, 0, len(rows))
+	for _, row := range rows {
+		row.Points = rules.Win*row.All.Win + rules.Draw*row.All.Draw
+		row.GoalsDiff = row.All.Goals.For - row.All.Goals.Against
+		row.Form = form(row.results)
+		list = append(list, row)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Points > list[j].Points
+	})
+	ordered := make([]*Row, 0, len(list))
+	for _, group := range splitBy(list, func(row *Row) int { return row.Points }) {
+		ordered = append(ordered, breakTies(group, rules.Tiebreakers, rules, counted)...)
+	}
+
+	table.Rows = make([]Row, len(ordered))
+	for i, row := range ordered {
+		row.Rank = i + 1
+		table.Rows[i] = *row
+	}
+	return table
+}
+
+func rowFor(rows map[int]*Row, id int, name string, logo string) *Row {
+	row, ok := rows[id]
+	if !ok {
+		row = &Row{Team: Team{id, name, logo}}
+		rows[id] = row
+	}
+	return row
+}
+
+func passes(fixture apifootball.Fixture, filters []Filter) bool {
+	for _, filter := range filters {
+		if !filter(fixture) {
+			return false
+		}
+	}
+	return true
+}
+
+func result(goalsFor int, goalsAgainst int) byte {
+	switch {
+	case goalsFor > goalsAgainst:
+		return 'W'
+	case goalsFor < goalsAgainst:
+		return 'L'
+	}
+	return 'D'
+}
+
+// form is the last five results, most recent first like the provider's.
+func form(results []byte) string {
+	form := make([]byte, 0, 5)
+	for i := len(results) - 1; i >= 0 && len(form) < 5; i-- {
+		form = append(form, results[i])
+	}
+	return string(form)
+}
+
+// splitBy cuts rows, already sorted by key, into runs of equal key.
+func splitBy(rows []*Row, key func(row *Row) int) [][]*Row {
+	var groups [][]*Row
+	for i, row := range rows {
+		if i == 0 || key(row) != key(rows[i-1]) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], row)
+	}
+	return groups
+}
+
+// breakTies orders teams level on points with the first tiebreaker and
+// hands teams still level to the next one. Head-to-head criteria are
+// computed between the teams still level at that point.
+func breakTies(group []*Row, tiebreakers []Tiebreaker, rules Rules, fixtures []apifootball.Fixture) []*Row {
+	if len(group) < 2 {
+		return group
+	}
+	if len(tiebreakers) == 0 {
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Team.Name < group[j].Team.Name
+		})
+		return group
+	}
+
+	value := criterion(tiebreakers[0], group, rules, fixtures)
+	sort.SliceStable(group, func(i, j int) bool {
+		return value(group[i]) > value(group[j])
+	})
+	var ordered []*Row
+	for _, tied := range splitBy(group, value) {
+		ordered = append(ordered, breakTies(tied, tiebreakers[1:], rules, fixtures)...)
+	}
+	return ordered
+}
+
+func criterion(tiebreaker Tiebreaker, group []*Row, rules Rules, fixtures []apifootball.Fixture) func(row *Row) int {
+	switch tiebreaker {
+	case GoalDifference:
+		return func(row *Row) int { return row.GoalsDiff }
+	case GoalsScored:
+		return func(row *Row) int { return row.All.Goals.For }
+	case Wins:
+		return func(row *Row) int { return row.All.Win }
+	}
+
+	inGroup := map[int]bool{}
+	for _, row := range group {
+		inGroup[row.Team.ID] = true
+	}
+	type mini struct{ points, diff, scored, away int }
+	league := map[int]*mini{}
+	for _, row := range group {
+		league[row.Team.ID] = &mini{}
+	}
+	for _, fixture := range fixtures {
+		homeId, awayId := fixture.Teams.Home.ID, fixture.Teams.Away.ID
+		if !inGroup[homeId] || !inGroup[awayId] {
+			continue
+		}
+		homeGoals, awayGoals := fixture.Goals.Home.Int, fixture.Goals.Away.Int
+		home, away := league[homeId], league[awayId]
+		home.diff += homeGoals - awayGoals
+		away.diff += awayGoals - homeGoals
+		home.scored += homeGoals
+		away.scored += awayGoals
+		away.away += awayGoals
+		switch result(homeGoals, awayGoals) {
+		case 'W':
+			home.points += rules.Win
+		case 'L':
+			away.points += rules.Win
+		default:
+			home.points += rules.Draw
+			away.points += rules.Draw
+		}
+	}
+	return func(row *Row) int {
+		m := league[row.Team.ID]
+		switch tiebreaker {
+		case HeadToHeadPoints:
+			return m.points
+		case HeadToHeadGoalDifference:
+			return m.diff
+		case HeadToHeadGoalsScored:
+			return m.scored
+		}
+		return m.away
+	}
+}
@@ -0,0 +1,162 @@
+package standings
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+)
+
+var kickoff = time.Date(2021, 8, 21, 18, 45, 0, 0, time.UTC)
+
+func fixture(round int, home int, away int, homeGoals int, awayGoals int) apifootball.Fixture {
+	var f apifootball.Fixture
+	f.Fixture.Date = kickoff.AddDate(0, 0, 7*(round-1))
+	f.Fixture.Status.Short = "FT"
+	f.League.Round = "Regular Season - " + strconv.Itoa(round)
+	f.Teams.Home.ID, f.Teams.Home.Name = home, names[home]
+	f.Teams.Away.ID, f.Teams.Away.Name = away, names[away]
+	f.Goals.Home = apifootball.NullInt{Int: homeGoals, Valid: true}
+	f.Goals.Away = apifootball.NullInt{Int: awayGoals, Valid: true}
+	return f
+}
+
+var names = map[int]string{1: "Atalanta", 2: "Bologna", 3: "Cagliari", 4: "Empoli"}
+
+func ids(table Table) []int {
+	var ids []int
+	for _, row := range table.Rows {
+		ids = append(ids, row.Team.ID)
+	}
+	return ids
+}
+
+func equal(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Atalanta and Bologna both finish on 6 points. Atalanta won the head to
+// head, Bologna has the better goal difference.
+var season = []apifootball.Fixture{
+	fixture(1, 1, 2, 1, 0),
+	fixture(1, 3, 4, 0, 0),
+	fixture(2, 2, 3, 5, 0),
+	fixture(2, 4, 1, 2, 0),
+	fixture(3, 1, 3, 1, 0),
+	fixture(3, 4, 2, 0, 1),
+}
+
+func TestTiebreakers(t *testing.T) {
+	serieA := Compute(season, SerieA)
+	if got := ids(serieA); !equal(got, []int{1, 2, 4, 3}) {
+		t.Errorf("serie a order = %v, want head to head winner first", got)
+	}
+	premier := Compute(season, PremierLeague)
+	if got := ids(premier); !equal(got, []int{2, 1, 4, 3}) {
+		t.Errorf("premier league order = %v, want better goal difference first", got)
+	}
+
+	row, _ := premier.Row(2)
+	if row.Rank != 1 || row.Points != 6 || row.GoalsDiff != 5 || row.Form != "WWL" || row.Home.Played != 1 || row.Away.Played != 2 {
+		t.Errorf("bologna row = %+v", row)
+	}
+}
+
+func TestTableAsOfRoundAndDate(t *testing.T) {
+	byRound := Compute(season, SerieA, UpToRound(1))
+	byDate := Compute(season, SerieA, Until(kickoff))
+	if byRound.Round != 1 || !equal(ids(byRound), ids(byDate)) {
+		t.Errorf("round 1 = %v, kickoff day = %v", ids(byRound), ids(byDate))
+	}
+	if row, _ := byRound.Row(4); row.All.Played != 1 || row.Points != 1 {
+		t.Errorf("empoli after round 1 = %+v", row)
+	}
+}
+
+func TestUnplayedFixturesAreListedNotCounted(t *testing.T) {
+	upcoming := fixture(4, 3, 1, 0, 0)
+	upcoming.Fixture.Status.Short = "NS"
+	upcoming.Goals = apifootball.Score{}
+	table := Compute(append(season, upcoming), SerieA)
+	if row, _ := table.Row(3); row.All.Played != 3 {
+		t.Errorf("cagliari played %d, want 3", row.All.Played)
+	}
+}
+
+// The provider's table and the computed one must agree on points and goals.
+func TestMatchesProviderStandings(t *testing.T) {
+	server := httptest.NewServer(fake.New(fake.Options{DailyLimit: -1}))
+	defer server.Close()
+	client := apifootball.New("test-token", server.URL+"/")
+
+	fixtures, err := client.GetFixturesByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider, err := client.GetStandingsByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := Compute(fixtures.Response, SerieA)
+	for _, want := range provider.Response[0].League.Standings[0] {
+		row, ok := table.Row(want.Team.ID)
+		if !ok || row.Points != want.Points || row.GoalsDiff != want.Goalsdiff || row.All.Played != want.All.Played || row.Form != want.Form {
+			t.Errorf("%s: computed %+v, provider %+v", want.Team.Name, row, want)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	cache := NewSnapshotCache()
+	history := cache.History(1, season, SerieA)
+	if !equal(history.Rounds, []int{1, 2, 3}) || len(history.Teams) != 4 {
+		t.Fatalf("history = %+v", history)
+	}
+	atalanta := history.Teams[0]
+	if atalanta.Team.ID != 1 || len(atalanta.History) != 3 {
+		t.Fatalf("leader history = %+v", atalanta)
+	}
+	if last := atalanta.History[2]; last.Round != 3 || last.Rank != 1 || last.Points != 6 {
+		t.Errorf("atalanta after round 3 = %+v", last)
+	}
+
+	corrected := append([]apifootball.Fixture{}, season...)
+	corrected[5] = fixture(3, 4, 2, 1, 0)
+	before := cache.snapshots["1/serie-a/2"]
+	history = cache.History(1, corrected, SerieA)
+	if cache.snapshots["1/serie-a/2"].hash != before.hash {
+		t.Error("round 2 snapshot was recomputed for a round 3 change")
+	}
+	if history.Teams[0].Team.ID != 4 || history.Teams[0].History[2].Points != 7 {
+		t.Errorf("latest table not recomputed: %+v", history.Teams[0])
+	}
+}
+
+func TestHistoryPostponed(t *testing.T) {
+	// Cagliari v Empoli of round 1 is played between rounds 2 and 3.
+	postponed := append([]apifootball.Fixture{}, season...)
+	postponed[1].Fixture.Date = kickoff.AddDate(0, 0, 10)
+	history := NewSnapshotCache().History(1, postponed, SerieA)
+	points := map[int][]int{}
+	for _, team := range history.Teams {
+		for _, position := range team.History {
+			points[team.Team.ID] = append(points[team.Team.ID], position.Points)
+		}
+	}
+	for id, want := range map[int][]int{1: {3, 3, 6}, 2: {0, 3, 6}, 3: {0, 0, 1}, 4: {0, 3, 4}} {
+		if !equal(points[id], want) {
+			t.Errorf("%s points after each round = %v, want %v", names[id], points[id], want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package tactics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/cache"
+	"github.com/nero-15/calcio-app/form"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+// parallel bounds the lineup requests in flight for one team.
+const parallel = 4
+
+// Collect builds the report of a team: one request for its fixtures, one
+// for its coaches, one per finished fixture for the lineup and, with a
+// leagueId, one for the team statistics of that league. leagueId 0 covers
+// every competition.
+func Collect(ctx context.Context, client *apifootball.APIClient, teamId int, leagueId int) (Report, error) {
+	client = client.WithContext(ctx)
+	team := strconv.Itoa(teamId)
+	fixtures, err := client.GetFixturesByTeamId(team)
+	if err != nil {
+		return Report{}, err
+	}
+	if fixtures.Results == 0 {
+		return Report{}, fmt.Errorf("tactics: team %d has no fixtures", teamId)
+	}
+	played := form.InLeague(fixtures.Response, leagueId)
+
+	var mu sync.Mutex
+	lineups := map[int]Lineup{}
+	var errs []error
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for _, f := range played {
+		if !standings.Finished(f) {
+			continue
+		}
+		wg.Add(1)
+		go func(fixtureId int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			l, err := client.GetLineupsByTeamIdAndFixtureId(team, strconv.Itoa(fixtureId))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+			} else if lineup, ok := LineupOf(teamId, l); ok {
+				lineups[fixtureId] = lineup
+			}
+		}(f.Fixture.ID)
+	}
+	// A missing coach list only costs the attribution of lineups that do
+	// not name their coach.
+	coachs, _ := client.GetCoachsByTeamId(team)
+	wg.Wait()
+	if len(errs) > 0 {
+		return Report{}, errs[0]
+	}
+
+	report := Analyse(teamId, played, lineups, coachs.Response)
+	if leagueId != 0 {
+		statistics, err := client.GetStatisticsByLeagueIdAndTeamId(strconv.Itoa(leagueId), team)
+		if err != nil {
+			return Report{}, err
+		}
+		report.Season = SeasonOf(statistics)
+	}
+	return report, nil
+}
+
+// Cache keeps the report of each team and league for a while: it costs a
+// request per match played.
+type Cache struct {
+	reports *cache.Cache
+}
+
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{reports: cache.New("tactics", ttl)}
+}
+
+// Report returns the report of a team, collecting it when it is not cached
+// or older than the TTL.
+func (c *Cache) Report(ctx context.Context, client *apifootball.APIClient, teamId int, leagueId int) (Report, error) {
+	report, err := c.reports.Get(ctx, fmt.Sprintf("%d/%d", teamId, leagueId), func(ctx context.Context) (interface{}, error) {
+		return Collect(ctx, client, teamId, leagueId)
+	})
+	if err != nil {
+		return Report{}, err
+	}
+	return report.(Report), nil
+}
@@ -0,0 +1,263 @@
+// Package tactics follows the formations a team lines up in over a season:
+// how often and with which results each was used, when the shape changed
+// and which coach picked which systems.
+package tactics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/form"
+)
+
+type Team struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+type Coach struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Photo string `json:"photo"`
+}
+
+// Match is a finished match of the team with the formation it started in.
+type Match struct {
+	FixtureID int       `json:"fixtureId"`
+	Date      time.Time `json:"date"`
+	LeagueID  int       `json:"leagueId"`
+	Opponent  Team      `json:"opponent"`
+	Home      bool      `json:"home"`
+	Formation string    `json:"formation"`
+	Coach     *Coach    `json:"coach"`
+	Scored    int       `json:"scored"`
+	Conceded  int       `json:"conceded"`
+	Result    string    `json:"result"`
+}
+
+// Usage is the record of the team in one formation.
+type Usage struct {
+	Formation string `json:"formation"`
+	Played    int    `json:"played"`
+	// Share is the part of the matches with a known formation, 0 to 1.
+	Share               float64   `json:"share"`
+	Wins                int       `json:"wins"`
+	Draws               int       `json:"draws"`
+	Losses              int       `json:"losses"`
+	Points              int       `json:"points"`
+	PointsPerGame       float64   `json:"pointsPerGame"`
+	GoalsFor            int       `json:"goalsFor"`
+	GoalsAgainst        int       `json:"goalsAgainst"`
+	GoalsForPerGame     float64   `json:"goalsForPerGame"`
+	GoalsAgainstPerGame float64   `json:"goalsAgainstPerGame"`
+	First               time.Time `json:"first"`
+	Last                time.Time `json:"last"`
+}
+
+// Change is a match the team started in another shape than the last one.
+type Change struct {
+	FixtureID int       `json:"fixtureId"`
+	Date      time.Time `json:"date"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Coach     *Coach    `json:"coach"`
+}
+
+// CoachSystems are the formations of the matches a coach was in charge of.
+type CoachSystems struct {
+	Coach  Coach `json:"coach"`
+	Played int   `json:"played"`
+	// Preferred is the coach's most used formation.
+	Preferred     string  `json:"preferred"`
+	PointsPerGame float64 `json:"pointsPerGame"`
+	Formations    []Usage `json:"formations"`
+}
+
+// Count is a formation and its matches as counted by the provider's team
+// statistics.
+type Count struct {
+	Formation string `json:"formation"`
+	Played    int    `json:"played"`
+}
+
+type Report struct {
+	Team Team `json:"team"`
+	// Played are the finished matches with a known formation; Unknown
+	// those the provider has no lineup for.
+	Played     int            `json:"played"`
+	Unknown    int            `json:"unknown"`
+	Formations []Usage        `json:"formations"`
+	Changes    []Change       `json:"changes"`
+	Coaches    []CoachSystems `json:"coaches"`
+	Matches    []Match        `json:"matches"`
+	// Season is the provider's own count for a league, when asked for one.
+	Season []Count `json:"season,omitempty"`
+}
+
+type tally struct {
+	usages map[string]*Usage
+	order  []string
+	points int
+	played int
+}
+
+func (t *tally) add(m Match) {
+	if t.usages == nil {
+		t.usages = map[string]*Usage{}
+	}
+	u, ok := t.usages[m.Formation]
+	if !ok {
+		u = &Usage{Formation: m.Formation, First: m.Date}
+		t.usages[m.Formation] = u
+		t.order = append(t.order, m.Formation)
+	}
+	u.Played++
+	u.Last = m.Date
+	u.GoalsFor += m.Scored
+	u.GoalsAgainst += m.Conceded
+	switch m.Result {
+	case "W":
+		u.Wins++
+		u.Points += 3
+		t.points += 3
+	case "D":
+		u.Draws++
+		u.Points++
+		t.points++
+	default:
+		u.Losses++
+	}
+	t.played++
+}
+
+// list returns the usages, most played first.
+func (t *tally) list() []Usage {
+	list := make([]Usage, 0, len(t.order))
+	for _, formation := range t.order {
+		u := *t.usages[formation]
+		u.Share = float64(u.Played) / float64(t.played)
+		u.PointsPerGame = float64(u.Points) / float64(u.Played)
+		u.GoalsForPerGame = float64(u.GoalsFor) / float64(u.Played)
+		u.GoalsAgainstPerGame = float64(u.GoalsAgainst) / float64(u.Played)
+		list = append(list, u)
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Played > list[j].Played })
+	return list
+}
+
+// Lineup is the part of a fixtures/lineups response the analysis reads.
+type Lineup struct {
+	Formation string
+	Coach     Coach
+}
+
+// LineupOf reads the lineup of teamId from a fixtures/lineups response.
+func LineupOf(teamId int, lineups apifootball.Lineups) (Lineup, bool) {
+	for _, l := range lineups.Response {
+		if l.Team.ID == teamId && l.Formation != "" {
+			return Lineup{l.Formation, Coach{l.Coach.ID, l.Coach.Name, l.Coach.Photo}}, true
+		}
+	}
+	return Lineup{}, false
+}
+
+// tenure finds the coach of the team on a date from the coaches' careers,
+// for lineups that name none.
+func tenure(teamId int, coachs []apifootball.Coach, date time.Time) *Coach {
+	day := date.Format("2006-01-02")
+	for _, c := range coachs {
+		for _, job := range c.Career {
+			if job.Team.ID == teamId && job.Start <= day && (job.End == "" || day <= job.End) {
+				return &Coach{c.ID, c.Name, c.Photo}
+			}
+		}
+	}
+	return nil
+}
+
+// Analyse reports the formations of teamId in its finished fixtures, given
+// the lineups by fixture id and the coaches of the team.
+func Analyse(teamId int, fixtures []apifootball.Fixture, lineups map[int]Lineup, coachs []apifootball.Coach) Report {
+	report := Report{Formations: []Usage{}, Changes: []Change{}, Coaches: []CoachSystems{}, Matches: []Match{}}
+	for _, f := range fixtures {
+		switch teamId {
+		case f.Teams.Home.ID:
+			report.Team = Team{teamId, f.Teams.Home.Name, f.Teams.Home.Logo}
+		case f.Teams.Away.ID:
+			report.Team = Team{teamId, f.Teams.Away.Name, f.Teams.Away.Logo}
+		}
+	}
+
+	var all tally
+	byCoach := map[int]*tally{}
+	var coaches []Coach
+	for _, played := range form.Matches(teamId, fixtures) {
+		opponent := played.Opponent
+		m := Match{
+			FixtureID: played.FixtureID,
+			Date:      played.Date,
+			LeagueID:  played.League.ID,
+			Opponent:  Team{opponent.ID, opponent.Name, opponent.Logo},
+			Home:      played.Home,
+			Scored:    played.GoalsFor,
+			Conceded:  played.GoalsAgainst,
+			Result:    played.Result,
+		}
+
+		lineup, ok := lineups[m.FixtureID]
+		if !ok {
+			report.Unknown++
+			continue
+		}
+		m.Formation = lineup.Formation
+		if lineup.Coach.ID != 0 {
+			coach := lineup.Coach
+			m.Coach = &coach
+		} else {
+			m.Coach = tenure(teamId, coachs, m.Date)
+		}
+
+		if n := len(report.Matches); n > 0 && report.Matches[n-1].Formation != m.Formation {
+			report.Changes = append(report.Changes, Change{m.FixtureID, m.Date, report.Matches[n-1].Formation, m.Formation, m.Coach})
+		}
+		report.Matches = append(report.Matches, m)
+		all.add(m)
+		if m.Coach != nil {
+			t, ok := byCoach[m.Coach.ID]
+			if !ok {
+				t = &tally{}
+				byCoach[m.Coach.ID] = t
+				coaches = append(coaches, *m.Coach)
+			}
+			t.add(m)
+		}
+	}
+
+	report.Played = all.played
+	if all.played > 0 {
+		report.Formations = all.list()
+	}
+	for _, coach := range coaches {
+		t := byCoach[coach.ID]
+		formations := t.list()
+		report.Coaches = append(report.Coaches, CoachSystems{
+			Coach:         coach,
+			Played:        t.played,
+			Preferred:     formations[0].Formation,
+			PointsPerGame: float64(t.points) / float64(t.played),
+			Formations:    formations,
+		})
+	}
+	return report
+}
+
+// SeasonOf reads the provider's formation counts from team statistics.
+func SeasonOf(statistics apifootball.Statistics) []Count {
+	counts := []Count{}
+	for _, l := range statistics.Response.Lineups {
+		counts = append(counts, Count{l.Formation, l.Played})
+	}
+	return counts
+}
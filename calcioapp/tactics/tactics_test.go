@@ -0,0 +1,107 @@
+package tactics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+	"github.com/nero-15/calcio-app/internal/fixturetest"
+)
+
+func fixture(id int, day int, home int, away int, homeGoals int, awayGoals int) apifootball.Fixture {
+	return fixturetest.Finished(id, fixturetest.Date(2021, time.September, day), home, away, homeGoals, awayGoals)
+}
+
+func TestAnalyse(t *testing.T) {
+	var coachs apifootball.Coachs
+	json.Unmarshal([]byte(`{"response": [
+		{"id": 1, "name": "First", "career": [{"team": {"id": 1}, "start": "2019-07-01", "end": "2021-09-10"}]},
+		{"id": 2, "name": "Second", "career": [{"team": {"id": 1}, "start": "2021-09-11", "end": null}]}]}`), &coachs)
+	first, second := Coach{ID: 1, Name: "First"}, Coach{ID: 2, Name: "Second"}
+
+	upcoming := fixture(9, 30, 1, 9, 0, 0)
+	upcoming.Fixture.Status.Short = "NS"
+	fixtures := []apifootball.Fixture{
+		fixture(3, 8, 1, 4, 1, 1),
+		fixture(1, 1, 1, 2, 2, 0),
+		fixture(2, 4, 3, 1, 1, 0),
+		fixture(4, 12, 5, 1, 0, 3),
+		fixture(5, 15, 1, 6, 2, 1),
+		fixture(6, 18, 7, 1, 1, 1),
+		upcoming,
+	}
+	lineups := map[int]Lineup{
+		1: {"4-3-3", first},
+		2: {"4-3-3", first},
+		3: {"4-4-2", first},
+		4: {"3-5-2", second},
+		// No coach in the lineup: the careers tell it was the second.
+		5: {"3-5-2", Coach{}},
+		// 6 has no lineup.
+	}
+
+	report := Analyse(1, fixtures, lineups, coachs.Response)
+	if report.Played != 5 || report.Unknown != 1 || len(report.Matches) != 5 {
+		t.Fatalf("played %d, unknown %d, matches %d", report.Played, report.Unknown, len(report.Matches))
+	}
+	if len(report.Formations) != 3 {
+		t.Fatalf("formations = %+v", report.Formations)
+	}
+	// 4-3-3 and 3-5-2 both played twice: the first used comes first.
+	u := report.Formations[0]
+	if u.Formation != "4-3-3" || u.Played != 2 || u.Share != 0.4 || u.Wins != 1 || u.Losses != 1 || u.PointsPerGame != 1.5 || u.GoalsFor != 2 || u.GoalsAgainst != 1 {
+		t.Errorf("4-3-3 = %+v", u)
+	}
+	if u := report.Formations[1]; u.Formation != "3-5-2" || u.Points != 6 || u.GoalsForPerGame != 2.5 {
+		t.Errorf("3-5-2 = %+v", u)
+	}
+
+	if len(report.Changes) != 2 || report.Changes[0].From != "4-3-3" || report.Changes[0].To != "4-4-2" || report.Changes[1].FixtureID != 4 {
+		t.Errorf("changes = %+v", report.Changes)
+	}
+
+	if len(report.Coaches) != 2 {
+		t.Fatalf("coaches = %+v", report.Coaches)
+	}
+	if c := report.Coaches[0]; c.Coach.ID != 1 || c.Played != 3 || c.Preferred != "4-3-3" || len(c.Formations) != 2 {
+		t.Errorf("first coach = %+v", c)
+	}
+	if c := report.Coaches[1]; c.Coach.ID != 2 || c.Played != 2 || c.Preferred != "3-5-2" || c.PointsPerGame != 3 {
+		t.Errorf("second coach = %+v", c)
+	}
+	if m := report.Matches[4]; m.Coach == nil || m.Coach.Name != "Second" {
+		t.Errorf("coach of match 5 = %+v", m.Coach)
+	}
+}
+
+func TestCache(t *testing.T) {
+	server := fake.New(fake.Options{DailyLimit: -1})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	client := apifootball.New("test-token", ts.URL+"/")
+
+	cache := NewCache(time.Hour)
+	report, err := cache.Report(context.Background(), client, 505, 135)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Team.ID != 505 || report.Played == 0 || report.Unknown != 0 || len(report.Coaches) == 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	season := 0
+	for _, count := range report.Season {
+		season += count.Played
+	}
+	if season != report.Played {
+		t.Errorf("provider counts %d matches, lineups %d", season, report.Played)
+	}
+
+	used := server.Used()
+	if _, err := cache.Report(context.Background(), client, 505, 135); err != nil || server.Used() != used {
+		t.Errorf("cached report cost %d requests (%v)", server.Used()-used, err)
+	}
+}
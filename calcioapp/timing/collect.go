@@ -0,0 +1,76 @@
+package timing
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/cache"
+)
+
+// parallel bounds the statistics requests in flight for one league.
+const parallel = 4
+
+// Collect fetches the statistics of every team of a league: one request for
+// the teams and one per team.
+func Collect(ctx context.Context, client *apifootball.APIClient, leagueId int) ([]apifootball.Statistics, error) {
+	client = client.WithContext(ctx)
+	league := strconv.Itoa(leagueId)
+	teams, err := client.GetTeamsByLeagueId(league)
+	if err != nil {
+		return nil, err
+	}
+	if teams.Results == 0 {
+		return nil, fmt.Errorf("timing: league %d has no teams", leagueId)
+	}
+
+	statistics := make([]apifootball.Statistics, len(teams.Response))
+	errs := make([]error, len(teams.Response))
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, team := range teams.Response {
+		wg.Add(1)
+		go func(i int, teamId int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			statistics[i], errs[i] = client.GetStatisticsByLeagueIdAndTeamId(league, strconv.Itoa(teamId))
+		}(i, team.Team.ID)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return statistics, nil
+}
+
+// Cache keeps the analysis of each league for a while: it costs a request
+// per team and only changes once a matchday.
+type Cache struct {
+	leagues *cache.Cache
+}
+
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{leagues: cache.New("timing", ttl)}
+}
+
+// League returns the analysis of a league, collecting it when it is not
+// cached or older than the TTL.
+func (c *Cache) League(ctx context.Context, client *apifootball.APIClient, leagueId int) (League, error) {
+	league, err := c.leagues.Get(ctx, strconv.Itoa(leagueId), func(ctx context.Context) (interface{}, error) {
+		statistics, err := Collect(ctx, client, leagueId)
+		if err != nil {
+			return nil, err
+		}
+		return Analyse(statistics), nil
+	})
+	if err != nil {
+		return League{}, err
+	}
+	return league.(League), nil
+}
@@ -0,0 +1,237 @@
+// Package timing analyses when teams score, concede and are booked, from
+// the 15 minute buckets of the provider's team statistics.
+package timing
+
+import (
+	"sort"
+
+	"github.com/nero-15/calcio-app/apifootball"
+)
+
+// Periods are the provider's buckets, in order.
+var Periods = []string{"0-15", "16-30", "31-45", "46-60", "61-75", "76-90", "91-105", "106-120"}
+
+// late are the periods of a "late" goal: after the 75th minute, stoppage
+// and extra time included.
+var late = []int{5, 6, 7}
+
+type Team struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+// Bucket is one period of a distribution.
+type Bucket struct {
+	Period string `json:"period"`
+	Total  int    `json:"total"`
+	// Share is the part of all events falling in the period, from 0 to 1.
+	Share float64 `json:"share"`
+	// PerMatch is the events in the period per match played.
+	PerMatch float64 `json:"perMatch"`
+}
+
+type Distribution struct {
+	Total   int      `json:"total"`
+	Buckets []Bucket `json:"buckets"`
+}
+
+// Profile is a team's distributions, or the league's average.
+type Profile struct {
+	Team         *Team        `json:"team,omitempty"`
+	Played       int          `json:"played"`
+	GoalsFor     Distribution `json:"goalsFor"`
+	GoalsAgainst Distribution `json:"goalsAgainst"`
+	Yellow       Distribution `json:"yellow"`
+	Red          Distribution `json:"red"`
+}
+
+// counts reads the totals of a Minute. The provider leaves both fields null
+// in empty periods; where only the percentage is known it is applied to
+// total, the number of events in the season.
+func counts(minute apifootball.Minute, total int) [8]float64 {
+	buckets := [8]apifootball.MinuteBucket{
+		minute.Zero15, minute.One630, minute.Three145, minute.Four660,
+		minute.Six175, minute.Seven690, minute.Nine1105, minute.One06120,
+	}
+	var c [8]float64
+	for i, b := range buckets {
+		switch {
+		case b.Total.Valid:
+			c[i] = float64(b.Total.Int)
+		case b.Percentage.Valid:
+			c[i] = b.Percentage.Ratio() * float64(total)
+		}
+	}
+	return c
+}
+
+func distribution(c [8]float64, played int) Distribution {
+	var sum float64
+	for _, n := range c {
+		sum += n
+	}
+	d := Distribution{Total: int(sum + 0.5), Buckets: make([]Bucket, len(Periods))}
+	for i, period := range Periods {
+		d.Buckets[i] = Bucket{Period: period, Total: int(c[i] + 0.5)}
+		if sum > 0 {
+			d.Buckets[i].Share = c[i] / sum
+		}
+		if played > 0 {
+			d.Buckets[i].PerMatch = c[i] / float64(played)
+		}
+	}
+	return d
+}
+
+type raw struct {
+	played                 int
+	goalsFor, goalsAgainst [8]float64
+	yellow, red            [8]float64
+}
+
+func rawOf(statistics apifootball.Statistics) raw {
+	season := statistics.Response.TeamSeason
+	return raw{
+		played:       season.Fixtures.Played.Total,
+		goalsFor:     counts(season.Goals.For.Minute, season.Goals.For.Total.Total),
+		goalsAgainst: counts(season.Goals.Against.Minute, season.Goals.Against.Total.Total),
+		yellow:       counts(season.Cards.Yellow, 0),
+		red:          counts(season.Cards.Red, 0),
+	}
+}
+
+func (r raw) profile(team *Team) Profile {
+	return Profile{
+		Team:         team,
+		Played:       r.played,
+		GoalsFor:     distribution(r.goalsFor, r.played),
+		GoalsAgainst: distribution(r.goalsAgainst, r.played),
+		Yellow:       distribution(r.yellow, r.played),
+		Red:          distribution(r.red, r.played),
+	}
+}
+
+// ProfileOf is the profile of the team of a teams/statistics response.
+func ProfileOf(statistics apifootball.Statistics) Profile {
+	t := statistics.Response.Team
+	return rawOf(statistics).profile(&Team{t.ID, t.Name, t.Logo})
+}
+
+// Ranked is a team's place in a ranking.
+type Ranked struct {
+	Rank  int  `json:"rank"`
+	Team  Team `json:"team"`
+	Total int  `json:"total"`
+	// Share is the part of the team's goals in the ranked periods.
+	Share    float64 `json:"share"`
+	PerMatch float64 `json:"perMatch"`
+}
+
+type League struct {
+	// Average pools the events of every team: its shares are the league's
+	// distribution and its per match values those of an average team.
+	Average Profile `json:"average"`
+	// LateGoals ranks teams by the share of their goals scored after the
+	// 75th minute.
+	LateGoals []Ranked `json:"lateGoals"`
+	// EarlyConcessions ranks teams by the share of the goals they conceded
+	// in the first 15 minutes.
+	EarlyConcessions []Ranked  `json:"earlyConcessions"`
+	Teams            []Profile `json:"teams"`
+}
+
+// Analyse builds the league report from the statistics of its teams.
+func Analyse(statistics []apifootball.Statistics) League {
+	var league League
+	var sum raw
+	for _, s := range statistics {
+		r := rawOf(s)
+		sum.played += r.played
+		for i := range Periods {
+			sum.goalsFor[i] += r.goalsFor[i]
+			sum.goalsAgainst[i] += r.goalsAgainst[i]
+			sum.yellow[i] += r.yellow[i]
+			sum.red[i] += r.red[i]
+		}
+		league.Teams = append(league.Teams, ProfileOf(s))
+	}
+	// Per match values of the pooled profile are per team and match.
+	league.Average = sum.profile(nil)
+	league.LateGoals = rank(league.Teams, func(p Profile) Distribution { return p.GoalsFor }, late)
+	league.EarlyConcessions = rank(league.Teams, func(p Profile) Distribution { return p.GoalsAgainst }, []int{0})
+	sort.Slice(league.Teams, func(i, j int) bool { return league.Teams[i].Team.Name < league.Teams[j].Team.Name })
+	return league
+}
+
+func rank(profiles []Profile, of func(p Profile) Distribution, periods []int) []Ranked {
+	ranking := make([]Ranked, 0, len(profiles))
+	for _, p := range profiles {
+		d := of(p)
+		r := Ranked{Team: *p.Team}
+		for _, i := range periods {
+			r.Total += d.Buckets[i].Total
+			r.Share += d.Buckets[i].Share
+			r.PerMatch += d.Buckets[i].PerMatch
+		}
+		ranking = append(ranking, r)
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if ranking[i].Share != ranking[j].Share {
+			return ranking[i].Share > ranking[j].Share
+		}
+		return ranking[i].Total > ranking[j].Total
+	})
+	for i := range ranking {
+		ranking[i].Rank = i + 1
+	}
+	return ranking
+}
+
+// Difference is a team's bucket against the league's.
+type Difference struct {
+	Period      string  `json:"period"`
+	Share       float64 `json:"share"`
+	LeagueShare float64 `json:"leagueShare"`
+	// Index is Share over LeagueShare: above 1 the team is over-represented
+	// in the period. It is 0 when the league has no event in the period.
+	Index float64 `json:"index"`
+}
+
+type Comparison struct {
+	Team         Profile      `json:"team"`
+	Average      Profile      `json:"average"`
+	GoalsFor     []Difference `json:"goalsFor"`
+	GoalsAgainst []Difference `json:"goalsAgainst"`
+	Yellow       []Difference `json:"yellow"`
+	Red          []Difference `json:"red"`
+}
+
+// Compare sets a team's distributions against the league average.
+func (l League) Compare(teamId int) (Comparison, bool) {
+	for _, p := range l.Teams {
+		if p.Team.ID != teamId {
+			continue
+		}
+		return Comparison{
+			Team:         p,
+			Average:      l.Average,
+			GoalsFor:     differences(p.GoalsFor, l.Average.GoalsFor),
+			GoalsAgainst: differences(p.GoalsAgainst, l.Average.GoalsAgainst),
+			Yellow:       differences(p.Yellow, l.Average.Yellow),
+			Red:          differences(p.Red, l.Average.Red),
+		}, true
+	}
+	return Comparison{}, false
+}
+
+func differences(team Distribution, league Distribution) []Difference {
+	diffs := make([]Difference, len(Periods))
+	for i, period := range Periods {
+		diffs[i] = Difference{Period: period, Share: team.Buckets[i].Share, LeagueShare: league.Buckets[i].Share}
+		if league.Buckets[i].Share > 0 {
+			diffs[i].Index = team.Buckets[i].Share / league.Buckets[i].Share
+		}
+	}
+	return diffs
+}
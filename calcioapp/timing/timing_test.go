@@ -0,0 +1,128 @@
+package timing
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+)
+
+// statistics builds a teams/statistics response whose goals for fall in
+// the given periods, indexed like Periods.
+func statistics(t *testing.T, id int, played int, scored map[int]int, conceded map[int]int) apifootball.Statistics {
+	minute := func(goals map[int]int) map[string]interface{} {
+		m := map[string]interface{}{}
+		for i, period := range Periods {
+			if n, ok := goals[i]; ok {
+				m[period] = map[string]interface{}{"total": n, "percentage": "99.99%"}
+			} else {
+				m[period] = map[string]interface{}{"total": nil, "percentage": nil}
+			}
+		}
+		return m
+	}
+	payload, _ := json.Marshal(map[string]interface{}{
+		"response": map[string]interface{}{
+			"team":     map[string]interface{}{"id": id, "name": string(rune('A' + id))},
+			"fixtures": map[string]interface{}{"played": map[string]int{"total": played}},
+			"goals": map[string]interface{}{
+				"for":     map[string]interface{}{"minute": minute(scored)},
+				"against": map[string]interface{}{"minute": minute(conceded)},
+			},
+		},
+	})
+	var s apifootball.Statistics
+	if err := json.Unmarshal(payload, &s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func near(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestAnalyse(t *testing.T) {
+	league := Analyse([]apifootball.Statistics{
+		statistics(t, 1, 10, map[int]int{0: 2, 5: 6, 6: 2}, map[int]int{0: 1, 3: 1}),
+		statistics(t, 2, 10, map[int]int{1: 4, 5: 1}, map[int]int{0: 3, 4: 2}),
+		statistics(t, 3, 5, map[int]int{2: 3}, map[int]int{5: 4}),
+	})
+
+	a := league.Teams[0]
+	if a.GoalsFor.Total != 10 || !near(a.GoalsFor.Buckets[5].Share, 0.6) || !near(a.GoalsFor.Buckets[5].PerMatch, 0.6) {
+		t.Errorf("team A goals for = %+v", a.GoalsFor)
+	}
+	if average := league.Average.GoalsFor; average.Total != 18 || !near(average.Buckets[5].Share, 7.0/18) || !near(average.Buckets[5].PerMatch, 7.0/25) {
+		t.Errorf("average goals for = %+v", average)
+	}
+
+	if first := league.LateGoals[0]; first.Team.ID != 1 || first.Total != 8 || !near(first.Share, 0.8) {
+		t.Errorf("late goals leader = %+v", first)
+	}
+	if last := league.LateGoals[2]; last.Team.ID != 3 || last.Share != 0 {
+		t.Errorf("late goals last = %+v", last)
+	}
+	if first := league.EarlyConcessions[0]; first.Team.ID != 2 || !near(first.Share, 0.6) {
+		t.Errorf("early concessions leader = %+v", first)
+	}
+
+	comparison, ok := league.Compare(2)
+	if !ok {
+		t.Fatal("team 2 not compared")
+	}
+	if d := comparison.GoalsFor[1]; !near(d.Share, 0.8) || !near(d.LeagueShare, 4.0/18) || !near(d.Index, 0.8/(4.0/18)) {
+		t.Errorf("16-30 difference = %+v", d)
+	}
+	if d := comparison.GoalsFor[7]; d.Index != 0 {
+		t.Errorf("a period without goals has index %f", d.Index)
+	}
+	if _, ok := league.Compare(99); ok {
+		t.Error("unknown team compared")
+	}
+}
+
+func TestPercentageOnly(t *testing.T) {
+	var minute apifootball.Minute
+	json.Unmarshal([]byte(`{"0-15": {"total": null, "percentage": "25.00%"}, "76-90": {"total": null, "percentage": "75.00%"}}`), &minute)
+	c := counts(minute, 8)
+	if c[0] != 2 || c[5] != 6 {
+		t.Errorf("counts = %v", c)
+	}
+}
+
+func TestCache(t *testing.T) {
+	server := fake.New(fake.Options{DailyLimit: -1})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	client := apifootball.New("test-token", ts.URL+"/")
+
+	cache := NewCache(time.Hour)
+	league, err := cache.League(context.Background(), client, 135)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(league.Teams) != 20 || league.Average.GoalsFor.Total == 0 {
+		t.Fatalf("league = %+v", league.Average)
+	}
+	if league.Average.GoalsFor.Total != league.Average.GoalsAgainst.Total {
+		t.Errorf("goals scored %d and conceded %d differ", league.Average.GoalsFor.Total, league.Average.GoalsAgainst.Total)
+	}
+	var share float64
+	for _, bucket := range league.Average.GoalsFor.Buckets {
+		share += bucket.Share
+	}
+	if !near(share, 1) {
+		t.Errorf("shares sum to %f", share)
+	}
+
+	used := server.Used()
+	if _, err := cache.League(context.Background(), client, 135); err != nil || server.Used() != used {
+		t.Errorf("cached league cost %d requests (%v)", server.Used()-used, err)
+	}
+}
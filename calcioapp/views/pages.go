@@ -0,0 +1,170 @@
+package views
+
+import (
+	"sort"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/form"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+// Results and Upcoming are how many fixtures a team page lists.
+const (
+	Results  = 10
+	Upcoming = 5
+)
+
+type League struct {
+	ID      int
+	Name    string
+	Country string
+	Logo    string
+	Season  int
+}
+
+type Team struct {
+	ID   int
+	Name string
+	Logo string
+}
+
+// Group is a table of the league: the whole league or one of its groups.
+type Group struct {
+	Name string
+	Rows []apifootball.StandingsRow
+}
+
+// LeaguePage is the data of league.html.
+type LeaguePage struct {
+	League League
+	Groups []Group
+}
+
+// LeagueOf reads the tables of the provider's standings.
+func LeagueOf(s apifootball.Standings) (LeaguePage, bool) {
+	if len(s.Response) == 0 {
+		return LeaguePage{}, false
+	}
+	l := s.Response[0].League
+	page := LeaguePage{League: League{l.ID, l.Name, l.Country, l.Logo, l.Season}}
+	for _, rows := range l.Standings {
+		if len(rows) == 0 {
+			continue
+		}
+		page.Groups = append(page.Groups, Group{rows[0].Group, rows})
+	}
+	return page, len(page.Groups) > 0
+}
+
+// Round is the fixtures of a round, in order of kick-off.
+type Round struct {
+	Name     string
+	Fixtures []apifootball.Fixture
+}
+
+// FixturesPage is the data of fixtures.html.
+type FixturesPage struct {
+	League League
+	Rounds []Round
+}
+
+// FixturesOf groups the fixtures of a league by round, the rounds in order
+// of their first kick-off.
+func FixturesOf(fixtures []apifootball.Fixture) (FixturesPage, bool) {
+	if len(fixtures) == 0 {
+		return FixturesPage{}, false
+	}
+	sorted := make([]apifootball.Fixture, len(fixtures))
+	copy(sorted, fixtures)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Fixture.Date.Before(sorted[j].Fixture.Date) })
+
+	l := sorted[0].League
+	page := FixturesPage{League: League{l.ID, l.Name, l.Country, l.Logo, l.Season}}
+	rounds := map[string]int{}
+	for _, f := range sorted {
+		i, ok := rounds[f.League.Round]
+		if !ok {
+			i = len(page.Rounds)
+			rounds[f.League.Round] = i
+			page.Rounds = append(page.Rounds, Round{Name: f.League.Round})
+		}
+		page.Rounds[i].Fixtures = append(page.Rounds[i].Fixtures, f)
+	}
+	return page, true
+}
+
+type SquadPlayer struct {
+	ID       int
+	Name     string
+	Age      int
+	Number   int
+	Position string
+	Photo    string
+}
+
+// Position is the players of the squad in one position.
+type Position struct {
+	Name    string
+	Players []SquadPlayer
+}
+
+// TeamPage is the data of team.html.
+type TeamPage struct {
+	Team Team
+	// Form is the last five results, most recent first.
+	Form     string
+	Results  []apifootball.Fixture
+	Upcoming []apifootball.Fixture
+	Squad    []Position
+}
+
+var positions = []string{"Goalkeeper", "Defender", "Midfielder", "Attacker"}
+
+// TeamOf builds the page of a team from its fixtures and squad.
+func TeamOf(teamId int, fixtures []apifootball.Fixture, squads apifootball.Squads) (TeamPage, bool) {
+	var page TeamPage
+	sorted := make([]apifootball.Fixture, len(fixtures))
+	copy(sorted, fixtures)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Fixture.Date.Before(sorted[j].Fixture.Date) })
+	for _, f := range sorted {
+		switch teamId {
+		case f.Teams.Home.ID:
+			page.Team = Team{f.Teams.Home.ID, f.Teams.Home.Name, f.Teams.Home.Logo}
+		case f.Teams.Away.ID:
+			page.Team = Team{f.Teams.Away.ID, f.Teams.Away.Name, f.Teams.Away.Logo}
+		default:
+			continue
+		}
+		if standings.Finished(f) {
+			page.Results = append([]apifootball.Fixture{f}, page.Results...)
+		} else if len(page.Upcoming) < Upcoming {
+			page.Upcoming = append(page.Upcoming, f)
+		}
+	}
+	if len(page.Results) > Results {
+		page.Results = page.Results[:Results]
+	}
+	matches := form.Matches(teamId, fixtures)
+	for i := len(matches) - 1; i >= 0 && len(page.Form) < 5; i-- {
+		page.Form += matches[i].Result
+	}
+
+	byPosition := map[string][]SquadPlayer{}
+	for _, s := range squads.Response {
+		if s.Team.ID != teamId {
+			continue
+		}
+		if page.Team.ID == 0 {
+			page.Team = Team{s.Team.ID, s.Team.Name, s.Team.Logo}
+		}
+		for _, p := range s.Players {
+			byPosition[p.Position] = append(byPosition[p.Position], SquadPlayer{p.ID, p.Name, p.Age, p.Number, p.Position, p.Photo})
+		}
+	}
+	for _, position := range positions {
+		if players, ok := byPosition[position]; ok {
+			page.Squad = append(page.Squad, Position{position, players})
+		}
+	}
+	return page, page.Team.ID != 0
+}
@@ -0,0 +1,154 @@
+// Package views renders the server-side pages from the templates of this
+// directory. The templates use [[ ]] delimiters, as Vue uses {{ }}. Each
+// page defines a title and a content template, executed in the shared
+// layout of layouts/.
+package views
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/standings"
+)
+
+// Set holds a template per page, each parsed with the layouts.
+type Set struct {
+	pages map[string]*template.Template
+}
+
+// Load parses the layouts of dir/layouts and the pages of dir.
+func Load(dir string) (*Set, error) {
+	layouts, err := template.New("").Delims("[[", "]]").Funcs(Funcs).ParseGlob(filepath.Join(dir, "layouts", "*.html"))
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+	set := &Set{pages: map[string]*template.Template{}}
+	for _, file := range files {
+		page, err := layouts.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := page.ParseFiles(file); err != nil {
+			return nil, err
+		}
+		set.pages[filepath.Base(file)] = page
+	}
+	return set, nil
+}
+
+// Must is like template.Must.
+func Must(set *Set, err error) *Set {
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
+
+// Execute renders a page, e.g. league.html, in the layout.
+func (s *Set) Execute(w io.Writer, name string, data interface{}) error {
+	page, ok := s.pages[name]
+	if !ok {
+		return fmt.Errorf("views: no page %s", name)
+	}
+	return page.ExecuteTemplate(w, "base", data)
+}
+
+// Funcs are the helpers the templates can call.
+var Funcs = template.FuncMap{
+	"date":     date,
+	"kickoff":  kickoff,
+	"crest":    crest,
+	"form":     badges,
+	"score":    score,
+	"season":   season,
+	"decimal":  decimal,
+	"nullint":  nullInt,
+	"stat":     statistic,
+	"finished": standings.Finished,
+}
+
+// date is the day of a match, e.g. Sat 18 Sep 2021.
+func date(t time.Time) string {
+	return t.UTC().Format("Mon 2 Jan 2006")
+}
+
+// kickoff is the day and time of a match, e.g. Sat 18 Sep 2021, 18:45 UTC.
+func kickoff(t time.Time) string {
+	return t.UTC().Format("Mon 2 Jan 2006, 15:04 UTC")
+}
+
+// crest is the image of a team's crest or a competition's logo, nothing
+// when the provider has no http(s) image for it.
+func crest(src string, name string) template.HTML {
+	u, err := url.Parse(src)
+	if src == "" || err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return ""
+	}
+	return template.HTML(fmt.Sprintf(`<img class="crest" src="%s" alt="%s" width="24" height="24" loading="lazy">`,
+		html.EscapeString(u.String()), html.EscapeString(name)))
+}
+
+var resultTitles = map[rune]string{'W': "Win", 'D': "Draw", 'L': "Loss"}
+
+// badges renders form such as "WWDLW" as badges, skipping other letters.
+func badges(results string) template.HTML {
+	var b strings.Builder
+	for _, r := range results {
+		title, ok := resultTitles[r]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, `<span class="form form-%c" title="%s">%c</span>`, r+'a'-'A', title, r)
+	}
+	return template.HTML(b.String())
+}
+
+// score is the result of a match, e.g. 2 - 1, or vs before kick-off.
+func score(goals apifootball.Score) string {
+	if !goals.Home.Valid || !goals.Away.Valid {
+		return "vs"
+	}
+	return fmt.Sprintf("%d - %d", goals.Home.Int, goals.Away.Int)
+}
+
+// season names a season by its years, e.g. 2021/22.
+func season(year int) string {
+	return fmt.Sprintf("%d/%02d", year, (year+1)%100)
+}
+
+func decimal(f *float64) string {
+	if f == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
+
+func nullInt(n apifootball.NullInt) string {
+	if !n.Valid {
+		return "-"
+	}
+	return strconv.Itoa(n.Int)
+}
+
+func statistic(v apifootball.StatisticValue) string {
+	if !v.Valid {
+		return "-"
+	}
+	s := strconv.FormatFloat(v.Value, 'f', -1, 64)
+	if v.Percent {
+		s += "%"
+	}
+	return s
+}
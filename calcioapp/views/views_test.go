@@ -0,0 +1,124 @@
+package views
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nero-15/calcio-app/apifootball"
+	"github.com/nero-15/calcio-app/apifootball/fake"
+	"github.com/nero-15/calcio-app/players"
+	"github.com/nero-15/calcio-app/report"
+)
+
+func TestFuncs(t *testing.T) {
+	for _, c := range []struct {
+		got, want string
+	}{
+		{string(crest("https://media.example/teams/505.png", `Inter "FC"`)), `<img class="crest" src="https://media.example/teams/505.png" alt="Inter &#34;FC&#34;" width="24" height="24" loading="lazy">`},
+		{string(crest("javascript:alert(1)", "x")), ""},
+		{string(crest("", "x")), ""},
+		{string(badges("WDx L")), `<span class="form form-w" title="Win">W</span><span class="form form-d" title="Draw">D</span><span class="form form-l" title="Loss">L</span>`},
+		{date(time.Date(2021, 9, 18, 23, 0, 0, 0, time.FixedZone("CEST", -2*3600))), "Sun 19 Sep 2021"},
+		{kickoff(time.Date(2021, 9, 18, 18, 45, 0, 0, time.UTC)), "Sat 18 Sep 2021, 18:45 UTC"},
+		{score(apifootball.Score{Home: apifootball.NullInt{Int: 2, Valid: true}, Away: apifootball.NullInt{Int: 0, Valid: true}}), "2 - 0"},
+		{score(apifootball.Score{}), "vs"},
+		{season(2021), "2021/22"},
+		{season(1999), "1999/00"},
+	} {
+		if c.got != c.want {
+			t.Errorf("got %s, want %s", c.got, c.want)
+		}
+	}
+}
+
+func TestPages(t *testing.T) {
+	set, err := Load(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(fake.New(fake.Options{DailyLimit: -1}))
+	defer ts.Close()
+	client := apifootball.New("test-token", ts.URL+"/")
+
+	render := func(name string, data interface{}, want ...string) {
+		t.Helper()
+		var b bytes.Buffer
+		if err := set.Execute(&b, name, data); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		page := b.String()
+		if !strings.HasPrefix(page, "<!DOCTYPE html>") || strings.Contains(page, "[[") {
+			t.Errorf("%s is not rendered in the layout:\n%s", name, page)
+		}
+		for _, w := range want {
+			if !strings.Contains(page, w) {
+				t.Errorf("%s has no %q:\n%s", name, w, page)
+			}
+		}
+	}
+
+	render("index.html", map[string]interface{}{}, "<title>calcio app</title>", `href="/leagues/135"`)
+
+	standings, err := client.GetStandingsByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	league, ok := LeagueOf(standings)
+	if !ok || len(league.Groups) != 1 || len(league.Groups[0].Rows) != 20 {
+		t.Fatalf("league page = %+v", league)
+	}
+	render("league.html", league, "<title>Serie A 2021/22 table · calcio app</title>", `href="/teams/505"`, `class="form form-`)
+
+	fixtures, err := client.GetFixturesByLeagueId("135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, ok := FixturesOf(fixtures.Response)
+	if !ok || len(page.Rounds) != 38 || len(page.Rounds[0].Fixtures) != 10 {
+		t.Fatalf("%d rounds, first of %d fixtures", len(page.Rounds), len(page.Rounds[0].Fixtures))
+	}
+	render("fixtures.html", page, "<h2>"+page.Rounds[0].Name+"</h2>", `href="/fixtures/`)
+
+	inter, err := client.GetFixturesByTeamId("505")
+	if err != nil {
+		t.Fatal(err)
+	}
+	squads, err := client.GetSquadsByTeamId("505")
+	if err != nil {
+		t.Fatal(err)
+	}
+	team, ok := TeamOf(505, inter.Response, squads)
+	if !ok || team.Team.Name != "Inter" || len(team.Form) != 5 || len(team.Results) != Results || len(team.Squad) != 4 {
+		t.Fatalf("team page = %+v", team)
+	}
+	if len(team.Upcoming) > 0 && team.Upcoming[0].Fixture.Date.Before(team.Results[0].Fixture.Date) {
+		t.Errorf("upcoming %v before the last result %v", team.Upcoming[0].Fixture.Date, team.Results[0].Fixture.Date)
+	}
+	render("team.html", team, "<h2>Results</h2>", "<h3>Goalkeepers</h3>", `href="/players/`)
+
+	played := team.Results[0].Fixture.ID
+	parts, err := report.Fetch(context.Background(), client, played)
+	if err != nil {
+		t.Fatal(err)
+	}
+	render("match.html", report.Build(parts), "<h2>Statistics</h2>", "/api/fixture/"+strconv.Itoa(played)+"/lineups.svg")
+
+	player, err := client.GetPlayersByPlayerId(strconv.Itoa(team.Squad[3].Players[0].ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	season, ok := players.SeasonOf(player)
+	if !ok {
+		t.Fatal("no season for the player")
+	}
+	render("player.html", season, "<h1>"+season.Player.Name+"</h1>", `href="/teams/505"`)
+
+	if err := set.Execute(&bytes.Buffer{}, "missing.html", nil); err == nil {
+		t.Error("rendered a page that does not exist")
+	}
+}